@@ -10,6 +10,5 @@ require (
 	github.com/natefinch/atomic v1.0.1
 	github.com/spf13/pflag v1.0.10
 	github.com/tailscale/hujson v0.0.0-20250605163823-992244df8c5a
+	golang.org/x/sys v0.40.0
 )
-
-require golang.org/x/sys v0.40.0 // indirect
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/calvinalkan/agent-task/internal/ticket"
+
+	flag "github.com/spf13/pflag"
+)
+
+var (
+	errAssigneeRequired = errors.New("assignee is required")
+	errInvalidAssignee  = errors.New("invalid assignee")
+)
+
+// AssignCmd returns the assign command.
+func AssignCmd(cfg *ticket.Config) *Command {
+	fs := flag.NewFlagSet("assign", flag.ContinueOnError)
+	addAtomicFlag(fs)
+
+	return &Command{
+		Flags: fs,
+		Usage: "assign <id>... <user> [flags]",
+		Short: "Set ticket assignee",
+		Long: `Set the assignee of one or more tickets. The last argument is the user.
+
+Only the assignee line of the frontmatter is rewritten; tickets that are
+already assigned to the user are left untouched.
+Use --atomic to roll back the others if any ticket fails (best effort).`,
+		Exec: func(_ context.Context, io *IO, args []string) error {
+			atomicMode, _ := fs.GetBool("atomic")
+
+			return execAssign(io, cfg, args, atomicMode)
+		},
+	}
+}
+
+func execAssign(io *IO, cfg *ticket.Config, args []string, atomicMode bool) error {
+	if len(args) == 0 {
+		return ticket.ErrIDRequired
+	}
+
+	if len(args) < 2 {
+		return errAssigneeRequired
+	}
+
+	ids, assignee := args[:len(args)-1], strings.TrimSpace(args[len(args)-1])
+
+	if assignee == "" {
+		return errAssigneeRequired
+	}
+
+	if strings.ContainsAny(assignee, "\r\n") {
+		return fmt.Errorf("%w: %q", errInvalidAssignee, assignee)
+	}
+
+	return execBulkTransition(io, cfg, ids, atomicMode, bulkTransition{
+		name: "assign",
+		done: "Assigned",
+		apply: func(cfg *ticket.Config, ticketID string) ([]byte, error) {
+			return assignTicket(cfg.TicketDirAbs, ticketID, assignee)
+		},
+	})
+}
+
+func assignTicket(ticketDir, ticketID, assignee string) ([]byte, error) {
+	if !ticket.Exists(ticketDir, ticketID) {
+		return nil, fmt.Errorf("%w: %s", ticket.ErrTicketNotFound, ticketID)
+	}
+
+	var original []byte
+
+	err := ticket.WithTicketLock(ticket.Path(ticketDir, ticketID), func(content []byte) ([]byte, error) {
+		if ticket.GetFieldFromContent(content, "assignee") == assignee {
+			return nil, nil // unchanged, no write
+		}
+
+		original = content
+
+		return ticket.SetFieldInContent(content, "assignee", assignee)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update ticket: %w", err)
+	}
+
+	return original, nil
+}
@@ -0,0 +1,93 @@
+package cli_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/calvinalkan/agent-task/internal/cli"
+)
+
+func Test_Assign_Sets_Assignee_When_Ticket_Has_None(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketID := c.MustRun("create", "Ticket")
+
+	stdout := c.MustRun("assign", ticketID, "alice")
+	cli.AssertContains(t, stdout, "Assigned "+ticketID)
+	cli.AssertContains(t, c.ReadTicket(ticketID), "assignee: alice")
+
+	jsonOut := c.MustRun("ls", "--json")
+	cli.AssertContains(t, jsonOut, `"assignee":"alice"`)
+}
+
+func Test_Assign_Replaces_Assignee_When_Reassigning(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketID := c.MustRun("create", "Ticket", "-a", "alice")
+
+	c.MustRun("assign", ticketID, "bob")
+
+	content := c.ReadTicket(ticketID)
+	cli.AssertContains(t, content, "assignee: bob")
+	cli.AssertNotContains(t, content, "alice")
+}
+
+func Test_Assign_Does_Not_Rewrite_When_Assignee_Unchanged(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketID := c.MustRun("create", "Ticket", "-a", "alice")
+
+	path := filepath.Join(c.TicketDir(), ticketID+".md")
+	past := time.Now().Add(-time.Hour)
+
+	err := os.Chtimes(path, past, past)
+	if err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	c.MustRun("assign", ticketID, "alice")
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+
+	if !info.ModTime().Equal(past) {
+		t.Errorf("ticket was rewritten: mtime=%v, want=%v", info.ModTime(), past)
+	}
+}
+
+func Test_Assign_Updates_All_Tickets_When_Multiple_IDs_Given(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	firstID := c.MustRun("create", "First")
+	secondID := c.MustRun("create", "Second")
+
+	stdout := c.MustRun("assign", firstID, secondID, "carol")
+	cli.AssertContains(t, stdout, "Assigned "+firstID)
+	cli.AssertContains(t, stdout, "Assigned "+secondID)
+	cli.AssertContains(t, c.ReadTicket(firstID), "assignee: carol")
+	cli.AssertContains(t, c.ReadTicket(secondID), "assignee: carol")
+}
+
+func Test_Assign_Returns_Error_When_Args_Missing(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketID := c.MustRun("create", "Ticket")
+
+	stderr := c.MustFail("assign")
+	cli.AssertContains(t, stderr, "ticket ID is required")
+
+	stderr = c.MustFail("assign", ticketID)
+	cli.AssertContains(t, stderr, "assignee is required")
+
+	stderr = c.MustFail("assign", "nonexistent", "alice")
+	cli.AssertContains(t, stderr, "ticket not found")
+}
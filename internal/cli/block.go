@@ -75,6 +75,8 @@ func execBlock(io *IO, cfg *ticket.Config, args []string) error {
 		return fmt.Errorf("blocker cycle detected: %s", formatCyclePath(cycle))
 	}
 
+	var original []byte
+
 	err := ticket.WithTicketLock(path, func(content []byte) ([]byte, error) {
 		blockedBy, readErr := ticket.GetBlockedByFromContent(content)
 		if readErr != nil {
@@ -86,6 +88,7 @@ func execBlock(io *IO, cfg *ticket.Config, args []string) error {
 		}
 
 		blockedBy = append(blockedBy, blockerID)
+		original = content
 
 		return ticket.UpdateBlockedByInContent(content, blockedBy)
 	})
@@ -93,12 +96,17 @@ func execBlock(io *IO, cfg *ticket.Config, args []string) error {
 		return fmt.Errorf("update ticket: %w", err)
 	}
 
-	summary, parseErr := ticket.ParseTicketFrontmatter(path)
+	undoErr := recordUndo(cfg, "block", map[string][]byte{ticketID: original}, nil)
+	if undoErr != nil {
+		return undoErr
+	}
+
+	summary, parseErr := ticket.ParseTicketFrontmatter(path, &cfg.Workflow)
 	if parseErr != nil {
 		return fmt.Errorf("parse frontmatter: %w", parseErr)
 	}
 
-	cacheErr := ticket.UpdateCacheAfterTicketWrite(cfg.TicketDirAbs, ticketID+".md", &summary)
+	cacheErr := ticket.UpdateCacheAfterTicketWrite(cfg.TicketDirAbs, ticketID+".md", &summary, &cfg.Workflow)
 	if cacheErr != nil {
 		return fmt.Errorf("update cache: %w", cacheErr)
 	}
@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/calvinalkan/agent-task/internal/ticket"
+
+	flag "github.com/spf13/pflag"
+)
+
+var errBulkFailed = errors.New("tickets failed")
+
+// bulkTransition describes a status transition that start, close and reopen
+// apply to one or more tickets.
+type bulkTransition struct {
+	// name is the command name, used in the failure summary ("failed to close").
+	name string
+	// done is printed before the ticket ID on success ("Closed <id>").
+	done string
+	// apply validates and writes the transition for a single ticket under its
+	// lock and returns the ticket content from before the write, or nil if the
+	// ticket already matched and nothing was written. It must not touch the
+	// cache; the caller batches cache updates.
+	apply func(cfg *ticket.Config, ticketID string) ([]byte, error)
+}
+
+// bulkFailure records why a single ticket in a bulk transition failed.
+type bulkFailure struct {
+	id  string
+	err error
+}
+
+// addAtomicFlag registers the --atomic flag shared by bulk transition commands.
+func addAtomicFlag(fs *flag.FlagSet) {
+	fs.Bool("atomic", false, "Roll back the tickets already changed if any ticket fails (best effort)")
+}
+
+// execBulkTransition applies a transition to every ticket in args, in order.
+//
+// Each ticket is attempted even if an earlier one failed, so the final report
+// covers all of them. Without --atomic, successful transitions are kept. With
+// --atomic, any failure restores every already-written ticket to its previous
+// content. Kept transitions are recorded as one undo operation, and the cache
+// is updated once for all touched tickets.
+//
+// --atomic is best effort, not a transaction. Tickets are validated against
+// the state left by the ones before them (closing a child lets its parent
+// close later in the same call), so the batch cannot be checked up front;
+// each ticket is written as it passes. A failed restore is reported with the
+// rollback error, and a crash midway keeps the tickets written so far
+// without an undo record.
+//
+// With a single ticket ID the errors are returned unchanged, so the output is
+// identical to the single-ticket form of the command.
+func execBulkTransition(io *IO, cfg *ticket.Config, args []string, atomicMode bool, transition bulkTransition) error {
+	if len(args) == 0 {
+		return ticket.ErrIDRequired
+	}
+
+	originals := make(map[string][]byte, len(args))
+
+	var (
+		applied  []string
+		failures []bulkFailure
+	)
+
+	for _, ticketID := range args {
+		original, err := transition.apply(cfg, ticketID)
+		if err != nil {
+			failures = append(failures, bulkFailure{id: ticketID, err: err})
+
+			continue
+		}
+
+		applied = append(applied, ticketID)
+
+		// Nothing was written, so there is nothing to roll back or undo.
+		if original == nil {
+			continue
+		}
+
+		// Keep the oldest content so a rollback undoes repeated IDs fully.
+		if _, seen := originals[ticketID]; !seen {
+			originals[ticketID] = original
+		}
+	}
+
+	rolledBack := atomicMode && len(failures) > 0 && len(applied) > 0
+
+	var rollbackErr error
+	if rolledBack {
+		rollbackErr = rollbackTickets(cfg.TicketDirAbs, applied, originals)
+	}
+
+	var undoErr error
+	if !rolledBack && len(originals) > 0 {
+		undoErr = recordUndo(cfg, transition.name, originals, nil)
+	}
+
+	cacheErr := updateCacheForTickets(cfg, applied)
+
+	if len(args) == 1 {
+		if len(failures) > 0 {
+			return failures[0].err
+		}
+
+		if undoErr != nil {
+			return undoErr
+		}
+
+		if cacheErr != nil {
+			return cacheErr
+		}
+
+		io.Println(transition.done, args[0])
+
+		return nil
+	}
+
+	if !rolledBack {
+		for _, ticketID := range applied {
+			io.Println(transition.done, ticketID)
+		}
+	}
+
+	for _, failure := range failures {
+		io.ErrPrintln("error:", failure.id+":", failure.err)
+	}
+
+	if rollbackErr != nil {
+		return fmt.Errorf("rollback: %w", rollbackErr)
+	}
+
+	if undoErr != nil {
+		return undoErr
+	}
+
+	if cacheErr != nil {
+		return cacheErr
+	}
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	if atomicMode {
+		return fmt.Errorf("%d of %d %w to %s; no tickets were changed (--atomic)",
+			len(failures), len(args), errBulkFailed, transition.name)
+	}
+
+	return fmt.Errorf("%d of %d %w to %s", len(failures), len(args), errBulkFailed, transition.name)
+}
+
+// rollbackTickets restores applied tickets to their original content,
+// newest first.
+func rollbackTickets(ticketDir string, applied []string, originals map[string][]byte) error {
+	var errs []error
+
+	restored := make(map[string]bool, len(applied))
+
+	for _, ticketID := range slices.Backward(applied) {
+		original, written := originals[ticketID]
+		if !written || restored[ticketID] {
+			continue
+		}
+
+		restored[ticketID] = true
+
+		err := ticket.WithTicketLock(ticket.Path(ticketDir, ticketID), func(_ []byte) ([]byte, error) {
+			return original, nil
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ticketID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// updateCacheForTickets re-reads the given tickets and writes them to the
+// cache in a single update.
+func updateCacheForTickets(cfg *ticket.Config, ticketIDs []string) error {
+	if len(ticketIDs) == 0 {
+		return nil
+	}
+
+	ticketDir := cfg.TicketDirAbs
+
+	summaries := make(map[string]*ticket.Summary, len(ticketIDs))
+
+	for _, ticketID := range ticketIDs {
+		summary, parseErr := ticket.ParseTicketFrontmatter(ticket.Path(ticketDir, ticketID), &cfg.Workflow)
+		if parseErr != nil {
+			return fmt.Errorf("parse frontmatter: %w", parseErr)
+		}
+
+		summaries[ticketID+".md"] = &summary
+	}
+
+	cacheErr := ticket.UpdateCacheAfterTicketWrites(ticketDir, summaries, &cfg.Workflow)
+	if cacheErr != nil {
+		return fmt.Errorf("update cache: %w", cacheErr)
+	}
+
+	return nil
+}
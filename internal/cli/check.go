@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/calvinalkan/agent-task/internal/ticket"
+
+	flag "github.com/spf13/pflag"
+)
+
+var errCheckFailed = errors.New("check failed")
+
+// CheckCmd returns the check command.
+func CheckCmd(cfg *ticket.Config) *Command {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	fs.Bool("strict", false, "Also fail on open tickets blocked by closed tickets")
+
+	return &Command{
+		Flags: fs,
+		Usage: "check [flags]",
+		Short: "Validate ticket dependencies",
+		Long: `Validate the dependency graph across all tickets.
+
+Reports:
+  - cycle            blocked-by links that form a cycle
+  - missing-blocker  blocked-by references to nonexistent tickets
+  - missing-parent   parent references to nonexistent tickets
+  - closed-blocker   tickets that are not closed but still list a closed blocker
+
+Exits non-zero if cycles or missing references are found, so it can gate CI.
+Closed blockers are only reported unless --strict is set.`,
+		Exec: func(_ context.Context, io *IO, _ []string) error {
+			strict, _ := fs.GetBool("strict")
+
+			return execCheck(io, cfg, strict)
+		},
+	}
+}
+
+// checkProblem is a single finding of the check command.
+type checkProblem struct {
+	kind   string
+	detail string
+	// fatal problems make check exit non-zero.
+	fatal bool
+}
+
+func execCheck(io *IO, cfg *ticket.Config, strict bool) error {
+	graph, err := loadDepGraph(io, cfg)
+	if err != nil {
+		return err
+	}
+
+	problems := checkDepGraph(graph, strict)
+
+	failed := 0
+
+	for _, problem := range problems {
+		io.Println(problem.kind+":", problem.detail)
+
+		if problem.fatal {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%w: %d problem(s) found", errCheckFailed, failed)
+	}
+
+	if len(problems) == 0 {
+		io.Println("No problems found")
+	}
+
+	return nil
+}
+
+func checkDepGraph(graph *depGraph, strict bool) []checkProblem {
+	var problems []checkProblem
+
+	for _, cycle := range graph.cycles() {
+		problems = append(problems, checkProblem{kind: "cycle", detail: formatCyclePath(cycle), fatal: true})
+	}
+
+	for _, id := range graph.ids {
+		summary := graph.summaries[id]
+
+		for _, blockerID := range summary.BlockedBy {
+			blocker := graph.summaries[blockerID]
+
+			switch {
+			case blocker == nil:
+				problems = append(problems, checkProblem{
+					kind:   "missing-blocker",
+					detail: id + " blocked by nonexistent " + blockerID,
+					fatal:  true,
+				})
+			case blocker.Status == ticket.StatusClosed && summary.Status != ticket.StatusClosed:
+				problems = append(problems, checkProblem{
+					kind:   "closed-blocker",
+					detail: id + " blocked by closed " + blockerID,
+					fatal:  strict,
+				})
+			}
+		}
+
+		if summary.Parent != "" && graph.summaries[summary.Parent] == nil {
+			problems = append(problems, checkProblem{
+				kind:   "missing-parent",
+				detail: id + " has nonexistent parent " + summary.Parent,
+				fatal:  true,
+			})
+		}
+	}
+
+	return problems
+}
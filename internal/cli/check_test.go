@@ -0,0 +1,84 @@
+package cli_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/calvinalkan/agent-task/internal/cli"
+)
+
+func Test_Check_Reports_No_Problems_When_Graph_Is_Clean(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	blockerID := c.MustRun("create", "Blocker")
+	c.MustRun("create", "Blocked", "--blocked-by", blockerID)
+
+	stdout := c.MustRun("check")
+	cli.AssertContains(t, stdout, "No problems found")
+}
+
+func Test_Check_Fails_When_Blockers_Form_Cycle(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	firstID := c.MustRun("create", "First")
+	secondID := c.MustRun("create", "Second", "--blocked-by", firstID)
+
+	content := c.ReadTicket(firstID)
+	c.WriteTicket(firstID, strings.Replace(content, "blocked-by: []", "blocked-by: ["+secondID+"]", 1))
+	c.MustRun("repair", "--rebuild-cache")
+
+	stdout, stderr, exitCode := c.Run("check")
+
+	if got, want := exitCode, 1; got != want {
+		t.Fatalf("exitCode=%d, want=%d", got, want)
+	}
+
+	cli.AssertContains(t, stdout, "cycle: "+firstID+" -> "+secondID+" -> "+firstID)
+	cli.AssertContains(t, stderr, "check failed: 1 problem(s) found")
+}
+
+func Test_Check_Fails_When_References_Are_Dangling(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketID := c.MustRun("create", "Ticket")
+
+	content := c.ReadTicket(ticketID)
+	content = strings.Replace(content, "blocked-by: []", "blocked-by: [gone123]\nparent: gone456", 1)
+	c.WriteTicket(ticketID, content)
+	c.MustRun("repair", "--rebuild-cache")
+
+	stdout, stderr, exitCode := c.Run("check")
+
+	if got, want := exitCode, 1; got != want {
+		t.Fatalf("exitCode=%d, want=%d, stderr=%s", got, want, stderr)
+	}
+
+	cli.AssertContains(t, stdout, "missing-blocker: "+ticketID+" blocked by nonexistent gone123")
+	cli.AssertContains(t, stdout, "missing-parent: "+ticketID+" has nonexistent parent gone456")
+	cli.AssertContains(t, stderr, "2 problem(s) found")
+}
+
+func Test_Check_Fails_On_Closed_Blocker_Only_When_Strict(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	blockerID := c.MustRun("create", "Blocker")
+	blockedID := c.MustRun("create", "Blocked", "--blocked-by", blockerID)
+	c.MustRun("start", blockerID)
+	c.MustRun("close", blockerID)
+
+	stdout := c.MustRun("check")
+	cli.AssertContains(t, stdout, "closed-blocker: "+blockedID+" blocked by closed "+blockerID)
+	cli.AssertNotContains(t, stdout, "No problems found")
+
+	_, stderr, exitCode := c.Run("check", "--strict")
+
+	if got, want := exitCode, 1; got != want {
+		t.Fatalf("exitCode=%d, want=%d", got, want)
+	}
+
+	cli.AssertContains(t, stderr, "1 problem(s) found")
+}
@@ -12,60 +12,72 @@ import (
 
 // CloseCmd returns the close command.
 func CloseCmd(cfg *ticket.Config) *Command {
+	fs := flag.NewFlagSet("close", flag.ContinueOnError)
+	addAtomicFlag(fs)
+
 	return &Command{
-		Flags: flag.NewFlagSet("close", flag.ContinueOnError),
-		Usage: "close <id>",
+		Flags: fs,
+		Usage: "close <id>... [flags]",
 		Short: "Set status to closed",
 		Long: `Set ticket status to closed.
 
 Requirements:
-  - Ticket must be in_progress
-  - All child tickets must be closed first`,
+  - Ticket must be in_progress (or in a workflow status that allows closed)
+  - All child tickets must be closed first
+
+Multiple IDs are closed in order; failures are reported at the end.
+Use --atomic to roll back the others if any ticket fails (best effort).`,
 		Exec: func(_ context.Context, io *IO, args []string) error {
-			return execClose(io, cfg, args)
+			atomicMode, _ := fs.GetBool("atomic")
+
+			return execBulkTransition(io, cfg, args, atomicMode, bulkTransition{
+				name:  "close",
+				done:  "Closed",
+				apply: closeTicket,
+			})
 		},
 	}
 }
 
-func execClose(io *IO, cfg *ticket.Config, args []string) error {
-	if len(args) == 0 {
-		return ticket.ErrIDRequired
-	}
+func closeTicket(cfg *ticket.Config, ticketID string) ([]byte, error) {
+	ticketDir := cfg.TicketDirAbs
 
-	ticketID := args[0]
-
-	if !ticket.Exists(cfg.TicketDirAbs, ticketID) {
-		return fmt.Errorf("%w: %s", ticket.ErrTicketNotFound, ticketID)
+	if !ticket.Exists(ticketDir, ticketID) {
+		return nil, fmt.Errorf("%w: %s", ticket.ErrTicketNotFound, ticketID)
 	}
 
-	path := ticket.Path(cfg.TicketDirAbs, ticketID)
+	path := ticket.Path(ticketDir, ticketID)
 
 	// Check for open children before closing
-	openChildren, childErr := ticket.FindOpenChildren(cfg.TicketDirAbs, ticketID)
+	openChildren, childErr := ticket.FindOpenChildren(ticketDir, ticketID)
 	if childErr != nil {
-		return fmt.Errorf("checking children: %w", childErr)
+		return nil, fmt.Errorf("checking children: %w", childErr)
 	}
 
 	if len(openChildren) > 0 {
-		return fmt.Errorf("%w: %s", ticket.ErrHasOpenChildren, openChildren[0])
+		return nil, fmt.Errorf("%w: %s", ticket.ErrHasOpenChildren, openChildren[0])
 	}
 
+	var original []byte
+
 	err := ticket.WithTicketLock(path, func(content []byte) ([]byte, error) {
 		status, statusErr := ticket.GetStatusFromContent(content)
 		if statusErr != nil {
 			return nil, fmt.Errorf("reading status: %w", statusErr)
 		}
 
-		if status == ticket.StatusOpen {
-			return nil, ticket.ErrTicketNotStarted
-		}
-
 		if status == ticket.StatusClosed {
 			return nil, ticket.ErrTicketAlreadyClosed
 		}
 
-		if status != ticket.StatusInProgress {
-			return nil, fmt.Errorf("%w (current status: %s)", ticket.ErrTicketNotInProgress, status)
+		if !cfg.Workflow.Allows(status, ticket.StatusClosed) {
+			notAllowed := ticket.ErrTicketNotInProgress
+			if status == ticket.StatusOpen {
+				notAllowed = ticket.ErrTicketNotStarted
+			}
+
+			return nil, fmt.Errorf("%w (current status: %s, allowed next: %s)",
+				notAllowed, status, cfg.Workflow.FormatNext(status))
 		}
 
 		newContent, updateErr := ticket.UpdateStatusInContent(content, ticket.StatusClosed)
@@ -73,25 +85,14 @@ func execClose(io *IO, cfg *ticket.Config, args []string) error {
 			return nil, fmt.Errorf("updating status: %w", updateErr)
 		}
 
+		original = content
 		closedTime := time.Now().UTC().Format(time.RFC3339)
 
 		return ticket.AddFieldToContent(newContent, "closed", closedTime)
 	})
 	if err != nil {
-		return fmt.Errorf("update ticket: %w", err)
-	}
-
-	summary, parseErr := ticket.ParseTicketFrontmatter(path)
-	if parseErr != nil {
-		return fmt.Errorf("parse frontmatter: %w", parseErr)
+		return nil, fmt.Errorf("update ticket: %w", err)
 	}
 
-	cacheErr := ticket.UpdateCacheAfterTicketWrite(cfg.TicketDirAbs, ticketID+".md", &summary)
-	if cacheErr != nil {
-		return fmt.Errorf("update cache: %w", cacheErr)
-	}
-
-	io.Println("Closed", ticketID)
-
-	return nil
+	return original, nil
 }
@@ -230,3 +230,81 @@ func Test_Close_With_No_Children_Succeeds_When_Invoked(t *testing.T) {
 	content := c.ReadTicket(ticketID)
 	cli.AssertContains(t, content, "status: closed")
 }
+
+func Test_Close_Multiple_Tickets_When_Invoked_With_Several_IDs(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	id1 := c.MustRun("create", "First")
+	id2 := c.MustRun("create", "Second")
+	c.MustRun("start", id1, id2)
+
+	stdout := c.MustRun("close", id1, id2)
+	cli.AssertContains(t, stdout, "Closed "+id1)
+	cli.AssertContains(t, stdout, "Closed "+id2)
+
+	cli.AssertContains(t, c.ReadTicket(id1), "status: closed")
+	cli.AssertContains(t, c.ReadTicket(id2), "status: closed")
+}
+
+func Test_Close_Reports_Each_Failure_When_Some_IDs_Fail(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	startedID := c.MustRun("create", "Started")
+	openID := c.MustRun("create", "Still open")
+	c.MustRun("start", startedID)
+
+	stdout, stderr, exitCode := c.Run("close", openID, startedID, "nonexistent")
+
+	if got, want := exitCode, 1; got != want {
+		t.Fatalf("exitCode=%d, want=%d, stderr=%s", got, want, stderr)
+	}
+
+	cli.AssertContains(t, stdout, "Closed "+startedID)
+	cli.AssertNotContains(t, stdout, openID)
+	cli.AssertContains(t, stderr, "error: "+openID+": update ticket: ticket must be started first")
+	cli.AssertContains(t, stderr, "error: nonexistent: ticket not found")
+	cli.AssertContains(t, stderr, "2 of 3 tickets failed to close")
+
+	cli.AssertContains(t, c.ReadTicket(startedID), "status: closed")
+	cli.AssertContains(t, c.ReadTicket(openID), "status: open")
+}
+
+func Test_Close_Atomic_Rolls_Back_All_When_One_ID_Fails(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	startedID := c.MustRun("create", "Started")
+	openID := c.MustRun("create", "Still open")
+	c.MustRun("start", startedID)
+
+	before := c.ReadTicket(startedID)
+
+	stderr := c.MustFail("close", "--atomic", startedID, openID)
+	cli.AssertContains(t, stderr, "error: "+openID+":")
+	cli.AssertContains(t, stderr, "no tickets were changed (--atomic)")
+
+	if got, want := c.ReadTicket(startedID), before; got != want {
+		t.Fatalf("ticket not rolled back\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Cache must agree with the rolled back file.
+	stdout := c.MustRun("ls", "--status", "in_progress")
+	cli.AssertTicketListed(t, stdout, startedID)
+}
+
+func Test_Close_Parent_After_Child_When_Both_Given_In_Order(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	parentID := c.MustRun("create", "Parent")
+	childID := c.MustRun("create", "Child", "--parent", parentID)
+	c.MustRun("start", parentID)
+	c.MustRun("start", childID)
+
+	c.MustRun("close", "--atomic", childID, parentID)
+
+	cli.AssertContains(t, c.ReadTicket(parentID), "status: closed")
+	cli.AssertContains(t, c.ReadTicket(childID), "status: closed")
+}
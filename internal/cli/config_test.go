@@ -99,6 +99,41 @@ func Test_Config_Invalid_JSON_When_Invoked(t *testing.T) {
 	cli.AssertContains(t, stderr, "invalid")
 }
 
+func Test_Config_Rejects_Unknown_Ls_Key_When_Invoked(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	writeFile(t, filepath.Join(c.Dir, ".tk.json"), `{"ls": {"colums": ["id"]}}`)
+
+	stderr := c.MustFail("ls")
+	cli.AssertContains(t, stderr, "invalid config file")
+	cli.AssertContains(t, stderr, `ls: json: unknown field "colums"`)
+}
+
+func Test_Config_Rejects_Invalid_Ls_Values_When_Invoked(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name       string
+		config     string
+		wantStderr string
+	}{
+		{name: "unknown status", config: `{"ls": {"status": "done"}}`, wantStderr: `ls.status: unknown status "done"`},
+		{name: "priority out of range", config: `{"ls": {"priority": 9}}`, wantStderr: "ls.priority must be 1-4"},
+		{name: "ready with status", config: `{"ls": {"ready": true, "status": "open"}}`, wantStderr: "ls.ready and ls.status"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := cli.NewCLI(t)
+			writeFile(t, filepath.Join(c.Dir, ".tk.json"), tt.config)
+
+			stderr := c.MustFail("ls")
+			cli.AssertContains(t, stderr, tt.wantStderr)
+		})
+	}
+}
+
 func Test_Config_Empty_Ticket_Dir_When_Invoked(t *testing.T) {
 	t.Parallel()
 
@@ -137,12 +137,17 @@ func execCreate(io *IO, cfg *ticket.Config, fs *flag.FlagSet, args []string) err
 		return fmt.Errorf("write ticket: %w", writeErr)
 	}
 
-	summary, parseErr := ticket.ParseTicketFrontmatter(ticketPath)
+	undoErr := recordUndo(cfg, "create", nil, []string{ticketID})
+	if undoErr != nil {
+		return undoErr
+	}
+
+	summary, parseErr := ticket.ParseTicketFrontmatter(ticketPath, &cfg.Workflow)
 	if parseErr != nil {
 		return fmt.Errorf("parse frontmatter: %w", parseErr)
 	}
 
-	cacheErr := ticket.UpdateCacheAfterTicketWrite(cfg.TicketDirAbs, ticketID+".md", &summary)
+	cacheErr := ticket.UpdateCacheAfterTicketWrite(cfg.TicketDirAbs, ticketID+".md", &summary, &cfg.Workflow)
 	if cacheErr != nil {
 		return fmt.Errorf("update cache: %w", cacheErr)
 	}
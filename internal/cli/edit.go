@@ -169,6 +169,11 @@ func execEditApply(io *IO, cfg *ticket.Config, env map[string]string, ticketID s
 	// Read original ticket and get frontmatter
 	ticketPath := ticket.Path(cfg.TicketDirAbs, ticketID)
 
+	original, readErr := os.ReadFile(ticketPath)
+	if readErr != nil {
+		return fmt.Errorf("reading ticket: %w", readErr)
+	}
+
 	frontmatter, _, parseErr := parseTicketParts(ticketPath)
 	if parseErr != nil {
 		return fmt.Errorf("parsing ticket: %w", parseErr)
@@ -183,6 +188,11 @@ func execEditApply(io *IO, cfg *ticket.Config, env map[string]string, ticketID s
 		return fmt.Errorf("writing ticket: %w", writeErr)
 	}
 
+	undoErr := recordUndo(cfg, "edit", map[string][]byte{ticketID: original}, nil)
+	if undoErr != nil {
+		return undoErr
+	}
+
 	// Delete temp file
 	_ = os.Remove(tempPath)
 
@@ -199,7 +209,20 @@ func execEditLaunch(ctx context.Context, cfg *ticket.Config, env map[string]stri
 		return resolveErr
 	}
 
-	return runEditor(ctx, editor, path)
+	original, readErr := os.ReadFile(path)
+	if readErr != nil {
+		return fmt.Errorf("reading ticket: %w", readErr)
+	}
+
+	runErr := runEditor(ctx, editor, path)
+
+	// A detached editor saves after we return, so the result can't be journaled.
+	if editorDetaches(editor) {
+		return errors.Join(runErr, recordUntracked(cfg, "edit", ticketID))
+	}
+
+	// The editor may have saved before failing, so journal either way.
+	return errors.Join(runErr, recordUndo(cfg, "edit", map[string][]byte{ticketID: original}, nil))
 }
 
 // parseTicketParts reads a ticket file and returns the frontmatter (including delimiters)
@@ -318,10 +341,16 @@ func resolveEditor(cfg *ticket.Config, env map[string]string) (string, error) {
 	return "", ticket.ErrNoEditorFound
 }
 
+// editorDetaches reports whether editor returns before the user finishes
+// editing, as zed does when opening a new window.
+func editorDetaches(editor string) bool {
+	return filepath.Base(editor) == "zed"
+}
+
 func runEditor(ctx context.Context, editor, path string) error {
 	var cmd *exec.Cmd
 
-	if filepath.Base(editor) == "zed" {
+	if editorDetaches(editor) {
 		cmd = exec.CommandContext(ctx, editor, "-n", path)
 	} else {
 		cmd = exec.CommandContext(ctx, editor, path)
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/calvinalkan/agent-task/internal/ticket"
+
+	flag "github.com/spf13/pflag"
+)
+
+const exportFormatCSV = "csv"
+
+const defaultExportColumns = "id,status,type,priority,title,assignee,created,closed"
+
+var (
+	errInvalidExportFormat = errors.New("invalid format (valid: csv)")
+	errInvalidExportColumn = errors.New("invalid column")
+)
+
+// exportColumns maps column names to the summary value they export.
+// List values are joined with ", ".
+var exportColumns = map[string]func(summary *ticket.Summary) string{
+	"id":         func(s *ticket.Summary) string { return s.ID },
+	"status":     func(s *ticket.Summary) string { return s.Status },
+	"type":       func(s *ticket.Summary) string { return s.Type },
+	"priority":   func(s *ticket.Summary) string { return strconv.Itoa(s.Priority) },
+	"title":      func(s *ticket.Summary) string { return s.Title },
+	"assignee":   func(s *ticket.Summary) string { return s.Assignee },
+	"parent":     func(s *ticket.Summary) string { return s.Parent },
+	"blocked_by": func(s *ticket.Summary) string { return strings.Join(s.BlockedBy, ", ") },
+	"labels":     func(s *ticket.Summary) string { return strings.Join(s.Labels, ", ") },
+	"created":    func(s *ticket.Summary) string { return s.Created },
+	"closed":     func(s *ticket.Summary) string { return s.Closed },
+}
+
+// ExportCmd returns the export command.
+func ExportCmd(cfg *ticket.Config) *Command {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.String("format", exportFormatCSV, "Output format (csv)")
+	fs.String("columns", defaultExportColumns, "Comma-separated columns to export")
+	addListFilterFlags(fs)
+
+	return &Command{
+		Flags: fs,
+		Usage: "export [flags]",
+		Short: "Export tickets as CSV",
+		Long: `Export tickets matching the ls filters as CSV (RFC 4180), with a header row
+and one row per ticket, sorted by ID.
+
+Columns: ` + strings.Join(sortedExportColumns(), ", ") + `
+List columns (blocked_by, labels) are joined with ", ".
+
+Examples:
+  tk export --type bug --status closed > closed-bugs.csv
+  tk export --columns id,title,labels`,
+		Exec: func(_ context.Context, io *IO, _ []string) error {
+			return execExport(io, cfg, fs)
+		},
+	}
+}
+
+func execExport(io *IO, cfg *ticket.Config, fs *flag.FlagSet) error {
+	format, _ := fs.GetString("format")
+	if format != exportFormatCSV {
+		return fmt.Errorf("%w: %s", errInvalidExportFormat, format)
+	}
+
+	columnsFlag, _ := fs.GetString("columns")
+
+	columns, err := parseExportColumns(columnsFlag)
+	if err != nil {
+		return err
+	}
+
+	listOpts, err := parseListFilterFlags(cfg, fs)
+	if err != nil {
+		return err
+	}
+
+	summaries, err := listTickets(io, cfg, &listOpts)
+	if err != nil {
+		return err
+	}
+
+	var builder strings.Builder
+
+	writer := csv.NewWriter(&builder)
+	writer.UseCRLF = true
+
+	writeErr := writer.Write(columns)
+	if writeErr != nil {
+		return fmt.Errorf("write csv: %w", writeErr)
+	}
+
+	record := make([]string, len(columns))
+
+	for _, summary := range summaries {
+		for i, column := range columns {
+			record[i] = exportColumns[column](summary)
+		}
+
+		writeErr = writer.Write(record)
+		if writeErr != nil {
+			return fmt.Errorf("write csv: %w", writeErr)
+		}
+	}
+
+	writer.Flush()
+
+	flushErr := writer.Error()
+	if flushErr != nil {
+		return fmt.Errorf("write csv: %w", flushErr)
+	}
+
+	io.Printf("%s", builder.String())
+
+	return nil
+}
+
+func parseExportColumns(value string) ([]string, error) {
+	var columns []string
+
+	for column := range strings.SplitSeq(value, ",") {
+		column = strings.TrimSpace(column)
+		if column == "" {
+			continue
+		}
+
+		if exportColumns[column] == nil {
+			return nil, fmt.Errorf("%w: %s (valid: %s)", errInvalidExportColumn, column,
+				strings.Join(sortedExportColumns(), ", "))
+		}
+
+		columns = append(columns, column)
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("%w: (empty)", errInvalidExportColumn)
+	}
+
+	return columns, nil
+}
+
+func sortedExportColumns() []string {
+	names := make([]string, 0, len(exportColumns))
+	for name := range exportColumns {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
@@ -0,0 +1,73 @@
+package cli_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/calvinalkan/agent-task/internal/cli"
+)
+
+func Test_Export_Writes_CSV_Header_And_Rows_When_Invoked(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	firstID := c.MustRun("create", "First", "-t", "bug", "-p", "1", "-a", "alice")
+	secondID := c.MustRun("create", "Second")
+
+	stdout := c.MustRun("export", "--columns", "id,type,priority,title,assignee")
+
+	want := "id,type,priority,title,assignee\r\n" +
+		firstID + ",bug,1,First,alice\r\n" +
+		secondID + ",task,2,Second,"
+
+	if stdout != want {
+		t.Fatalf("stdout=%q, want=%q", stdout, want)
+	}
+}
+
+func Test_Export_Quotes_Fields_When_Title_Has_Commas_And_Quotes(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketID := c.MustRun("create", `Fix "save", then load`)
+
+	stdout := c.MustRun("export", "--columns", "id,title")
+
+	cli.AssertContains(t, stdout, ticketID+`,"Fix ""save"", then load"`)
+}
+
+func Test_Export_Applies_Ls_Filters_When_Given(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	bugID := c.MustRun("create", "Bug", "-t", "bug")
+	taskID := c.MustRun("create", "Task")
+
+	c.MustRun("start", bugID)
+	c.MustRun("close", bugID)
+
+	stdout := c.MustRun("export", "--type", "bug", "--status", "closed", "--columns", "id,status,closed")
+
+	lines := strings.Split(stdout, "\r\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected header and 1 row, got %d lines:\n%s", len(lines), stdout)
+	}
+
+	cli.AssertContains(t, lines[1], bugID+",closed,")
+	cli.AssertNotContains(t, stdout, taskID)
+}
+
+func Test_Export_Returns_Error_When_Format_Or_Column_Invalid(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+
+	stderr := c.MustFail("export", "--format", "xlsx")
+	cli.AssertContains(t, stderr, "invalid format (valid: csv): xlsx")
+
+	stderr = c.MustFail("export", "--columns", "id,bogus")
+	cli.AssertContains(t, stderr, "invalid column: bogus")
+
+	stderr = c.MustFail("export", "--columns", ",")
+	cli.AssertContains(t, stderr, "invalid column: (empty)")
+}
@@ -214,7 +214,7 @@ func checkInvariants(t *testing.T, ticketDir string) {
 		}
 	}
 
-	// Invariant: Only .md files and .cache allowed in ticket dir
+	// Invariant: Only .md files, .cache and the lock and journal dirs allowed in ticket dir
 	entries, err := os.ReadDir(ticketDir)
 	if err != nil {
 		t.Errorf("failed to read ticket dir: %v", err)
@@ -227,7 +227,7 @@ func checkInvariants(t *testing.T, ticketDir string) {
 	for _, entry := range entries {
 		name := entry.Name()
 		if entry.IsDir() {
-			if name != ".locks" {
+			if name != ".locks" && name != ".journal" {
 				t.Errorf("unexpected directory in ticket dir: %s", name)
 			}
 
@@ -0,0 +1,420 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/calvinalkan/agent-task/internal/ticket"
+
+	flag "github.com/spf13/pflag"
+)
+
+const (
+	graphFormatDOT     = "dot"
+	graphFormatMermaid = "mermaid"
+)
+
+var errInvalidGraphFormat = errors.New("invalid format (valid: dot, mermaid)")
+
+// GraphCmd returns the graph command.
+func GraphCmd(cfg *ticket.Config) *Command {
+	fs := flag.NewFlagSet("graph", flag.ContinueOnError)
+	fs.String("format", graphFormatDOT, "Output format (dot|mermaid)")
+	fs.String("root", "", "Only show this ticket and its transitive blockers")
+
+	return &Command{
+		Flags: fs,
+		Usage: "graph [flags]",
+		Short: "Print blocked-by dependency graph",
+		Long: `Print the blocked-by dependency graph as Graphviz DOT or Mermaid.
+
+Each edge points from a ticket to a ticket it is blocked by.
+Dependency cycles are highlighted in the graph and reported as warnings.
+Blockers that do not exist are drawn as "missing" nodes.
+
+Examples:
+  tk graph | dot -Tsvg > deps.svg
+  tk graph --format mermaid
+  tk graph --root <id>              # <id> and everything blocking it`,
+		Exec: func(_ context.Context, io *IO, _ []string) error {
+			format, _ := fs.GetString("format")
+			root, _ := fs.GetString("root")
+
+			return execGraph(io, cfg, format, root)
+		},
+	}
+}
+
+func execGraph(io *IO, cfg *ticket.Config, format, root string) error {
+	if format != graphFormatDOT && format != graphFormatMermaid {
+		return fmt.Errorf("%w: %s", errInvalidGraphFormat, format)
+	}
+
+	graph, err := loadDepGraph(io, cfg)
+	if err != nil {
+		return err
+	}
+
+	if root != "" {
+		if graph.summaries[root] == nil {
+			return fmt.Errorf("%w: %s", ticket.ErrTicketNotFound, root)
+		}
+
+		graph = graph.reachableFrom(root)
+	}
+
+	cycles := graph.cycles()
+	for _, cycle := range cycles {
+		io.WarnLLM(
+			"dependency cycle: "+formatCyclePath(cycle),
+			"run 'tk unblock' to remove one of the blockers in the cycle",
+		)
+	}
+
+	if format == graphFormatMermaid {
+		io.Printf("%s", graph.mermaid(cycles))
+	} else {
+		io.Printf("%s", graph.dot(cycles))
+	}
+
+	return nil
+}
+
+// depGraph is the blocked-by graph across tickets.
+// Blockers that don't exist as tickets are kept as edges without a summary.
+type depGraph struct {
+	summaries map[string]*ticket.Summary
+	ids       []string // ticket IDs, sorted
+}
+
+// loadDepGraph lists all tickets and builds the dependency graph.
+// Unparseable tickets are reported via WarnLLM and left out of the graph.
+func loadDepGraph(io *IO, cfg *ticket.Config) (*depGraph, error) {
+	results, err := ticket.ListTickets(cfg.TicketDirAbs, &ticket.ListTicketsOptions{Workflow: &cfg.Workflow}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("list tickets: %w", err)
+	}
+
+	summaries := make(map[string]*ticket.Summary, len(results))
+
+	for _, result := range results {
+		if result.Err != nil {
+			io.WarnLLM(
+				fmt.Sprintf("%s: %v", result.Path, result.Err),
+				"fix the ticket file or delete it if invalid",
+			)
+
+			continue
+		}
+
+		summaries[result.Summary.ID] = result.Summary
+	}
+
+	return newDepGraph(summaries), nil
+}
+
+func newDepGraph(summaries map[string]*ticket.Summary) *depGraph {
+	ids := make([]string, 0, len(summaries))
+	for id := range summaries {
+		ids = append(ids, id)
+	}
+
+	slices.Sort(ids)
+
+	return &depGraph{summaries: summaries, ids: ids}
+}
+
+// blockers returns the blocked-by list of id (nil for missing tickets).
+func (g *depGraph) blockers(id string) []string {
+	summary := g.summaries[id]
+	if summary == nil {
+		return nil
+	}
+
+	return summary.BlockedBy
+}
+
+// missing returns blocker IDs that are referenced but don't exist, sorted.
+func (g *depGraph) missing() []string {
+	var missing []string
+
+	for _, id := range g.ids {
+		for _, blockerID := range g.blockers(id) {
+			if g.summaries[blockerID] == nil && !slices.Contains(missing, blockerID) {
+				missing = append(missing, blockerID)
+			}
+		}
+	}
+
+	slices.Sort(missing)
+
+	return missing
+}
+
+// reachableFrom returns the subgraph of root and its transitive blockers.
+func (g *depGraph) reachableFrom(root string) *depGraph {
+	summaries := make(map[string]*ticket.Summary)
+	stack := []string{root}
+
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		summary := g.summaries[id]
+		if summary == nil || summaries[id] != nil {
+			continue
+		}
+
+		summaries[id] = summary
+		stack = append(stack, summary.BlockedBy...)
+	}
+
+	return newDepGraph(summaries)
+}
+
+// cycles returns one cycle per strongly connected component that contains a
+// cycle, as a path that starts and ends at the component's smallest ID
+// (e.g. [a b c a]). Cycles are sorted by their first ID.
+func (g *depGraph) cycles() [][]string {
+	var cycles [][]string
+
+	for _, component := range g.stronglyConnected() {
+		start := slices.Min(component)
+
+		if len(component) == 1 && !slices.Contains(g.blockers(start), start) {
+			continue
+		}
+
+		path := g.pathWithin(start, start, component, make(map[string]bool))
+		cycles = append(cycles, append([]string{start}, path...))
+	}
+
+	slices.SortFunc(cycles, func(a, b []string) int {
+		return strings.Compare(a[0], b[0])
+	})
+
+	return cycles
+}
+
+// pathWithin finds a blocker path from "from" to "target" that stays inside
+// component. The returned path excludes "from" and ends with "target".
+func (g *depGraph) pathWithin(from, target string, component []string, visited map[string]bool) []string {
+	for _, blockerID := range g.blockers(from) {
+		if !slices.Contains(component, blockerID) {
+			continue
+		}
+
+		if blockerID == target {
+			return []string{target}
+		}
+
+		if visited[blockerID] {
+			continue
+		}
+
+		visited[blockerID] = true
+
+		path := g.pathWithin(blockerID, target, component, visited)
+		if path != nil {
+			return append([]string{blockerID}, path...)
+		}
+	}
+
+	return nil
+}
+
+// stronglyConnected returns the strongly connected components of the graph
+// (Tarjan's algorithm). Missing blockers are ignored.
+func (g *depGraph) stronglyConnected() [][]string {
+	var (
+		index      int
+		stack      []string
+		components [][]string
+	)
+
+	indexes := make(map[string]int, len(g.ids))
+	lowlinks := make(map[string]int, len(g.ids))
+	onStack := make(map[string]bool, len(g.ids))
+
+	var connect func(id string)
+
+	connect = func(id string) {
+		indexes[id] = index
+		lowlinks[id] = index
+		index++
+
+		stack = append(stack, id)
+		onStack[id] = true
+
+		for _, blockerID := range g.blockers(id) {
+			if g.summaries[blockerID] == nil {
+				continue
+			}
+
+			if _, seen := indexes[blockerID]; !seen {
+				connect(blockerID)
+				lowlinks[id] = min(lowlinks[id], lowlinks[blockerID])
+			} else if onStack[blockerID] {
+				lowlinks[id] = min(lowlinks[id], indexes[blockerID])
+			}
+		}
+
+		if lowlinks[id] != indexes[id] {
+			return
+		}
+
+		var component []string
+
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+
+			component = append(component, top)
+
+			if top == id {
+				break
+			}
+		}
+
+		components = append(components, component)
+	}
+
+	for _, id := range g.ids {
+		if _, seen := indexes[id]; !seen {
+			connect(id)
+		}
+	}
+
+	return components
+}
+
+// cycleEdges returns the set of "from\x00to" edges that lie on a cycle.
+func cycleEdges(cycles [][]string) map[string]bool {
+	edges := make(map[string]bool)
+
+	for _, cycle := range cycles {
+		for i := range len(cycle) - 1 {
+			edges[cycle[i]+"\x00"+cycle[i+1]] = true
+		}
+	}
+
+	return edges
+}
+
+func (g *depGraph) dot(cycles [][]string) string {
+	onCycle := cycleEdges(cycles)
+
+	var builder strings.Builder
+
+	builder.WriteString("digraph tickets {\n")
+	builder.WriteString("  rankdir=LR;\n")
+	builder.WriteString("  node [shape=box];\n")
+
+	for _, id := range g.ids {
+		summary := g.summaries[id]
+		fmt.Fprintf(&builder, "  %s [label=%s];\n", dotQuote(id), dotQuote(id+" ["+summary.Status+"]\n"+summary.Title))
+	}
+
+	for _, id := range g.missing() {
+		fmt.Fprintf(&builder, "  %s [label=%s, style=dashed];\n", dotQuote(id), dotQuote(id+" (missing)"))
+	}
+
+	for _, id := range g.ids {
+		for _, blockerID := range g.blockers(id) {
+			attrs := ""
+			if onCycle[id+"\x00"+blockerID] {
+				attrs = " [color=red]"
+			}
+
+			fmt.Fprintf(&builder, "  %s -> %s%s;\n", dotQuote(id), dotQuote(blockerID), attrs)
+		}
+	}
+
+	builder.WriteString("}\n")
+
+	return builder.String()
+}
+
+func (g *depGraph) mermaid(cycles [][]string) string {
+	onCycle := cycleEdges(cycles)
+
+	var builder strings.Builder
+
+	missing := g.missing()
+
+	builder.WriteString("graph LR\n")
+
+	for _, id := range g.ids {
+		summary := g.summaries[id]
+		fmt.Fprintf(&builder, "  %s[%s]\n", mermaidID(id), mermaidQuote(id+" ["+summary.Status+"]: "+summary.Title))
+	}
+
+	for _, id := range missing {
+		fmt.Fprintf(&builder, "  %s[%s]:::missing\n", mermaidID(id), mermaidQuote(id+" (missing)"))
+	}
+
+	var cycleLinks []string
+
+	link := 0
+
+	for _, id := range g.ids {
+		for _, blockerID := range g.blockers(id) {
+			fmt.Fprintf(&builder, "  %s --> %s\n", mermaidID(id), mermaidID(blockerID))
+
+			if onCycle[id+"\x00"+blockerID] {
+				cycleLinks = append(cycleLinks, strconv.Itoa(link))
+			}
+
+			link++
+		}
+	}
+
+	if len(missing) > 0 {
+		builder.WriteString("  classDef missing stroke-dasharray: 5 5\n")
+	}
+
+	if len(cycleLinks) > 0 {
+		fmt.Fprintf(&builder, "  linkStyle %s stroke:red\n", strings.Join(cycleLinks, ","))
+	}
+
+	return builder.String()
+}
+
+func dotQuote(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+	return `"` + replacer.Replace(s) + `"`
+}
+
+// mermaidID maps a ticket ID to a safe Mermaid node identifier.
+//
+// Letters and digits are kept, "_" becomes "__" and any other rune its hex
+// code point between underscores ("-" becomes "_2d_"), so distinct IDs such
+// as "a-b" and "a_b" never share a node.
+func mermaidID(id string) string {
+	var builder strings.Builder
+
+	builder.WriteString("t_")
+
+	for _, r := range id {
+		switch {
+		case (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9'):
+			builder.WriteRune(r)
+		case r == '_':
+			builder.WriteString("__")
+		default:
+			fmt.Fprintf(&builder, "_%x_", r)
+		}
+	}
+
+	return builder.String()
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
@@ -0,0 +1,120 @@
+package cli_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/calvinalkan/agent-task/internal/cli"
+)
+
+func Test_Graph_Prints_DOT_Edges_When_Tickets_Have_Blockers(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	blockerID := c.MustRun("create", "Blocker")
+	blockedID := c.MustRun("create", "Blocked", "--blocked-by", blockerID)
+
+	stdout := c.MustRun("graph")
+
+	cli.AssertContains(t, stdout, "digraph tickets {")
+	cli.AssertContains(t, stdout, `"`+blockedID+`" -> "`+blockerID+`";`)
+	cli.AssertContains(t, stdout, `"`+blockerID+`" [label="`+blockerID+` [open]\nBlocker"];`)
+}
+
+func Test_Graph_Prints_Mermaid_When_Format_Is_Mermaid(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	blockerID := c.MustRun("create", "Blocker")
+	blockedID := c.MustRun("create", "Blocked", "--blocked-by", blockerID)
+
+	stdout := c.MustRun("graph", "--format", "mermaid")
+
+	cli.AssertContains(t, stdout, "graph LR")
+	cli.AssertContains(t, stdout, "t_"+blockedID+" --> t_"+blockerID)
+	cli.AssertContains(t, stdout, "t_"+blockerID+`["`+blockerID+` [open]: Blocker"]`)
+}
+
+func Test_Graph_Returns_Error_When_Format_Is_Invalid(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+
+	stderr := c.MustFail("graph", "--format", "svg")
+	cli.AssertContains(t, stderr, "invalid format")
+}
+
+func Test_Graph_Only_Shows_Transitive_Blockers_When_Root_Given(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	deepID := c.MustRun("create", "Deep")
+	midID := c.MustRun("create", "Mid", "--blocked-by", deepID)
+	rootID := c.MustRun("create", "Root", "--blocked-by", midID)
+	otherID := c.MustRun("create", "Unrelated")
+
+	stdout := c.MustRun("graph", "--root", midID)
+
+	cli.AssertContains(t, stdout, `"`+midID+`" -> "`+deepID+`";`)
+	cli.AssertNotContains(t, stdout, rootID)
+	cli.AssertNotContains(t, stdout, otherID)
+
+	stderr := c.MustFail("graph", "--root", "nonexistent")
+	cli.AssertContains(t, stderr, "ticket not found")
+}
+
+func Test_Graph_Warns_And_Highlights_When_Blockers_Form_Cycle(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	firstID := c.MustRun("create", "First")
+	secondID := c.MustRun("create", "Second", "--blocked-by", firstID)
+
+	// block refuses cycles, so create one by editing the file directly.
+	content := c.ReadTicket(firstID)
+	c.WriteTicket(firstID, strings.Replace(content, "blocked-by: []", "blocked-by: ["+secondID+"]", 1))
+	c.MustRun("repair", "--rebuild-cache")
+
+	stdout, stderr, exitCode := c.Run("graph")
+
+	if got, want := exitCode, 1; got != want {
+		t.Fatalf("exitCode=%d, want=%d", got, want)
+	}
+
+	cli.AssertContains(t, stderr, "dependency cycle: "+firstID+" -> "+secondID+" -> "+firstID)
+	cli.AssertContains(t, stdout, `"`+firstID+`" -> "`+secondID+`" [color=red];`)
+	cli.AssertContains(t, stdout, `"`+secondID+`" -> "`+firstID+`" [color=red];`)
+}
+
+func Test_Graph_Draws_Missing_Node_When_Blocker_Does_Not_Exist(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketID := c.MustRun("create", "Ticket")
+
+	content := c.ReadTicket(ticketID)
+	c.WriteTicket(ticketID, strings.Replace(content, "blocked-by: []", "blocked-by: [gone123]", 1))
+	c.MustRun("repair", "--rebuild-cache")
+
+	stdout := c.MustRun("graph")
+
+	cli.AssertContains(t, stdout, `"gone123" [label="gone123 (missing)", style=dashed];`)
+	cli.AssertContains(t, stdout, `"`+ticketID+`" -> "gone123";`)
+}
+
+func Test_Graph_Keeps_Mermaid_Nodes_Apart_When_IDs_Differ_Only_In_Punctuation(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	c.MustRun("mv", c.MustRun("create", "Dash"), "a-b")
+	c.MustRun("mv", c.MustRun("create", "Underscore"), "a_b")
+	c.MustRun("mv", c.MustRun("create", "Blocked"), "c")
+	c.MustRun("block", "c", "a-b")
+
+	stdout := c.MustRun("graph", "--format", "mermaid")
+
+	cli.AssertContains(t, stdout, `t_a_2d_b["a-b [open]: Dash"]`)
+	cli.AssertContains(t, stdout, `t_a__b["a_b [open]: Underscore"]`)
+	cli.AssertContains(t, stdout, "t_c --> t_a_2d_b")
+	cli.AssertNotContains(t, stdout, "t_c --> t_a__b")
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/calvinalkan/agent-task/internal/ticket"
+
+	flag "github.com/spf13/pflag"
+)
+
+var (
+	errLabelActionRequired = errors.New("label action required (add|remove)")
+	errLabelRequired       = errors.New("label is required")
+	errInvalidLabel        = errors.New("invalid label (no whitespace, commas or brackets)")
+	errAlreadyHasLabel     = errors.New("ticket already has label")
+	errDoesNotHaveLabel    = errors.New("ticket does not have label")
+)
+
+// LabelCmd returns the label command.
+func LabelCmd(cfg *ticket.Config) *Command {
+	fs := flag.NewFlagSet("label", flag.ContinueOnError)
+	addAtomicFlag(fs)
+
+	return &Command{
+		Flags: fs,
+		Usage: "label <add|remove> <id>... <label> [flags]",
+		Short: "Add or remove a ticket label",
+		Long: `Add a label to, or remove a label from, one or more tickets.
+The last argument is the label.
+
+Only the labels line of the frontmatter is rewritten. Removing the last
+label removes the field.
+Use --atomic to roll back the others if any ticket fails (best effort).
+
+Examples:
+  tk label add <id> backend
+  tk label remove <id1> <id2> needs-triage`,
+		Exec: func(_ context.Context, io *IO, args []string) error {
+			atomicMode, _ := fs.GetBool("atomic")
+
+			return execLabel(io, cfg, args, atomicMode)
+		},
+	}
+}
+
+func execLabel(io *IO, cfg *ticket.Config, args []string, atomicMode bool) error {
+	if len(args) == 0 {
+		return errLabelActionRequired
+	}
+
+	action := args[0]
+	if action != "add" && action != "remove" {
+		return fmt.Errorf("%w: %s", errLabelActionRequired, action)
+	}
+
+	args = args[1:]
+
+	if len(args) == 0 {
+		return ticket.ErrIDRequired
+	}
+
+	if len(args) < 2 {
+		return errLabelRequired
+	}
+
+	ids, label := args[:len(args)-1], args[len(args)-1]
+
+	if !ticket.IsValidLabel(label) {
+		return fmt.Errorf("%w: %q", errInvalidLabel, label)
+	}
+
+	transition := bulkTransition{
+		name: "label",
+		done: "Labeled",
+		apply: func(cfg *ticket.Config, ticketID string) ([]byte, error) {
+			return updateTicketLabels(cfg.TicketDirAbs, ticketID, func(labels []string) ([]string, error) {
+				if slices.Contains(labels, label) {
+					return nil, fmt.Errorf("%w: %s", errAlreadyHasLabel, label)
+				}
+
+				return append(labels, label), nil
+			})
+		},
+	}
+
+	if action == "remove" {
+		transition.name = "unlabel"
+		transition.done = "Unlabeled"
+		transition.apply = func(cfg *ticket.Config, ticketID string) ([]byte, error) {
+			return updateTicketLabels(cfg.TicketDirAbs, ticketID, func(labels []string) ([]string, error) {
+				idx := slices.Index(labels, label)
+				if idx == -1 {
+					return nil, fmt.Errorf("%w: %s", errDoesNotHaveLabel, label)
+				}
+
+				return slices.Delete(labels, idx, idx+1), nil
+			})
+		}
+	}
+
+	return execBulkTransition(io, cfg, ids, atomicMode, transition)
+}
+
+// updateTicketLabels rewrites the labels of a ticket under its lock and
+// returns the content from before the write.
+func updateTicketLabels(ticketDir, ticketID string, update func(labels []string) ([]string, error)) ([]byte, error) {
+	if !ticket.Exists(ticketDir, ticketID) {
+		return nil, fmt.Errorf("%w: %s", ticket.ErrTicketNotFound, ticketID)
+	}
+
+	var original []byte
+
+	err := ticket.WithTicketLock(ticket.Path(ticketDir, ticketID), func(content []byte) ([]byte, error) {
+		labels, readErr := ticket.GetLabelsFromContent(content)
+		if readErr != nil {
+			return nil, fmt.Errorf("reading labels: %w", readErr)
+		}
+
+		labels, updateErr := update(labels)
+		if updateErr != nil {
+			return nil, updateErr
+		}
+
+		original = content
+
+		return ticket.UpdateLabelsInContent(content, labels)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update ticket: %w", err)
+	}
+
+	return original, nil
+}
@@ -0,0 +1,81 @@
+package cli_test
+
+import (
+	"testing"
+
+	"github.com/calvinalkan/agent-task/internal/cli"
+)
+
+func Test_Label_Adds_And_Removes_Labels_When_Invoked(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketID := c.MustRun("create", "Ticket")
+
+	stdout := c.MustRun("label", "add", ticketID, "backend")
+	cli.AssertContains(t, stdout, "Labeled "+ticketID)
+
+	c.MustRun("label", "add", ticketID, "urgent")
+	cli.AssertContains(t, c.ReadTicket(ticketID), "labels: [backend, urgent]")
+
+	jsonOut := c.MustRun("ls", "--json")
+	cli.AssertContains(t, jsonOut, `"labels":["backend","urgent"]`)
+
+	stdout = c.MustRun("label", "remove", ticketID, "backend")
+	cli.AssertContains(t, stdout, "Unlabeled "+ticketID)
+	cli.AssertContains(t, c.ReadTicket(ticketID), "labels: [urgent]")
+
+	c.MustRun("label", "remove", ticketID, "urgent")
+	cli.AssertNotContains(t, c.ReadTicket(ticketID), "labels:")
+}
+
+func Test_Label_Returns_Error_When_Label_Already_Present_Or_Missing(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketID := c.MustRun("create", "Ticket")
+	c.MustRun("label", "add", ticketID, "backend")
+
+	stderr := c.MustFail("label", "add", ticketID, "backend")
+	cli.AssertContains(t, stderr, "ticket already has label: backend")
+
+	stderr = c.MustFail("label", "remove", ticketID, "frontend")
+	cli.AssertContains(t, stderr, "ticket does not have label: frontend")
+}
+
+func Test_Label_Returns_Error_When_Args_Invalid(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketID := c.MustRun("create", "Ticket")
+
+	stderr := c.MustFail("label")
+	cli.AssertContains(t, stderr, "label action required")
+
+	stderr = c.MustFail("label", "rename", ticketID, "x")
+	cli.AssertContains(t, stderr, "label action required (add|remove): rename")
+
+	stderr = c.MustFail("label", "add", ticketID)
+	cli.AssertContains(t, stderr, "label is required")
+
+	stderr = c.MustFail("label", "add", ticketID, "has space")
+	cli.AssertContains(t, stderr, "invalid label")
+}
+
+func Test_Label_Rolls_Back_All_When_Atomic_And_One_Fails(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	firstID := c.MustRun("create", "First")
+	secondID := c.MustRun("create", "Second")
+	c.MustRun("label", "add", secondID, "backend")
+
+	_, stderr, exitCode := c.Run("label", "add", "--atomic", firstID, secondID, "backend")
+
+	if got, want := exitCode, 1; got != want {
+		t.Fatalf("exitCode=%d, want=%d", got, want)
+	}
+
+	cli.AssertContains(t, stderr, "1 of 2 tickets failed to label; no tickets were changed (--atomic)")
+	cli.AssertNotContains(t, c.ReadTicket(firstID), "labels:")
+}
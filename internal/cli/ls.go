@@ -1,11 +1,15 @@
 package cli
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/calvinalkan/agent-task/internal/ticket"
 
@@ -17,21 +21,28 @@ const defaultLimit = 100
 // LsCmd returns the ls command.
 func LsCmd(cfg *ticket.Config) *Command {
 	fs := flag.NewFlagSet("ls", flag.ContinueOnError)
-	fs.String("status", "", "Filter by status (open|in_progress|closed)")
-	fs.Int("priority", 0, "Filter by priority (1-4)")
-	fs.String("type", "", "Filter by type (bug|feature|task|epic|chore)")
+	addListFilterFlags(fs)
 	fs.Int("limit", defaultLimit, "Maximum tickets to show")
 	fs.Int("offset", 0, "Skip first N tickets")
-	fs.String("parent", "", "Filter by parent ticket ID")
-	fs.Bool("roots", false, "Show only tickets without a parent")
+	fs.Bool("ready", false, "Show only ready tickets (open, blockers closed, parent started)")
 	fs.Bool("json", false, "Output as JSON array")
+	fs.String("columns", "", "Comma-separated `columns` to print ("+strings.Join(lsColumnNames, ",")+")")
+	fs.String("sort", "id", "Sort by `key` ("+strings.Join(lsSortKeys, "|")+"), prefix with - to reverse")
 
 	return &Command{
 		Flags: fs,
 		Usage: "ls [flags]",
 		Short: "List tickets",
-		Long:  "List all tickets. Output sorted by ID (oldest first).",
+		Long: `List all tickets. Output sorted by ID (oldest first).
+
+Defaults for the filter, limit, columns and sort flags can be set in the
+"ls" section of the config file; flags given on the command line win.`,
 		Exec: func(_ context.Context, io *IO, _ []string) error {
+			err := applyLsDefaults(fs, cfg.Ls)
+			if err != nil {
+				return err
+			}
+
 			jsonOutput, _ := fs.GetBool("json")
 
 			return execLs(io, cfg, fs, jsonOutput)
@@ -39,31 +50,107 @@ func LsCmd(cfg *ticket.Config) *Command {
 	}
 }
 
-var errConflictingFlags = errors.New("--parent and --roots cannot be used together")
+// applyLsDefaults sets each flag that was not given on the command line to
+// its value from the config. A default is also skipped when a conflicting
+// flag was given (--status or --ready, --roots or --parent), so explicit
+// flags never fail because of a configured default.
+func applyLsDefaults(fs *flag.FlagSet, defaults *ticket.LsDefaults) error {
+	if defaults == nil {
+		return nil
+	}
 
-func execLs(io *IO, cfg *ticket.Config, fs *flag.FlagSet, jsonOutput bool) error {
+	values := []struct {
+		flag      string
+		value     string
+		set       bool
+		conflicts string
+	}{
+		{"status", defaults.Status, defaults.Status != "", "ready"},
+		{"priority", strconv.Itoa(defaults.Priority), defaults.Priority != 0, ""},
+		{"type", defaults.Type, defaults.Type != "", ""},
+		{"roots", "true", defaults.Roots, "parent"},
+		{"ready", "true", defaults.Ready, "status"},
+		{"limit", strconv.Itoa(defaults.Limit), defaults.Limit != 0, ""},
+		{"columns", strings.Join(defaults.Columns, ","), len(defaults.Columns) > 0, ""},
+		{"sort", defaults.Sort, defaults.Sort != "", ""},
+	}
+
+	for _, v := range values {
+		if !v.set || fs.Changed(v.flag) || (v.conflicts != "" && fs.Changed(v.conflicts)) {
+			continue
+		}
+
+		err := fs.Set(v.flag, v.value)
+		if err != nil {
+			return fmt.Errorf("config ls.%s: %w", v.flag, err)
+		}
+	}
+
+	return nil
+}
+
+var (
+	errConflictingFlags = errors.New("--parent and --roots cannot be used together")
+	errReadyWithStatus  = errors.New("--ready and --status cannot be used together")
+)
+
+// addListFilterFlags registers the ticket filter flags shared by ls and the
+// commands that select tickets the same way.
+func addListFilterFlags(fs *flag.FlagSet) {
+	fs.String("status", "", "Filter by status (open|in_progress|closed or a workflow status)")
+	fs.Int("priority", 0, "Filter by priority (1-4)")
+	fs.String("type", "", "Filter by type (bug|feature|task|epic|chore)")
+	fs.String("parent", "", "Filter by parent ticket ID")
+	fs.Bool("roots", false, "Show only tickets without a parent")
+}
+
+// parseListFilterFlags validates the flags registered by addListFilterFlags
+// and returns them as list options (without limit and offset).
+func parseListFilterFlags(cfg *ticket.Config, fs *flag.FlagSet) (ticket.ListTicketsOptions, error) {
 	status, _ := fs.GetString("status")
 	if fs.Changed("status") {
-		err := validateStatusFlag(status)
+		err := validateStatusFlag(&cfg.Workflow, status)
 		if err != nil {
-			return err
+			return ticket.ListTicketsOptions{}, err
 		}
 	}
 
 	priority, _ := fs.GetInt("priority")
 	if fs.Changed("priority") {
 		if priority < 1 || priority > 4 {
-			return errors.New("--priority must be 1-4")
+			return ticket.ListTicketsOptions{}, errors.New("--priority must be 1-4")
 		}
 	}
 
 	ticketType, _ := fs.GetString("type")
 	if fs.Changed("type") {
 		if !ticket.IsValidTicketType(ticketType) {
-			return fmt.Errorf("invalid type: %s", ticketType)
+			return ticket.ListTicketsOptions{}, fmt.Errorf("invalid type: %s", ticketType)
 		}
 	}
 
+	parentFilter, _ := fs.GetString("parent")
+	rootsOnly, _ := fs.GetBool("roots")
+
+	if parentFilter != "" && rootsOnly {
+		return ticket.ListTicketsOptions{}, errConflictingFlags
+	}
+
+	return ticket.ListTicketsOptions{
+		Status:    status,
+		Priority:  priority,
+		Type:      ticketType,
+		Parent:    parentFilter,
+		RootsOnly: rootsOnly,
+	}, nil
+}
+
+func execLs(io *IO, cfg *ticket.Config, fs *flag.FlagSet, jsonOutput bool) error {
+	listOpts, err := parseListFilterFlags(cfg, fs)
+	if err != nil {
+		return err
+	}
+
 	limit, _ := fs.GetInt("limit")
 	if limit < 0 {
 		return errors.New("--limit must be non-negative")
@@ -74,26 +161,75 @@ func execLs(io *IO, cfg *ticket.Config, fs *flag.FlagSet, jsonOutput bool) error
 		return errors.New("--offset must be non-negative")
 	}
 
-	parentFilter, _ := fs.GetString("parent")
-	rootsOnly, _ := fs.GetBool("roots")
+	readyOnly, _ := fs.GetBool("ready")
+	if readyOnly && fs.Changed("status") {
+		return errReadyWithStatus
+	}
 
-	if parentFilter != "" && rootsOnly {
-		return errConflictingFlags
+	columns, err := parseLsColumns(fs)
+	if err != nil {
+		return err
 	}
 
-	listOpts := ticket.ListTicketsOptions{
-		Status:    status,
-		Priority:  priority,
-		Type:      ticketType,
-		Parent:    parentFilter,
-		RootsOnly: rootsOnly,
-		Limit:     limit,
-		Offset:    offset,
+	sortFlag, _ := fs.GetString("sort")
+
+	sortKey, descending, err := parseLsSort(sortFlag)
+	if err != nil {
+		return err
 	}
 
-	results, err := ticket.ListTickets(cfg.TicketDirAbs, &listOpts, nil)
+	// ListTickets pages in ID order; any other order is paged after sorting.
+	customSort := sortKey != "id" || descending
+	if !customSort {
+		listOpts.Limit = limit
+		listOpts.Offset = offset
+	}
+
+	var valid []*ticket.Summary
+
+	if readyOnly {
+		valid, err = listReadyTickets(io, cfg, &listOpts)
+	} else {
+		valid, err = listTickets(io, cfg, &listOpts)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if customSort {
+		sortSummaries(valid, sortKey, descending)
+
+		valid, err = pageSummaries(valid, offset, limit)
+		if err != nil {
+			return err
+		}
+	}
+
+	if jsonOutput {
+		return outputLsJSON(io, valid)
+	}
+
+	if len(columns) > 0 {
+		io.Printf("%s", formatTicketColumns(valid, columns))
+
+		return nil
+	}
+
+	for _, summary := range valid {
+		io.Println(formatTicketLine(summary))
+	}
+
+	return nil
+}
+
+func listTickets(io *IO, cfg *ticket.Config, listOpts *ticket.ListTicketsOptions) ([]*ticket.Summary, error) {
+	opts := *listOpts
+	opts.Workflow = &cfg.Workflow
+
+	results, err := ticket.ListTickets(cfg.TicketDirAbs, &opts, nil)
 	if err != nil {
-		return fmt.Errorf("list tickets: %w", err)
+		return nil, fmt.Errorf("list tickets: %w", err)
 	}
 
 	var valid []*ticket.Summary
@@ -111,15 +247,82 @@ func execLs(io *IO, cfg *ticket.Config, fs *flag.FlagSet, jsonOutput bool) error
 		valid = append(valid, result.Summary)
 	}
 
-	if jsonOutput {
-		return outputLsJSON(io, valid)
+	return valid, nil
+}
+
+// listReadyTickets returns ready tickets matching listOpts.
+//
+// Readiness depends on other tickets (blockers, ancestors), so all summaries
+// are loaded from the cache and joined in memory; no ticket file is read.
+// Filters, offset and limit are applied after the readiness check.
+func listReadyTickets(io *IO, cfg *ticket.Config, listOpts *ticket.ListTicketsOptions) ([]*ticket.Summary, error) {
+	all, err := listTickets(io, cfg, &ticket.ListTicketsOptions{Limit: 0})
+	if err != nil {
+		return nil, err
 	}
 
-	for _, summary := range valid {
-		io.Println(formatTicketLine(summary))
+	summaryMap := make(map[string]*ticket.Summary, len(all))
+	for _, summary := range all {
+		summaryMap[summary.ID] = summary
 	}
 
-	return nil
+	var (
+		ready   []*ticket.Summary
+		matches int
+	)
+
+	for _, summary := range all {
+		if summary.Status != ticket.StatusOpen || !lsSummaryMatches(summary, listOpts) {
+			continue
+		}
+
+		isReady, warnings := checkTicketReady(summary, summaryMap)
+		for _, w := range warnings {
+			io.WarnLLM(w.issue, w.action)
+		}
+
+		if !isReady {
+			continue
+		}
+
+		matches++
+
+		if matches <= listOpts.Offset {
+			continue
+		}
+
+		if listOpts.Limit > 0 && len(ready) >= listOpts.Limit {
+			continue
+		}
+
+		ready = append(ready, summary)
+	}
+
+	if listOpts.Offset > 0 && matches <= listOpts.Offset {
+		return nil, fmt.Errorf("list tickets: %w", ticket.ErrOffsetOutOfBounds)
+	}
+
+	return ready, nil
+}
+
+func lsSummaryMatches(summary *ticket.Summary, listOpts *ticket.ListTicketsOptions) bool {
+	if listOpts.Priority != 0 && summary.Priority != listOpts.Priority {
+		return false
+	}
+
+	if listOpts.Type != "" && summary.Type != listOpts.Type {
+		return false
+	}
+
+	if listOpts.Parent != "" && summary.Parent != listOpts.Parent {
+		return false
+	}
+
+	if listOpts.RootsOnly && summary.Parent != "" {
+		return false
+	}
+
+	return true
 }
 
 // lsTicketJSON is the JSON representation of a ticket in ls output.
@@ -131,6 +334,8 @@ type lsTicketJSON struct {
 	Title     string   `json:"title"`
 	Parent    string   `json:"parent,omitempty"`
 	BlockedBy []string `json:"blocked_by"`
+	Assignee  string   `json:"assignee,omitempty"`
+	Labels    []string `json:"labels,omitempty"`
 	Created   string   `json:"created"`
 	Closed    string   `json:"closed,omitempty"`
 }
@@ -152,6 +357,8 @@ func outputLsJSON(io *IO, summaries []*ticket.Summary) error {
 			Title:     summary.Title,
 			Parent:    summary.Parent,
 			BlockedBy: blockedBy,
+			Assignee:  summary.Assignee,
+			Labels:    summary.Labels,
 			Created:   summary.Created,
 			Closed:    summary.Closed,
 		})
@@ -169,22 +376,18 @@ func outputLsJSON(io *IO, summaries []*ticket.Summary) error {
 
 var errInvalidStatus = errors.New("invalid status")
 
-func validateStatusFlag(status string) error {
+func validateStatusFlag(workflow *ticket.Workflow, status string) error {
 	if status == "" {
 		return fmt.Errorf("%w: (empty)", errInvalidStatus)
 	}
 
-	if !isValidStatus(status) {
+	if !workflow.HasStatus(status) {
 		return fmt.Errorf("%w: %s", errInvalidStatus, status)
 	}
 
 	return nil
 }
 
-func isValidStatus(status string) bool {
-	return status == ticket.StatusOpen || status == ticket.StatusInProgress || status == ticket.StatusClosed
-}
-
 func formatTicketLine(summary *ticket.Summary) string {
 	var builder strings.Builder
 
@@ -208,3 +411,139 @@ func formatTicketLine(summary *ticket.Summary) string {
 
 	return builder.String()
 }
+
+// lsColumnNames lists the columns accepted by --columns, in display order
+// of the default line format.
+var lsColumnNames = []string{
+	"id", "status", "priority", "type", "title", "assignee", "parent", "blocked-by", "labels", "created",
+}
+
+// lsSortKeys lists the keys accepted by --sort.
+var lsSortKeys = []string{"id", "priority", "status", "type", "created", "title"}
+
+var (
+	errUnknownColumn  = errors.New("unknown column")
+	errUnknownSortKey = errors.New("unknown sort key")
+)
+
+func parseLsColumns(fs *flag.FlagSet) ([]string, error) {
+	value, _ := fs.GetString("columns")
+	if value == "" {
+		return nil, nil
+	}
+
+	columns := strings.Split(value, ",")
+	for i, column := range columns {
+		column = strings.TrimSpace(column)
+		if !slices.Contains(lsColumnNames, column) {
+			return nil, fmt.Errorf("--columns: %w: %q (valid: %s)", errUnknownColumn, column, strings.Join(lsColumnNames, ", "))
+		}
+
+		columns[i] = column
+	}
+
+	return columns, nil
+}
+
+func parseLsSort(value string) (string, bool, error) {
+	key, descending := strings.CutPrefix(value, "-")
+	if !slices.Contains(lsSortKeys, key) {
+		return "", false, fmt.Errorf("--sort: %w: %q (valid: %s)", errUnknownSortKey, value, strings.Join(lsSortKeys, ", "))
+	}
+
+	return key, descending, nil
+}
+
+// sortSummaries sorts by key, breaking ties by ID (always ascending).
+func sortSummaries(summaries []*ticket.Summary, key string, descending bool) {
+	slices.SortStableFunc(summaries, func(a, b *ticket.Summary) int {
+		var c int
+
+		switch key {
+		case "priority":
+			c = cmp.Compare(a.Priority, b.Priority)
+		case "status":
+			c = strings.Compare(a.Status, b.Status)
+		case "type":
+			c = strings.Compare(a.Type, b.Type)
+		case "created":
+			c = strings.Compare(a.Created, b.Created)
+		case "title":
+			c = strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+		default:
+			c = strings.Compare(a.ID, b.ID)
+		}
+
+		if descending {
+			c = -c
+		}
+
+		if c == 0 {
+			c = strings.Compare(a.ID, b.ID)
+		}
+
+		return c
+	})
+}
+
+// pageSummaries applies offset and limit the way [ticket.ListTickets] does.
+func pageSummaries(summaries []*ticket.Summary, offset, limit int) ([]*ticket.Summary, error) {
+	if offset > 0 && offset >= len(summaries) {
+		return nil, fmt.Errorf("list tickets: %w", ticket.ErrOffsetOutOfBounds)
+	}
+
+	summaries = summaries[offset:]
+	if limit > 0 && len(summaries) > limit {
+		summaries = summaries[:limit]
+	}
+
+	return summaries, nil
+}
+
+// formatTicketColumns renders summaries as aligned columns, one ticket per
+// line. Empty values are shown as "-".
+func formatTicketColumns(summaries []*ticket.Summary, columns []string) string {
+	var builder strings.Builder
+
+	writer := tabwriter.NewWriter(&builder, 0, 0, 2, ' ', 0)
+
+	for _, summary := range summaries {
+		values := make([]string, 0, len(columns))
+		for _, column := range columns {
+			values = append(values, cmp.Or(ticketColumnValue(summary, column), "-"))
+		}
+
+		_, _ = fmt.Fprintln(writer, strings.Join(values, "\t"))
+	}
+
+	_ = writer.Flush()
+
+	return builder.String()
+}
+
+func ticketColumnValue(summary *ticket.Summary, column string) string {
+	switch column {
+	case "id":
+		return summary.ID
+	case "status":
+		return summary.Status
+	case "priority":
+		return strconv.Itoa(summary.Priority)
+	case "type":
+		return summary.Type
+	case "title":
+		return summary.Title
+	case "assignee":
+		return summary.Assignee
+	case "parent":
+		return summary.Parent
+	case "blocked-by":
+		return strings.Join(summary.BlockedBy, ",")
+	case "labels":
+		return strings.Join(summary.Labels, ",")
+	case "created":
+		return summary.Created
+	default:
+		return ""
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -1688,6 +1690,159 @@ func Test_Ls_Parent_With_Limit_Offset_When_Invoked(t *testing.T) {
 	cli.AssertTicketNotListed(t, stdout, child3)
 }
 
+func Test_Ls_Ready_Lists_Only_Unblocked_Open_Tickets_When_Invoked(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketDir := c.TicketDir()
+
+	createTestTicket(t, ticketDir, "a-001", statusClosed, "Closed blocker", nil)
+	createTestTicket(t, ticketDir, "b-002", statusOpen, "Open blocker", nil)
+	createTestTicket(t, ticketDir, "c-003", statusOpen, "Blocked by closed", []string{"a-001"})
+	createTestTicket(t, ticketDir, "d-004", statusOpen, "Blocked by open", []string{"b-002"})
+	createTestTicket(t, ticketDir, "e-005", "in_progress", "In progress", nil)
+
+	stdout := c.MustRun("ls", "--ready")
+
+	cli.AssertTicketNotListed(t, stdout, "a-001")
+	cli.AssertTicketListed(t, stdout, "b-002")
+	cli.AssertTicketListed(t, stdout, "c-003")
+	cli.AssertTicketNotListed(t, stdout, "d-004")
+	cli.AssertTicketNotListed(t, stdout, "e-005")
+}
+
+func Test_Ls_Ready_Applies_Limit_And_Offset_After_Readiness_When_Invoked(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketDir := c.TicketDir()
+
+	createTestTicket(t, ticketDir, "a-001", statusOpen, "Blocker", nil)
+	createTestTicket(t, ticketDir, "b-002", statusOpen, "Blocked", []string{"a-001"})
+	createTestTicket(t, ticketDir, "c-003", statusOpen, "Ready 2", nil)
+	createTestTicket(t, ticketDir, "d-004", statusOpen, "Ready 3", nil)
+
+	stdout := c.MustRun("ls", "--ready", "--offset", "1", "--limit", "1")
+
+	cli.AssertTicketNotListed(t, stdout, "a-001")
+	cli.AssertTicketNotListed(t, stdout, "b-002")
+	cli.AssertTicketListed(t, stdout, "c-003")
+	cli.AssertTicketNotListed(t, stdout, "d-004")
+
+	stderr := c.MustFail("ls", "--ready", "--offset", "3")
+	cli.AssertContains(t, stderr, "offset out of bounds")
+}
+
+func Test_Ls_Ready_Rejects_Status_Filter_When_Invoked(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+
+	stderr := c.MustFail("ls", "--ready", "--status", "open")
+	cli.AssertContains(t, stderr, "--ready and --status cannot be used together")
+}
+
+func Test_Ls_Ready_Matches_Ready_Command_When_Parent_Not_Started(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	parentID := c.MustRun("create", "Parent")
+	childID := c.MustRun("create", "Child", "--parent", parentID)
+
+	stdout := c.MustRun("ls", "--ready")
+	cli.AssertTicketListed(t, stdout, parentID)
+	cli.AssertTicketNotListed(t, stdout, childID)
+
+	c.MustRun("start", parentID)
+
+	stdout = c.MustRun("ls", "--ready")
+	cli.AssertTicketNotListed(t, stdout, parentID)
+	cli.AssertTicketListed(t, stdout, childID)
+}
+
+func Test_Ls_Uses_Config_Defaults_When_Flags_Not_Given(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	writeFile(t, filepath.Join(c.Dir, ".tk.json"), `{
+		"ls": {"status": "open", "columns": ["id", "assignee", "title"], "sort": "-priority"}
+	}`)
+
+	lowID := c.MustRun("create", "Low", "--priority", "4", "--assignee", "alice")
+	highID := c.MustRun("create", "High", "--priority", "1")
+	startedID := c.MustRun("create", "Started")
+	c.MustRun("start", startedID)
+
+	stdout := c.MustRun("ls")
+	cli.AssertNotContains(t, stdout, startedID)
+
+	lines := strings.Split(stdout, "\n")
+	if len(lines) != 2 {
+		t.Fatalf("want 2 lines, got:\n%s", stdout)
+	}
+
+	if got, want := strings.Fields(lines[0]), []string{lowID, "alice", "Low"}; !slices.Equal(got, want) {
+		t.Errorf("line 0=%q, want fields %q", lines[0], want)
+	}
+
+	if got, want := strings.Fields(lines[1]), []string{highID, "-", "High"}; !slices.Equal(got, want) {
+		t.Errorf("line 1=%q, want fields %q", lines[1], want)
+	}
+}
+
+func Test_Ls_Flags_Override_Config_Defaults_When_Given(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	writeFile(t, filepath.Join(c.Dir, ".tk.json"), `{"ls": {"status": "open", "roots": true, "columns": ["id"]}}`)
+
+	parentID := c.MustRun("create", "Parent")
+	c.MustRun("start", parentID)
+	childID := c.MustRun("create", "Child", "--parent", parentID)
+
+	// --ready and --parent conflict with the configured status and roots,
+	// so those defaults are dropped instead of failing.
+	stdout := c.MustRun("ls", "--ready", "--parent", parentID, "--columns", "id,status")
+	if got, want := strings.Fields(stdout), []string{childID, "open"}; !slices.Equal(got, want) {
+		t.Errorf("stdout=%q, want fields %q", stdout, want)
+	}
+
+	stdout = c.MustRun("ls", "--status", "in_progress")
+	cli.AssertContains(t, stdout, parentID)
+	cli.AssertNotContains(t, stdout, "in_progress")
+}
+
+func Test_Ls_Sort_And_Columns_When_Invalid(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+
+	stderr := c.MustFail("ls", "--sort", "size")
+	cli.AssertContains(t, stderr, `unknown sort key: "size"`)
+
+	stderr = c.MustFail("ls", "--columns", "id,bogus")
+	cli.AssertContains(t, stderr, `unknown column: "bogus"`)
+}
+
+func Test_Ls_Sort_Applies_Offset_And_Limit_After_Sorting_When_Invoked(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ids := make([]string, 0, 4)
+
+	for i, priority := range []string{"3", "1", "4", "2"} {
+		ids = append(ids, c.MustRun("create", "Ticket "+strconv.Itoa(i), "--priority", priority))
+	}
+
+	stdout := c.MustRun("ls", "--sort", "priority", "--offset", "1", "--limit", "2", "--columns", "id")
+	if got, want := stdout, ids[3]+"\n"+ids[0]; got != want {
+		t.Fatalf("stdout=%q, want=%q", got, want)
+	}
+
+	stderr := c.MustFail("ls", "--sort", "priority", "--offset", "4")
+	cli.AssertContains(t, stderr, "offset out of bounds")
+}
+
 // createTestTicket creates a test ticket with proper format.
 func createTestTicket(t *testing.T, ticketDir, ticketID, status, title string, blockedBy []string) {
 	t.Helper()
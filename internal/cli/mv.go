@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/calvinalkan/agent-task/internal/ticket"
+
+	flag "github.com/spf13/pflag"
+)
+
+// MvCmd returns the mv command.
+func MvCmd(cfg *ticket.Config) *Command {
+	return &Command{
+		Flags: flag.NewFlagSet("mv", flag.ContinueOnError),
+		Usage: "mv <id> <new-id>",
+		Short: "Rename a ticket and update references",
+		Long: `Rename a ticket to a new ID. Other tickets that reference it in
+blocked-by or parent are rewritten to point at the new ID.
+
+Fails if <new-id> already exists. The move is recorded for undo before
+any ticket is written. If a ticket cannot be updated, all changes are
+rolled back; if tk is interrupted first, undo reverts the move.`,
+		Exec: func(_ context.Context, io *IO, args []string) error {
+			return execMv(io, cfg, args)
+		},
+	}
+}
+
+func execMv(io *IO, cfg *ticket.Config, args []string) error {
+	if len(args) == 0 {
+		return ticket.ErrIDRequired
+	}
+
+	oldID := args[0]
+
+	if !ticket.Exists(cfg.TicketDirAbs, oldID) {
+		return fmt.Errorf("%w: %s", ticket.ErrTicketNotFound, oldID)
+	}
+
+	if len(args) < 2 || args[1] == "" {
+		return ticket.ErrNewIDRequired
+	}
+
+	newID := args[1]
+
+	if !ticket.IsValidID(newID) {
+		return fmt.Errorf("%w: %s", ticket.ErrInvalidTicketID, newID)
+	}
+
+	if ticket.Exists(cfg.TicketDirAbs, newID) {
+		return fmt.Errorf("%w: %s", ticket.ErrTicketFileExists, newID)
+	}
+
+	referrers, err := ticket.MoveTicket(cfg.TicketDirAbs, oldID, newID)
+	if err != nil {
+		return fmt.Errorf("move ticket: %w", err)
+	}
+
+	cacheErr := ticket.DeleteCacheEntry(cfg.TicketDirAbs, oldID+".md")
+	if cacheErr != nil {
+		return fmt.Errorf("update cache: %w", cacheErr)
+	}
+
+	cacheErr = updateCacheForTickets(cfg, append([]string{newID}, referrers...))
+	if cacheErr != nil {
+		return cacheErr
+	}
+
+	io.Println("Moved", oldID, "to", newID)
+
+	for _, ticketID := range referrers {
+		io.Println("Updated", ticketID)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package cli_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/calvinalkan/agent-task/internal/cli"
+)
+
+func Test_Mv_Command_When_Args_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name       string
+		args       func(ticketID, otherID string) []string
+		wantStderr string
+	}{
+		{
+			name:       "missing ID",
+			args:       func(string, string) []string { return []string{"mv"} },
+			wantStderr: "ticket ID is required",
+		},
+		{
+			name:       "unknown ID",
+			args:       func(string, string) []string { return []string{"mv", "nonexistent", "new-id"} },
+			wantStderr: "ticket not found",
+		},
+		{
+			name:       "missing new ID",
+			args:       func(ticketID, _ string) []string { return []string{"mv", ticketID} },
+			wantStderr: "new ticket ID is required",
+		},
+		{
+			name:       "invalid new ID",
+			args:       func(ticketID, _ string) []string { return []string{"mv", ticketID, "bad/id"} },
+			wantStderr: "invalid ticket ID",
+		},
+		{
+			name:       "existing new ID",
+			args:       func(ticketID, otherID string) []string { return []string{"mv", ticketID, otherID} },
+			wantStderr: "ticket file already exists",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := cli.NewCLI(t)
+			ticketID := c.MustRun("create", "Ticket")
+			otherID := c.MustRun("create", "Other")
+
+			stderr := c.MustFail(tt.args(ticketID, otherID)...)
+			cli.AssertContains(t, stderr, tt.wantStderr)
+
+			cli.AssertContains(t, c.ReadTicket(ticketID), "# Ticket")
+		})
+	}
+}
+
+func Test_Mv_Renames_Ticket_And_Rewrites_References_When_Invoked(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketID := c.MustRun("create", "Epic")
+	childID := c.MustRun("create", "Child", "--parent", ticketID)
+	blockedID := c.MustRun("create", "Blocked")
+	unrelatedID := c.MustRun("create", "Unrelated")
+
+	c.MustRun("block", blockedID, ticketID)
+	c.MustRun("block", blockedID, unrelatedID)
+
+	stdout := c.MustRun("mv", ticketID, "epic-1")
+	cli.AssertContains(t, stdout, "Moved "+ticketID+" to epic-1")
+	cli.AssertContains(t, stdout, "Updated "+childID)
+	cli.AssertContains(t, stdout, "Updated "+blockedID)
+	cli.AssertNotContains(t, stdout, "Updated "+unrelatedID)
+
+	_, err := os.Stat(filepath.Join(c.TicketDir(), ticketID+".md"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("old ticket file still exists: err=%v", err)
+	}
+
+	cli.AssertContains(t, c.ReadTicket("epic-1"), "id: epic-1")
+	cli.AssertContains(t, c.ReadTicket(childID), "parent: epic-1")
+	cli.AssertContains(t, c.ReadTicket(blockedID), "blocked-by: [epic-1, "+unrelatedID+"]")
+
+	cli.AssertContains(t, c.MustRun("show", "epic-1"), "Epic")
+
+	ls := c.MustRun("ls")
+	cli.AssertTicketListed(t, ls, "epic-1")
+	cli.AssertTicketNotListed(t, ls, ticketID)
+}
@@ -2,6 +2,8 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 
 	"github.com/calvinalkan/agent-task/internal/ticket"
 
@@ -29,6 +31,15 @@ func execPrintConfig(io *IO, cfg *ticket.Config) error {
 		io.Println("editor=" + cfg.Editor)
 	}
 
+	if cfg.Ls != nil {
+		data, err := json.Marshal(cfg.Ls)
+		if err != nil {
+			return fmt.Errorf("marshal ls defaults: %w", err)
+		}
+
+		io.Println("ls=" + string(data))
+	}
+
 	io.Println("")
 	io.Println("# sources")
 
@@ -44,5 +55,9 @@ func execPrintConfig(io *IO, cfg *ticket.Config) error {
 		}
 	}
 
+	if cfg.Sources.Workflow != "" {
+		io.Println("workflow=" + cfg.Sources.Workflow)
+	}
+
 	return nil
 }
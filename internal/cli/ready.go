@@ -71,12 +71,12 @@ func execReady(io *IO, cfg *ticket.Config, jsonOutput bool, limit int, field str
 		return errInvalidField
 	}
 
-	results, err := ticket.ListTickets(cfg.TicketDirAbs, &ticket.ListTicketsOptions{Limit: 0}, nil)
+	results, err := ticket.ListTickets(cfg.TicketDirAbs, &ticket.ListTicketsOptions{Workflow: &cfg.Workflow}, nil)
 	if err != nil {
 		return fmt.Errorf("list tickets: %w", err)
 	}
 
-	ready, warnings := filterReadyTickets(results)
+	ready, warnings := filterReadyTickets(results, &cfg.Workflow)
 
 	for _, w := range warnings {
 		io.WarnLLM(w.issue, w.action)
@@ -232,7 +232,7 @@ type readyCheckResult struct {
 }
 
 // filterReadyTickets builds status map and returns ready tickets.
-func filterReadyTickets(results []ticket.Result) ([]*ticket.Summary, []readyWarning) {
+func filterReadyTickets(results []ticket.Result, workflow *ticket.Workflow) ([]*ticket.Summary, []readyWarning) {
 	summaryMap := make(map[string]*ticket.Summary)
 
 	var (
@@ -250,7 +250,7 @@ func filterReadyTickets(results []ticket.Result) ([]*ticket.Summary, []readyWarn
 			continue
 		}
 
-		summary, parseErr := ticket.ParseTicketFrontmatter(result.Path)
+		summary, parseErr := ticket.ParseTicketFrontmatter(result.Path, workflow)
 		if parseErr != nil {
 			allWarnings = append(allWarnings, readyWarning{
 				issue:  fmt.Sprintf("%s: %v", result.Path, parseErr),
@@ -11,53 +11,63 @@ import (
 
 // ReopenCmd returns the reopen command.
 func ReopenCmd(cfg *ticket.Config) *Command {
+	fs := flag.NewFlagSet("reopen", flag.ContinueOnError)
+	addAtomicFlag(fs)
+
 	return &Command{
-		Flags: flag.NewFlagSet("reopen", flag.ContinueOnError),
-		Usage: "reopen <id>",
+		Flags: fs,
+		Usage: "reopen <id>... [flags]",
 		Short: "Set status to open",
 		Long: `Set ticket status back to open.
 
 Requirements:
-  - Ticket must be closed
-  - Parent ticket must not be closed (reopen parent first)`,
+  - Ticket must be closed (or in a workflow status that allows open)
+  - Parent ticket must not be closed (reopen parent first)
+
+Multiple IDs are reopened in order; failures are reported at the end.
+Use --atomic to roll back the others if any ticket fails (best effort).`,
 		Exec: func(_ context.Context, io *IO, args []string) error {
-			return execReopen(io, cfg, args)
+			atomicMode, _ := fs.GetBool("atomic")
+
+			return execBulkTransition(io, cfg, args, atomicMode, bulkTransition{
+				name:  "reopen",
+				done:  "Reopened",
+				apply: reopenTicket,
+			})
 		},
 	}
 }
 
-func execReopen(io *IO, cfg *ticket.Config, args []string) error {
-	if len(args) == 0 {
-		return ticket.ErrIDRequired
-	}
+func reopenTicket(cfg *ticket.Config, ticketID string) ([]byte, error) {
+	ticketDir := cfg.TicketDirAbs
 
-	ticketID := args[0]
-
-	if !ticket.Exists(cfg.TicketDirAbs, ticketID) {
-		return fmt.Errorf("%w: %s", ticket.ErrTicketNotFound, ticketID)
+	if !ticket.Exists(ticketDir, ticketID) {
+		return nil, fmt.Errorf("%w: %s", ticket.ErrTicketNotFound, ticketID)
 	}
 
-	path := ticket.Path(cfg.TicketDirAbs, ticketID)
+	path := ticket.Path(ticketDir, ticketID)
 
 	// Check parent constraints before acquiring lock
 	parentID, parentErr := ticket.ReadTicketParent(path)
 	if parentErr != nil {
-		return fmt.Errorf("reading parent: %w", parentErr)
+		return nil, fmt.Errorf("reading parent: %w", parentErr)
 	}
 
 	if parentID != "" {
-		parentPath := ticket.Path(cfg.TicketDirAbs, parentID)
+		parentPath := ticket.Path(ticketDir, parentID)
 
 		parentStatus, statusErr := ticket.ReadTicketStatus(parentPath)
 		if statusErr != nil {
-			return fmt.Errorf("reading parent status: %w", statusErr)
+			return nil, fmt.Errorf("reading parent status: %w", statusErr)
 		}
 
 		if parentStatus == ticket.StatusClosed {
-			return fmt.Errorf("%w: %s", ticket.ErrParentClosed, parentID)
+			return nil, fmt.Errorf("%w: %s", ticket.ErrParentClosed, parentID)
 		}
 	}
 
+	var original []byte
+
 	err := ticket.WithTicketLock(path, func(content []byte) ([]byte, error) {
 		status, statusErr := ticket.GetStatusFromContent(content)
 		if statusErr != nil {
@@ -68,12 +78,9 @@ func execReopen(io *IO, cfg *ticket.Config, args []string) error {
 			return nil, ticket.ErrTicketAlreadyOpen
 		}
 
-		if status == ticket.StatusInProgress {
-			return nil, ticket.ErrTicketNotClosed
-		}
-
-		if status != ticket.StatusClosed {
-			return nil, fmt.Errorf("%w (current status: %s)", ticket.ErrTicketNotClosed, status)
+		if !cfg.Workflow.Allows(status, ticket.StatusOpen) {
+			return nil, fmt.Errorf("%w (current status: %s, allowed next: %s)",
+				ticket.ErrTicketNotClosed, status, cfg.Workflow.FormatNext(status))
 		}
 
 		newContent, updateErr := ticket.UpdateStatusInContent(content, ticket.StatusOpen)
@@ -81,6 +88,8 @@ func execReopen(io *IO, cfg *ticket.Config, args []string) error {
 			return nil, fmt.Errorf("updating status: %w", updateErr)
 		}
 
+		original = content
+
 		result := ticket.RemoveFieldFromContent(newContent, "closed")
 		if result == nil {
 			return newContent, nil
@@ -89,20 +98,8 @@ func execReopen(io *IO, cfg *ticket.Config, args []string) error {
 		return result, nil
 	})
 	if err != nil {
-		return fmt.Errorf("update ticket: %w", err)
-	}
-
-	summary, parseErr := ticket.ParseTicketFrontmatter(path)
-	if parseErr != nil {
-		return fmt.Errorf("parse frontmatter: %w", parseErr)
+		return nil, fmt.Errorf("update ticket: %w", err)
 	}
 
-	cacheErr := ticket.UpdateCacheAfterTicketWrite(cfg.TicketDirAbs, ticketID+".md", &summary)
-	if cacheErr != nil {
-		return fmt.Errorf("update cache: %w", cacheErr)
-	}
-
-	io.Println("Reopened", ticketID)
-
-	return nil
+	return original, nil
 }
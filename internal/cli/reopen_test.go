@@ -256,3 +256,21 @@ func Test_Reopen_Parent_Then_Child_When_Invoked(t *testing.T) {
 	content := c.ReadTicket(childID)
 	cli.AssertContains(t, content, "status: open")
 }
+
+func Test_Reopen_Atomic_Keeps_Tickets_Closed_When_One_ID_Fails(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	closedID := c.MustRun("create", "Closed")
+	openID := c.MustRun("create", "Open")
+	c.MustRun("start", closedID)
+	c.MustRun("close", closedID)
+
+	stderr := c.MustFail("reopen", "--atomic", closedID, openID)
+	cli.AssertContains(t, stderr, "error: "+openID+": update ticket: ticket is already open")
+	cli.AssertContains(t, stderr, "1 of 2 tickets failed to reopen")
+
+	content := c.ReadTicket(closedID)
+	cli.AssertContains(t, content, "status: closed")
+	cli.AssertContains(t, content, "closed: ")
+}
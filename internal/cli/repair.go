@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"slices"
@@ -38,7 +39,7 @@ func execRepair(io *IO, cfg *ticket.Config, fs *flag.FlagSet, args []string) err
 	rebuildCache, _ := fs.GetBool("rebuild-cache")
 
 	if rebuildCache {
-		results, err := ticket.BuildCacheParallelLocked(cfg.TicketDirAbs, nil)
+		results, err := ticket.BuildCacheParallelLocked(cfg.TicketDirAbs, nil, &cfg.Workflow)
 		if err != nil {
 			return fmt.Errorf("rebuild cache: %w", err)
 		}
@@ -62,15 +63,17 @@ func execRepair(io *IO, cfg *ticket.Config, fs *flag.FlagSet, args []string) err
 	}
 
 	if all {
-		return repairAllTickets(io, cfg.TicketDirAbs, dryRun)
+		return repairAllTickets(io, cfg, dryRun)
 	}
 
 	ticketID := args[0]
 
-	return repairSingleTicket(io, cfg.TicketDirAbs, ticketID, dryRun)
+	return repairSingleTicket(io, cfg, ticketID, dryRun)
 }
 
-func repairSingleTicket(io *IO, ticketDirAbs, ticketID string, dryRun bool) error {
+func repairSingleTicket(io *IO, cfg *ticket.Config, ticketID string, dryRun bool) error {
+	ticketDirAbs := cfg.TicketDirAbs
+
 	if !ticket.Exists(ticketDirAbs, ticketID) {
 		return fmt.Errorf("%w: %s", ticket.ErrTicketNotFound, ticketID)
 	}
@@ -104,17 +107,22 @@ func repairSingleTicket(io *IO, ticketDirAbs, ticketID string, dryRun bool) erro
 
 	newBlockedBy := removeItems(blockedBy, staleBlockers)
 
-	err = ticket.UpdateTicketBlockedByLocked(path, newBlockedBy)
+	original, err := writeBlockedBy(path, newBlockedBy)
 	if err != nil {
 		return fmt.Errorf("update blocked_by: %w", err)
 	}
 
-	summary, parseErr := ticket.ParseTicketFrontmatter(path)
+	undoErr := recordUndo(cfg, "repair", map[string][]byte{ticketID: original}, nil)
+	if undoErr != nil {
+		return undoErr
+	}
+
+	summary, parseErr := ticket.ParseTicketFrontmatter(path, &cfg.Workflow)
 	if parseErr != nil {
 		return fmt.Errorf("parse frontmatter: %w", parseErr)
 	}
 
-	cacheErr := ticket.UpdateCacheAfterTicketWrite(ticketDirAbs, ticketID+".md", &summary)
+	cacheErr := ticket.UpdateCacheAfterTicketWrite(ticketDirAbs, ticketID+".md", &summary, &cfg.Workflow)
 	if cacheErr != nil {
 		return fmt.Errorf("update cache: %w", cacheErr)
 	}
@@ -124,13 +132,14 @@ func repairSingleTicket(io *IO, ticketDirAbs, ticketID string, dryRun bool) erro
 	return nil
 }
 
-func repairAllTickets(io *IO, ticketDirAbs string, dryRun bool) error {
-	results, err := ticket.ListTickets(ticketDirAbs, &ticket.ListTicketsOptions{Limit: 0}, nil)
+func repairAllTickets(io *IO, cfg *ticket.Config, dryRun bool) error {
+	results, err := ticket.ListTickets(cfg.TicketDirAbs, &ticket.ListTicketsOptions{Workflow: &cfg.Workflow}, nil)
 	if err != nil {
 		return fmt.Errorf("list tickets: %w", err)
 	}
 
 	validIDs := buildValidIDMap(io, results)
+	originals := make(map[string][]byte)
 	anyRepaired := false
 
 	for _, result := range results {
@@ -138,9 +147,10 @@ func repairAllTickets(io *IO, ticketDirAbs string, dryRun bool) error {
 			continue
 		}
 
-		repaired, repairErr := repairTicketBlockers(io, result.Summary, validIDs, dryRun)
+		repaired, repairErr := repairTicketBlockers(io, &cfg.Workflow, result.Summary, validIDs, dryRun, originals)
 		if repairErr != nil {
-			return repairErr
+			// Journal the tickets already repaired so they can still be undone.
+			return errors.Join(repairErr, recordUndo(cfg, "repair", originals, nil))
 		}
 
 		if repaired {
@@ -152,7 +162,7 @@ func repairAllTickets(io *IO, ticketDirAbs string, dryRun bool) error {
 		io.Println("Nothing to repair")
 	}
 
-	return nil
+	return recordUndo(cfg, "repair", originals, nil)
 }
 
 func buildValidIDMap(io *IO, results []ticket.Result) map[string]bool {
@@ -174,7 +184,9 @@ func buildValidIDMap(io *IO, results []ticket.Result) map[string]bool {
 	return validIDs
 }
 
-func repairTicketBlockers(io *IO, summary *ticket.Summary, validIDs map[string]bool, dryRun bool) (bool, error) {
+// repairTicketBlockers removes stale blockers from a ticket and stores its
+// content from before the write in originals.
+func repairTicketBlockers(io *IO, workflow *ticket.Workflow, summary *ticket.Summary, validIDs map[string]bool, dryRun bool, originals map[string][]byte) (bool, error) {
 	staleBlockers := findStaleBlockersFromMap(summary.BlockedBy, validIDs)
 
 	if len(staleBlockers) == 0 {
@@ -192,18 +204,20 @@ func repairTicketBlockers(io *IO, summary *ticket.Summary, validIDs map[string]b
 	if !dryRun {
 		newBlockedBy := removeItems(summary.BlockedBy, staleBlockers)
 
-		updateErr := ticket.UpdateTicketBlockedByLocked(summary.Path, newBlockedBy)
+		original, updateErr := writeBlockedBy(summary.Path, newBlockedBy)
 		if updateErr != nil {
 			return false, fmt.Errorf("updating blocked-by: %w", updateErr)
 		}
 
+		originals[summary.ID] = original
+
 		updated := *summary
 		updated.BlockedBy = newBlockedBy
 
 		ticketDir := filepath.Dir(summary.Path)
 		filename := filepath.Base(summary.Path)
 
-		cacheErr := ticket.UpdateCacheAfterTicketWrite(ticketDir, filename, &updated)
+		cacheErr := ticket.UpdateCacheAfterTicketWrite(ticketDir, filename, &updated, workflow)
 		if cacheErr != nil {
 			return false, fmt.Errorf("updating cache: %w", cacheErr)
 		}
@@ -238,6 +252,23 @@ func findStaleBlockersFromMap(blockedBy []string, validIDs map[string]bool) []st
 	return stale
 }
 
+// writeBlockedBy sets the blocked-by list of the ticket at path under its
+// lock and returns the content from before the write.
+func writeBlockedBy(path string, blockedBy []string) ([]byte, error) {
+	var original []byte
+
+	err := ticket.WithTicketLock(path, func(content []byte) ([]byte, error) {
+		original = content
+
+		return ticket.UpdateBlockedByInContent(content, blockedBy)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update ticket: %w", err)
+	}
+
+	return original, nil
+}
+
 func removeItems(slice, toRemove []string) []string {
 	result := make([]string, 0, len(slice))
 
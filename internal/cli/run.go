@@ -142,13 +142,24 @@ func allCommands(cfg *ticket.Config, env map[string]string) []*Command {
 		ShowCmd(cfg),
 		CreateCmd(cfg),
 		LsCmd(cfg),
+		SearchCmd(cfg),
+		ExportCmd(cfg),
+		StatsCmd(cfg),
 		StartCmd(cfg),
 		CloseCmd(cfg),
 		ReopenCmd(cfg),
+		StatusCmd(cfg),
 		BlockCmd(cfg),
 		UnblockCmd(cfg),
+		AssignCmd(cfg),
+		LabelCmd(cfg),
 		ReadyCmd(cfg),
+		GraphCmd(cfg),
+		CheckCmd(cfg),
+		WatchCmd(cfg),
 		RepairCmd(cfg),
+		MvCmd(cfg),
+		UndoCmd(cfg),
 		EditCmd(cfg, env),
 		PrintConfigCmd(cfg),
 	}
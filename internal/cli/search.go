@@ -0,0 +1,217 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/calvinalkan/agent-task/internal/ticket"
+
+	flag "github.com/spf13/pflag"
+)
+
+// Search scoring weights. A title hit counts more than a body hit, and the
+// full query appearing verbatim ranks above scattered terms.
+const (
+	searchTitleWeight  = 5
+	searchBodyWeight   = 1
+	searchPhraseWeight = 10
+	searchSnippetWidth = 40 // bytes of context on each side of the match
+)
+
+var errSearchQueryRequired = errors.New("search query is required")
+
+// SearchCmd returns the search command.
+func SearchCmd(cfg *ticket.Config) *Command {
+	fs := flag.NewFlagSet("search", flag.ContinueOnError)
+	addListFilterFlags(fs)
+	fs.Int("limit", defaultLimit, "Maximum tickets to show")
+
+	return &Command{
+		Flags: fs,
+		Usage: "search <query> [flags]",
+		Short: "Search ticket titles and bodies",
+		Long: `Search ticket titles and bodies for all words of the query (case-insensitive).
+
+Matches are ranked by relevance: title hits count more than body hits, and
+tickets containing the exact phrase rank first. Each match is printed with a
+snippet of the body around the first hit.
+
+Accepts the same filters as ls, e.g.:
+  tk search "race condition" --status open --type bug`,
+		Exec: func(_ context.Context, io *IO, args []string) error {
+			return execSearch(io, cfg, fs, args)
+		},
+	}
+}
+
+// searchMatch is a ticket matching a search query.
+type searchMatch struct {
+	summary *ticket.Summary
+	score   int
+	snippet string
+}
+
+func execSearch(io *IO, cfg *ticket.Config, fs *flag.FlagSet, args []string) error {
+	query := strings.TrimSpace(strings.Join(args, " "))
+	if query == "" {
+		return errSearchQueryRequired
+	}
+
+	listOpts, err := parseListFilterFlags(cfg, fs)
+	if err != nil {
+		return err
+	}
+
+	limit, _ := fs.GetInt("limit")
+	if limit < 0 {
+		return errors.New("--limit must be non-negative")
+	}
+
+	summaries, err := listTickets(io, cfg, &listOpts)
+	if err != nil {
+		return err
+	}
+
+	terms := strings.Fields(strings.ToLower(query))
+
+	var matches []searchMatch
+
+	for _, summary := range summaries {
+		_, body, readErr := parseTicketParts(ticket.Path(cfg.TicketDirAbs, summary.ID))
+		if readErr != nil {
+			io.WarnLLM(summary.ID+": "+readErr.Error(), "fix the ticket file or delete it if invalid")
+
+			continue
+		}
+
+		body = stripTitleHeading(body, summary.Title)
+
+		score := scoreSearchMatch(terms, summary.Title, body)
+		if score == 0 {
+			continue
+		}
+
+		matches = append(matches, searchMatch{
+			summary: summary,
+			score:   score,
+			snippet: searchSnippet(body, strings.Join(terms, " "), terms),
+		})
+	}
+
+	slices.SortStableFunc(matches, func(a, b searchMatch) int {
+		return cmp.Or(cmp.Compare(b.score, a.score), strings.Compare(a.summary.ID, b.summary.ID))
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	for _, match := range matches {
+		io.Println(formatTicketLine(match.summary))
+
+		if match.snippet != "" {
+			io.Println("    " + match.snippet)
+		}
+	}
+
+	return nil
+}
+
+// scoreSearchMatch returns the relevance of a ticket for the query terms,
+// or 0 if any term is missing from both title and body.
+func scoreSearchMatch(terms []string, title, body string) int {
+	lowerTitle := strings.ToLower(title)
+	lowerBody := strings.ToLower(body)
+
+	score := 0
+
+	for _, term := range terms {
+		titleHits := strings.Count(lowerTitle, term)
+		bodyHits := strings.Count(lowerBody, term)
+
+		if titleHits+bodyHits == 0 {
+			return 0
+		}
+
+		score += titleHits*searchTitleWeight + bodyHits*searchBodyWeight
+	}
+
+	if len(terms) > 1 {
+		phrase := strings.Join(terms, " ")
+		if strings.Contains(lowerTitle, phrase) || strings.Contains(lowerBody, phrase) {
+			score += searchPhraseWeight
+		}
+	}
+
+	return score
+}
+
+// stripTitleHeading removes the "# <title>" line so title words are not
+// counted twice.
+func stripTitleHeading(body, title string) string {
+	heading := "# " + title
+
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == heading {
+			return strings.Join(slices.Delete(lines, i, i+1), "\n")
+		}
+	}
+
+	return body
+}
+
+// searchSnippet returns a single-line excerpt of body around the first
+// occurrence of phrase, falling back to the first term found.
+// Returns "" if nothing matches in the body.
+func searchSnippet(body, phrase string, terms []string) string {
+	text := strings.Join(strings.Fields(body), " ")
+	lower := strings.ToLower(text)
+
+	// Offsets into lower are only valid for text if lowercasing kept the length.
+	if len(lower) != len(text) {
+		lower = text
+	}
+
+	pos := strings.Index(lower, phrase)
+	matchLen := len(phrase)
+
+	for _, term := range terms {
+		if pos >= 0 {
+			break
+		}
+
+		pos = strings.Index(lower, term)
+		matchLen = len(term)
+	}
+
+	if pos < 0 {
+		return ""
+	}
+
+	start := max(0, pos-searchSnippetWidth)
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+
+	end := min(len(text), pos+matchLen+searchSnippetWidth)
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+
+	snippet := text[start:end]
+
+	if start > 0 {
+		snippet = "..." + snippet
+	}
+
+	if end < len(text) {
+		snippet += "..."
+	}
+
+	return snippet
+}
@@ -0,0 +1,81 @@
+package cli_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/calvinalkan/agent-task/internal/cli"
+)
+
+func Test_Search_Ranks_Title_Match_Above_Body_Match_When_Both_Match(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	bodyID := c.MustRun("create", "Refactor parser", "-d", "The cache invalidation is flaky here.")
+	titleID := c.MustRun("create", "Fix cache invalidation")
+	c.MustRun("create", "Unrelated", "-d", "Nothing to see")
+
+	stdout := c.MustRun("search", "cache", "invalidation")
+
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines (2 matches, 1 snippet), got %d:\n%s", len(lines), stdout)
+	}
+
+	cli.AssertContains(t, lines[0], titleID+" [open] - Fix cache invalidation")
+	cli.AssertContains(t, lines[1], bodyID+" [open] - Refactor parser")
+	cli.AssertContains(t, lines[2], "The cache invalidation is flaky here.")
+}
+
+func Test_Search_Requires_All_Terms_When_Query_Has_Multiple_Words(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	bothID := c.MustRun("create", "Login timeout", "-d", "Happens on slow networks")
+	oneID := c.MustRun("create", "Login button color")
+
+	stdout := c.MustRun("search", "login", "NETWORKS")
+
+	cli.AssertTicketListed(t, stdout, bothID)
+	cli.AssertTicketNotListed(t, stdout, oneID)
+}
+
+func Test_Search_Honors_Ls_Filters_When_Given(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	bugID := c.MustRun("create", "Crash on save", "-t", "bug")
+	taskID := c.MustRun("create", "Crash reporting", "-t", "task")
+
+	stdout := c.MustRun("search", "crash", "--type", "bug")
+
+	cli.AssertTicketListed(t, stdout, bugID)
+	cli.AssertTicketNotListed(t, stdout, taskID)
+
+	stderr := c.MustFail("search", "crash", "--status", "bogus")
+	cli.AssertContains(t, stderr, "invalid status")
+}
+
+func Test_Search_Returns_Error_When_Query_Empty(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+
+	stderr := c.MustFail("search")
+	cli.AssertContains(t, stderr, "search query is required")
+}
+
+func Test_Search_Truncates_Snippet_When_Body_Is_Long(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	long := strings.Repeat("lorem ipsum ", 20) + "needle " + strings.Repeat("dolor sit ", 20)
+	ticketID := c.MustRun("create", "Haystack", "-d", long)
+
+	stdout := c.MustRun("search", "needle")
+
+	cli.AssertTicketListed(t, stdout, ticketID)
+	cli.AssertContains(t, stdout, "...")
+	cli.AssertContains(t, stdout, "needle")
+	cli.AssertNotContains(t, stdout, strings.Repeat("lorem ipsum ", 10))
+}
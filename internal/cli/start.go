@@ -11,102 +11,133 @@ import (
 
 // StartCmd returns the start command.
 func StartCmd(cfg *ticket.Config) *Command {
+	fs := flag.NewFlagSet("start", flag.ContinueOnError)
+	addAtomicFlag(fs)
+
 	return &Command{
-		Flags: flag.NewFlagSet("start", flag.ContinueOnError),
-		Usage: "start <id>",
+		Flags: fs,
+		Usage: "start <id>... [flags]",
 		Short: "Set status to in_progress",
 		Long: `Set ticket status to in_progress.
 
 Requirements:
-  - Ticket must be open
-  - Parent ticket must be started first (if any)`,
+  - Ticket must be open (or in a workflow status that allows in_progress)
+  - Parent ticket must be started first (if any)
+
+With a single ID, the started ticket is printed.
+Multiple IDs are started in order; failures are reported at the end.
+Use --atomic to roll back the others if any ticket fails (best effort).`,
 		Exec: func(_ context.Context, io *IO, args []string) error {
-			return execStart(io, cfg, args)
+			atomicMode, _ := fs.GetBool("atomic")
+
+			return execStart(io, cfg, args, atomicMode)
 		},
 	}
 }
 
-func execStart(io *IO, cfg *ticket.Config, args []string) error {
-	if len(args) == 0 {
-		return ticket.ErrIDRequired
+func execStart(io *IO, cfg *ticket.Config, args []string, atomicMode bool) error {
+	err := execBulkTransition(io, cfg, args, atomicMode, bulkTransition{
+		name:  "start",
+		done:  "Started",
+		apply: startTicket,
+	})
+	if err != nil || len(args) != 1 {
+		return err
 	}
 
-	ticketID := args[0]
+	io.Println()
 
-	if !ticket.Exists(cfg.TicketDirAbs, ticketID) {
-		return fmt.Errorf("%w: %s", ticket.ErrTicketNotFound, ticketID)
+	content, err := ticket.ReadTicket(ticket.Path(cfg.TicketDirAbs, args[0]))
+	if err != nil {
+		return fmt.Errorf("read ticket: %w", err)
 	}
 
-	path := ticket.Path(cfg.TicketDirAbs, ticketID)
+	io.Printf("%s", content)
+
+	return nil
+}
+
+func startTicket(cfg *ticket.Config, ticketID string) ([]byte, error) {
+	ticketDir := cfg.TicketDirAbs
+
+	if !ticket.Exists(ticketDir, ticketID) {
+		return nil, fmt.Errorf("%w: %s", ticket.ErrTicketNotFound, ticketID)
+	}
+
+	path := ticket.Path(ticketDir, ticketID)
 
 	status, statusErr := ticket.ReadTicketStatus(path)
 	if statusErr != nil {
-		return fmt.Errorf("reading status: %w", statusErr)
+		return nil, fmt.Errorf("reading status: %w", statusErr)
 	}
 
-	if status != ticket.StatusOpen {
-		return fmt.Errorf("%w (current status: %s)", ticket.ErrTicketNotOpen, status)
+	startErr := checkCanStartFrom(&cfg.Workflow, status)
+	if startErr != nil {
+		return nil, startErr
 	}
 
-	summary, parseErr := ticket.ParseTicketFrontmatter(path)
+	summary, parseErr := ticket.ParseTicketFrontmatter(path, &cfg.Workflow)
 	if parseErr != nil {
-		return fmt.Errorf("parse frontmatter: %w", parseErr)
+		return nil, fmt.Errorf("parse frontmatter: %w", parseErr)
 	}
 
 	// Validate startability against the spec model rules.
-	canStartErr := canStartTicket(cfg.TicketDirAbs, &summary)
+	canStartErr := canStartTicket(&cfg.Workflow, ticketDir, &summary)
 	if canStartErr != nil {
-		return canStartErr
+		return nil, canStartErr
 	}
 
+	var original []byte
+
 	err := ticket.WithTicketLock(path, func(content []byte) ([]byte, error) {
 		status, statusErr := ticket.GetStatusFromContent(content)
 		if statusErr != nil {
 			return nil, fmt.Errorf("reading status: %w", statusErr)
 		}
 
-		if status != ticket.StatusOpen {
-			return nil, fmt.Errorf("%w (current status: %s)", ticket.ErrTicketNotOpen, status)
+		startErr := checkCanStartFrom(&cfg.Workflow, status)
+		if startErr != nil {
+			return nil, startErr
 		}
 
+		original = content
+
 		return ticket.UpdateStatusInContent(content, ticket.StatusInProgress)
 	})
 	if err != nil {
-		return fmt.Errorf("update ticket: %w", err)
-	}
-
-	summary, parseErr = ticket.ParseTicketFrontmatter(path)
-	if parseErr != nil {
-		return fmt.Errorf("parse frontmatter: %w", parseErr)
+		return nil, fmt.Errorf("update ticket: %w", err)
 	}
 
-	cacheErr := ticket.UpdateCacheAfterTicketWrite(cfg.TicketDirAbs, ticketID+".md", &summary)
-	if cacheErr != nil {
-		return fmt.Errorf("update cache: %w", cacheErr)
-	}
-
-	io.Println("Started", ticketID)
-	io.Println()
+	return original, nil
+}
 
-	content, err := ticket.ReadTicket(path)
-	if err != nil {
-		return fmt.Errorf("read ticket: %w", err)
+// checkCanStartFrom returns ErrTicketNotOpen, naming the allowed next
+// statuses, unless the workflow allows moving from status to in_progress.
+func checkCanStartFrom(workflow *ticket.Workflow, status string) error {
+	if workflow.Allows(status, ticket.StatusInProgress) {
+		return nil
 	}
 
-	io.Printf("%s", content)
-
-	return nil
+	return fmt.Errorf("%w (current status: %s, allowed next: %s)",
+		ticket.ErrTicketNotOpen, status, workflow.FormatNext(status))
 }
 
 // canStartTicket mirrors the spec model's canStart logic so behavior tests
 // stay aligned with the in-memory oracle.
-func canStartTicket(ticketDir string, summary *ticket.Summary) error {
-	if summary.Status != ticket.StatusOpen {
-		return fmt.Errorf("%w (current status: %s)", ticket.ErrTicketNotOpen, summary.Status)
+func canStartTicket(workflow *ticket.Workflow, ticketDir string, summary *ticket.Summary) error {
+	startErr := checkCanStartFrom(workflow, summary.Status)
+	if startErr != nil {
+		return startErr
 	}
 
+	return checkStartDependencies(workflow, ticketDir, summary)
+}
+
+// checkStartDependencies checks that all blockers are closed and that the
+// parent is started and its ancestors are unblocked.
+func checkStartDependencies(workflow *ticket.Workflow, ticketDir string, summary *ticket.Summary) error {
 	for _, blockerID := range summary.BlockedBy {
-		blocker, err := readSummary(ticketDir, blockerID, "blocker")
+		blocker, err := readSummary(workflow, ticketDir, blockerID, "blocker")
 		if err != nil {
 			return err
 		}
@@ -120,7 +151,7 @@ func canStartTicket(ticketDir string, summary *ticket.Summary) error {
 		return nil
 	}
 
-	parent, err := readSummary(ticketDir, summary.Parent, "parent")
+	parent, err := readSummary(workflow, ticketDir, summary.Parent, "parent")
 	if err != nil {
 		return err
 	}
@@ -129,7 +160,7 @@ func canStartTicket(ticketDir string, summary *ticket.Summary) error {
 		return fmt.Errorf("%w: %s", ticket.ErrParentNotStarted, summary.Parent)
 	}
 
-	ancestorErr := ensureAncestorUnblocked(ticketDir, parent, make(map[string]bool))
+	ancestorErr := ensureAncestorUnblocked(workflow, ticketDir, parent, make(map[string]bool))
 	if ancestorErr != nil {
 		return fmt.Errorf("ancestor not ready: %s: %w", parent.ID, ancestorErr)
 	}
@@ -137,7 +168,7 @@ func canStartTicket(ticketDir string, summary *ticket.Summary) error {
 	return nil
 }
 
-func ensureAncestorUnblocked(ticketDir string, summary *ticket.Summary, visited map[string]bool) error {
+func ensureAncestorUnblocked(workflow *ticket.Workflow, ticketDir string, summary *ticket.Summary, visited map[string]bool) error {
 	if visited[summary.ID] {
 		return fmt.Errorf("ancestor cycle detected: %s", summary.ID)
 	}
@@ -145,7 +176,7 @@ func ensureAncestorUnblocked(ticketDir string, summary *ticket.Summary, visited
 	visited[summary.ID] = true
 
 	for _, blockerID := range summary.BlockedBy {
-		blocker, err := readSummary(ticketDir, blockerID, "blocker")
+		blocker, err := readSummary(workflow, ticketDir, blockerID, "blocker")
 		if err != nil {
 			return err
 		}
@@ -159,15 +190,15 @@ func ensureAncestorUnblocked(ticketDir string, summary *ticket.Summary, visited
 		return nil
 	}
 
-	parent, err := readSummary(ticketDir, summary.Parent, "parent")
+	parent, err := readSummary(workflow, ticketDir, summary.Parent, "parent")
 	if err != nil {
 		return err
 	}
 
-	return ensureAncestorUnblocked(ticketDir, parent, visited)
+	return ensureAncestorUnblocked(workflow, ticketDir, parent, visited)
 }
 
-func readSummary(ticketDir, id, relation string) (*ticket.Summary, error) {
+func readSummary(workflow *ticket.Workflow, ticketDir, id, relation string) (*ticket.Summary, error) {
 	if !ticket.Exists(ticketDir, id) {
 		switch relation {
 		case "parent":
@@ -181,7 +212,7 @@ func readSummary(ticketDir, id, relation string) (*ticket.Summary, error) {
 
 	path := ticket.Path(ticketDir, id)
 
-	summary, err := ticket.ParseTicketFrontmatter(path)
+	summary, err := ticket.ParseTicketFrontmatter(path, workflow)
 	if err != nil {
 		return nil, fmt.Errorf("parse frontmatter: %w", err)
 	}
@@ -207,3 +207,20 @@ func Test_Start_With_No_Parent_Succeeds_When_Invoked(t *testing.T) {
 	content := c.ReadTicket(ticketID)
 	cli.AssertContains(t, content, "status: in_progress")
 }
+
+func Test_Start_Multiple_Tickets_Prints_Only_Status_Lines_When_Invoked_With_Several_IDs(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	id1 := c.MustRun("create", "First")
+	id2 := c.MustRun("create", "Second")
+
+	stdout := c.MustRun("start", id1, id2)
+
+	if got, want := stdout, "Started "+id1+"\nStarted "+id2; got != want {
+		t.Fatalf("stdout=%q, want=%q", got, want)
+	}
+
+	cli.AssertContains(t, c.ReadTicket(id1), "status: in_progress")
+	cli.AssertContains(t, c.ReadTicket(id2), "status: in_progress")
+}
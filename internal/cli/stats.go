@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/calvinalkan/agent-task/internal/ticket"
+
+	flag "github.com/spf13/pflag"
+)
+
+// statsTypes lists ticket types in display order.
+var statsTypes = []string{ticket.TypeBug, ticket.TypeFeature, ticket.TypeTask, ticket.TypeEpic, ticket.TypeChore}
+
+// StatsCmd returns the stats command.
+func StatsCmd(cfg *ticket.Config) *Command {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	fs.Bool("json", false, "Output as JSON")
+
+	return &Command{
+		Flags: fs,
+		Usage: "stats [flags]",
+		Short: "Show ticket counts by status, type, priority",
+		Long: `Show ticket counts grouped by status, type and priority, plus the oldest
+open ticket. Computed from the cache, without reading ticket files.`,
+		Exec: func(_ context.Context, io *IO, _ []string) error {
+			jsonOutput, _ := fs.GetBool("json")
+
+			return execStats(io, cfg, jsonOutput, time.Now())
+		},
+	}
+}
+
+// ticketStats is the aggregate computed by the stats command.
+type ticketStats struct {
+	Total      int            `json:"total"`
+	ByStatus   map[string]int `json:"by_status"`
+	ByType     map[string]int `json:"by_type"`
+	ByPriority map[string]int `json:"by_priority"`
+	OldestOpen *oldestOpen    `json:"oldest_open,omitempty"`
+}
+
+// oldestOpen is the open ticket with the earliest created timestamp.
+type oldestOpen struct {
+	ID         string `json:"id"`
+	Created    string `json:"created"`
+	AgeSeconds int64  `json:"age_seconds"`
+}
+
+func execStats(io *IO, cfg *ticket.Config, jsonOutput bool, now time.Time) error {
+	summaries, err := listTickets(io, cfg, &ticket.ListTicketsOptions{Limit: 0})
+	if err != nil {
+		return err
+	}
+
+	stats := computeStats(summaries, &cfg.Workflow, now)
+
+	if jsonOutput {
+		data, marshalErr := json.Marshal(stats)
+		if marshalErr != nil {
+			return fmt.Errorf("marshal json: %w", marshalErr)
+		}
+
+		io.Println(string(data))
+
+		return nil
+	}
+
+	io.Printf("%s", formatStats(stats, &cfg.Workflow))
+
+	return nil
+}
+
+func computeStats(summaries []*ticket.Summary, workflow *ticket.Workflow, now time.Time) *ticketStats {
+	stats := &ticketStats{
+		Total:      len(summaries),
+		ByStatus:   make(map[string]int),
+		ByType:     make(map[string]int),
+		ByPriority: make(map[string]int),
+	}
+
+	// Report every known group, even when empty, so output is stable.
+	for _, status := range workflow.Statuses {
+		stats.ByStatus[status] = 0
+	}
+
+	for _, ticketType := range statsTypes {
+		stats.ByType[ticketType] = 0
+	}
+
+	for priority := ticket.MinPriority; priority <= ticket.MaxPriority; priority++ {
+		stats.ByPriority[strconv.Itoa(priority)] = 0
+	}
+
+	var oldestCreated time.Time
+
+	for _, summary := range summaries {
+		stats.ByStatus[summary.Status]++
+		stats.ByType[summary.Type]++
+		stats.ByPriority[strconv.Itoa(summary.Priority)]++
+
+		if summary.Status != ticket.StatusOpen {
+			continue
+		}
+
+		created, parseErr := time.Parse(time.RFC3339, summary.Created)
+		if parseErr != nil {
+			continue
+		}
+
+		if stats.OldestOpen == nil || created.Before(oldestCreated) {
+			oldestCreated = created
+			stats.OldestOpen = &oldestOpen{
+				ID:         summary.ID,
+				Created:    summary.Created,
+				AgeSeconds: int64(now.Sub(created).Seconds()),
+			}
+		}
+	}
+
+	return stats
+}
+
+func formatStats(stats *ticketStats, workflow *ticket.Workflow) string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "Total: %d\n", stats.Total)
+
+	writeGroup := func(title string, keys []string, counts map[string]int, label func(string) string) {
+		fmt.Fprintf(&builder, "\n%s\n", title)
+
+		for _, key := range keys {
+			fmt.Fprintf(&builder, "  %-12s %6d\n", label(key), counts[key])
+		}
+	}
+
+	identity := func(key string) string { return key }
+
+	writeGroup("Status", orderedKeys(stats.ByStatus, workflow.Statuses), stats.ByStatus, identity)
+	writeGroup("Type", orderedKeys(stats.ByType, statsTypes), stats.ByType, identity)
+	writeGroup("Priority", orderedKeys(stats.ByPriority, nil), stats.ByPriority, func(key string) string {
+		return "P" + key
+	})
+
+	if stats.OldestOpen != nil {
+		age := time.Duration(stats.OldestOpen.AgeSeconds) * time.Second
+		fmt.Fprintf(&builder, "\nOldest open: %s (%s, created %s)\n",
+			stats.OldestOpen.ID, formatAge(age), stats.OldestOpen.Created)
+	}
+
+	return builder.String()
+}
+
+// orderedKeys returns the keys of counts: first those in order, then any
+// others sorted.
+func orderedKeys(counts map[string]int, order []string) []string {
+	keys := slices.Clone(order)
+
+	var extra []string
+
+	for key := range counts {
+		if !slices.Contains(order, key) {
+			extra = append(extra, key)
+		}
+	}
+
+	slices.Sort(extra)
+
+	return append(keys, extra...)
+}
+
+// formatAge formats a duration as whole days, or hours under a day.
+func formatAge(age time.Duration) string {
+	const day = 24 * time.Hour
+
+	if age < day {
+		return strconv.Itoa(int(age/time.Hour)) + "h"
+	}
+
+	return strconv.Itoa(int(age/day)) + "d"
+}
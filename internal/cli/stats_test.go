@@ -0,0 +1,73 @@
+package cli_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/calvinalkan/agent-task/internal/cli"
+)
+
+func Test_Stats_Prints_Counts_By_Group_When_Invoked(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	oldestID := c.MustRun("create", "Bug one", "-t", "bug", "-p", "1")
+	c.MustRun("create", "Bug two", "-t", "bug")
+	startedID := c.MustRun("create", "Feature", "-t", "feature")
+	c.MustRun("start", startedID)
+
+	stdout := c.MustRun("stats")
+
+	cli.AssertContains(t, stdout, "Total: 3")
+	cli.AssertContains(t, stdout, "  open              2")
+	cli.AssertContains(t, stdout, "  in_progress       1")
+	cli.AssertContains(t, stdout, "  closed            0")
+	cli.AssertContains(t, stdout, "  bug               2")
+	cli.AssertContains(t, stdout, "  chore             0")
+	cli.AssertContains(t, stdout, "  P1                1")
+	cli.AssertContains(t, stdout, "  P2                2")
+	cli.AssertContains(t, stdout, "Oldest open: "+oldestID+" (0h, created ")
+}
+
+func Test_Stats_Outputs_JSON_When_Flag_Set(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketID := c.MustRun("create", "Only", "-t", "chore", "-p", "4")
+
+	stdout := c.MustRun("stats", "--json")
+
+	var stats struct {
+		Total      int            `json:"total"`
+		ByStatus   map[string]int `json:"by_status"`
+		ByType     map[string]int `json:"by_type"`
+		ByPriority map[string]int `json:"by_priority"`
+		OldestOpen *struct {
+			ID string `json:"id"`
+		} `json:"oldest_open"`
+	}
+
+	err := json.Unmarshal([]byte(stdout), &stats)
+	if err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+
+	if stats.Total != 1 || stats.ByStatus["open"] != 1 || stats.ByType["chore"] != 1 || stats.ByPriority["4"] != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	if stats.OldestOpen == nil || stats.OldestOpen.ID != ticketID {
+		t.Fatalf("oldest_open=%+v, want id %s", stats.OldestOpen, ticketID)
+	}
+}
+
+func Test_Stats_Omits_Oldest_Open_When_No_Open_Tickets(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+
+	stdout := c.MustRun("stats")
+
+	cli.AssertContains(t, stdout, "Total: 0")
+	cli.AssertNotContains(t, stdout, "Oldest open")
+}
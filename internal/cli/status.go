@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/calvinalkan/agent-task/internal/ticket"
+
+	flag "github.com/spf13/pflag"
+)
+
+var (
+	errStatusRequired  = errors.New("status is required")
+	errStatusUnchanged = errors.New("ticket already has status")
+)
+
+// StatusCmd returns the status command.
+func StatusCmd(cfg *ticket.Config) *Command {
+	return &Command{
+		Flags: flag.NewFlagSet("status", flag.ContinueOnError),
+		Usage: "status <id> <status>",
+		Short: "Move ticket to a workflow status",
+		Long: `Move a ticket to any status of the workflow, e.g. a custom "review" status.
+
+The workflow is read from ` + ticket.WorkflowFileName + ` in the ticket directory.
+Without it, the default workflow open -> in_progress -> closed -> open applies.
+The transition must be allowed by the workflow; the error names the allowed
+next statuses otherwise.
+
+The requirements of start, close and reopen still apply:
+  - in_progress: blockers must be closed, parent must be started
+  - closed: all child tickets must be closed first
+  - leaving closed: parent ticket must not be closed`,
+		Exec: func(_ context.Context, io *IO, args []string) error {
+			return execStatus(io, cfg, args)
+		},
+	}
+}
+
+func execStatus(io *IO, cfg *ticket.Config, args []string) error {
+	if len(args) == 0 {
+		return ticket.ErrIDRequired
+	}
+
+	ticketID := args[0]
+
+	if len(args) < 2 || args[1] == "" {
+		return errStatusRequired
+	}
+
+	target := args[1]
+
+	if !cfg.Workflow.HasStatus(target) {
+		return fmt.Errorf("%w: %s (valid: %s)", errInvalidStatus, target, strings.Join(cfg.Workflow.Statuses, ", "))
+	}
+
+	if !ticket.Exists(cfg.TicketDirAbs, ticketID) {
+		return fmt.Errorf("%w: %s", ticket.ErrTicketNotFound, ticketID)
+	}
+
+	path := ticket.Path(cfg.TicketDirAbs, ticketID)
+
+	summary, parseErr := ticket.ParseTicketFrontmatter(path, &cfg.Workflow)
+	if parseErr != nil {
+		return fmt.Errorf("parse frontmatter: %w", parseErr)
+	}
+
+	transitionErr := checkStatusTransition(&cfg.Workflow, summary.Status, target)
+	if transitionErr != nil {
+		return transitionErr
+	}
+
+	requirementErr := checkStatusRequirements(&cfg.Workflow, cfg.TicketDirAbs, &summary, target)
+	if requirementErr != nil {
+		return requirementErr
+	}
+
+	var original []byte
+
+	err := ticket.WithTicketLock(path, func(content []byte) ([]byte, error) {
+		status, statusErr := ticket.GetStatusFromContent(content)
+		if statusErr != nil {
+			return nil, fmt.Errorf("reading status: %w", statusErr)
+		}
+
+		transitionErr := checkStatusTransition(&cfg.Workflow, status, target)
+		if transitionErr != nil {
+			return nil, transitionErr
+		}
+
+		original = content
+
+		newContent, updateErr := ticket.UpdateStatusInContent(content, target)
+		if updateErr != nil {
+			return nil, fmt.Errorf("updating status: %w", updateErr)
+		}
+
+		if target == ticket.StatusClosed {
+			closedTime := time.Now().UTC().Format(time.RFC3339)
+
+			return ticket.AddFieldToContent(newContent, "closed", closedTime)
+		}
+
+		if status == ticket.StatusClosed {
+			result := ticket.RemoveFieldFromContent(newContent, "closed")
+			if result != nil {
+				return result, nil
+			}
+		}
+
+		return newContent, nil
+	})
+	if err != nil {
+		return fmt.Errorf("update ticket: %w", err)
+	}
+
+	undoErr := recordUndo(cfg, "status", map[string][]byte{ticketID: original}, nil)
+	if undoErr != nil {
+		return undoErr
+	}
+
+	cacheErr := updateCacheForTickets(cfg, []string{ticketID})
+	if cacheErr != nil {
+		return cacheErr
+	}
+
+	io.Println("Set", ticketID, "to", target)
+
+	return nil
+}
+
+func checkStatusTransition(workflow *ticket.Workflow, from, to string) error {
+	if from == to {
+		return fmt.Errorf("%w: %s", errStatusUnchanged, to)
+	}
+
+	return workflow.CheckTransition(from, to)
+}
+
+// checkStatusRequirements applies the rules of start, close and reopen to a
+// transition into (or out of) the corresponding built-in status.
+func checkStatusRequirements(workflow *ticket.Workflow, ticketDir string, summary *ticket.Summary, target string) error {
+	switch target {
+	case ticket.StatusInProgress:
+		return checkStartDependencies(workflow, ticketDir, summary)
+	case ticket.StatusClosed:
+		openChildren, childErr := ticket.FindOpenChildren(ticketDir, summary.ID)
+		if childErr != nil {
+			return fmt.Errorf("checking children: %w", childErr)
+		}
+
+		if len(openChildren) > 0 {
+			return fmt.Errorf("%w: %s", ticket.ErrHasOpenChildren, openChildren[0])
+		}
+	}
+
+	if summary.Status != ticket.StatusClosed || summary.Parent == "" {
+		return nil
+	}
+
+	parent, err := readSummary(workflow, ticketDir, summary.Parent, "parent")
+	if err != nil {
+		return err
+	}
+
+	if parent.Status == ticket.StatusClosed {
+		return fmt.Errorf("%w: %s", ticket.ErrParentClosed, summary.Parent)
+	}
+
+	return nil
+}
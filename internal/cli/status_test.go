@@ -0,0 +1,124 @@
+package cli_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/calvinalkan/agent-task/internal/cli"
+)
+
+const reviewWorkflow = `{
+	// Tickets go through review before they can be closed.
+	"statuses": ["open", "in_progress", "review", "closed"],
+	"transitions": {
+		"open": ["in_progress"],
+		"in_progress": ["review"],
+		"review": ["in_progress", "closed"],
+		"closed": ["open"],
+	},
+}`
+
+func Test_Status_Moves_Ticket_Through_Custom_Status_When_Workflow_Allows(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	writeFile(t, filepath.Join(c.TicketDir(), ".workflow.json"), reviewWorkflow)
+
+	ticketID := c.MustRun("create", "Needs review")
+	c.MustRun("start", ticketID)
+
+	stdout := c.MustRun("status", ticketID, "review")
+	cli.AssertContains(t, stdout, "Set "+ticketID+" to review")
+	cli.AssertContains(t, c.ReadTicket(ticketID), "status: review")
+
+	lsOut := c.MustRun("ls", "--status", "review")
+	cli.AssertTicketListed(t, lsOut, ticketID)
+	cli.AssertContains(t, lsOut, "[review]")
+
+	c.MustRun("close", ticketID)
+	cli.AssertContains(t, c.ReadTicket(ticketID), "status: closed")
+	cli.AssertContains(t, c.ReadTicket(ticketID), "closed: ")
+}
+
+func Test_Status_Names_Allowed_Next_Statuses_When_Transition_Not_Allowed(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	writeFile(t, filepath.Join(c.TicketDir(), ".workflow.json"), reviewWorkflow)
+
+	ticketID := c.MustRun("create", "Skip review")
+	c.MustRun("start", ticketID)
+
+	stderr := c.MustFail("status", ticketID, "closed")
+	cli.AssertContains(t, stderr, "status transition not allowed: in_progress -> closed (allowed next: review)")
+
+	stderr = c.MustFail("close", ticketID)
+	cli.AssertContains(t, stderr, "ticket is not in_progress (current status: in_progress, allowed next: review)")
+}
+
+func Test_Status_Returns_Error_When_Status_Not_In_Workflow(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketID := c.MustRun("create", "Ticket")
+
+	stderr := c.MustFail("status", ticketID, "review")
+	cli.AssertContains(t, stderr, "invalid status: review (valid: open, in_progress, closed)")
+
+	stderr = c.MustFail("status", ticketID)
+	cli.AssertContains(t, stderr, "status is required")
+
+	stderr = c.MustFail("status", ticketID, "open")
+	cli.AssertContains(t, stderr, "ticket already has status: open")
+}
+
+func Test_Status_Applies_Start_Requirements_When_Moving_To_In_Progress(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	blockerID := c.MustRun("create", "Blocker")
+	ticketID := c.MustRun("create", "Blocked", "--blocked-by", blockerID)
+
+	stderr := c.MustFail("status", ticketID, "in_progress")
+	cli.AssertContains(t, stderr, "blocked by open blocker: "+blockerID)
+}
+
+func Test_Status_Clears_Closed_Timestamp_When_Leaving_Closed(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketID := c.MustRun("create", "Ticket")
+	c.MustRun("start", ticketID)
+	c.MustRun("close", ticketID)
+
+	c.MustRun("status", ticketID, "open")
+
+	content := c.ReadTicket(ticketID)
+	cli.AssertContains(t, content, "status: open")
+	cli.AssertNotContains(t, content, "closed: ")
+}
+
+func Test_Start_Names_Allowed_Next_Statuses_When_Ticket_Not_Open(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketID := c.MustRun("create", "Ticket")
+	c.MustRun("start", ticketID)
+
+	stderr := c.MustFail("start", ticketID)
+	cli.AssertContains(t, stderr, "ticket is not open (current status: in_progress, allowed next: closed)")
+}
+
+func Test_Workflow_Fails_Config_Load_When_Workflow_Is_Invalid(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	writeFile(t, filepath.Join(c.TicketDir(), ".workflow.json"), `{
+		"statuses": ["open", "in_progress", "closed"],
+		"transitions": {"open": ["review"]}
+	}`)
+
+	stderr := c.MustFail("ls")
+	cli.AssertContains(t, stderr, "invalid workflow file")
+	cli.AssertContains(t, stderr, `unknown status "review"`)
+}
@@ -51,6 +51,8 @@ func execUnblock(io *IO, cfg *ticket.Config, args []string) error {
 
 	blockerID := args[1]
 
+	var original []byte
+
 	err := ticket.WithTicketLock(path, func(content []byte) ([]byte, error) {
 		blockedBy, readErr := ticket.GetBlockedByFromContent(content)
 		if readErr != nil {
@@ -63,6 +65,7 @@ func execUnblock(io *IO, cfg *ticket.Config, args []string) error {
 		}
 
 		blockedBy = slices.Delete(blockedBy, idx, idx+1)
+		original = content
 
 		return ticket.UpdateBlockedByInContent(content, blockedBy)
 	})
@@ -70,12 +73,17 @@ func execUnblock(io *IO, cfg *ticket.Config, args []string) error {
 		return fmt.Errorf("update ticket: %w", err)
 	}
 
-	summary, parseErr := ticket.ParseTicketFrontmatter(path)
+	undoErr := recordUndo(cfg, "unblock", map[string][]byte{ticketID: original}, nil)
+	if undoErr != nil {
+		return undoErr
+	}
+
+	summary, parseErr := ticket.ParseTicketFrontmatter(path, &cfg.Workflow)
 	if parseErr != nil {
 		return fmt.Errorf("parse frontmatter: %w", parseErr)
 	}
 
-	cacheErr := ticket.UpdateCacheAfterTicketWrite(cfg.TicketDirAbs, ticketID+".md", &summary)
+	cacheErr := ticket.UpdateCacheAfterTicketWrite(cfg.TicketDirAbs, ticketID+".md", &summary, &cfg.Workflow)
 	if cacheErr != nil {
 		return fmt.Errorf("update cache: %w", cacheErr)
 	}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/calvinalkan/agent-task/internal/ticket"
+
+	flag "github.com/spf13/pflag"
+)
+
+// UndoCmd returns the undo command.
+func UndoCmd(cfg *ticket.Config) *Command {
+	fs := flag.NewFlagSet("undo", flag.ContinueOnError)
+	fs.Bool("list", false, "List recorded operations, newest first")
+	fs.Bool("drop", false, "Discard the last recorded operation without restoring anything")
+
+	return &Command{
+		Flags: fs,
+		Usage: "undo [flags]",
+		Short: "Undo the last state-changing command",
+		Long: fmt.Sprintf(`Restore the tickets changed by the most recent command that wrote tickets
+(create, start, close, reopen, status, block, unblock, assign, label, mv,
+edit or repair) to their previous content.
+
+The last %d operations are kept; use --list to show them. Undo fails
+without changing anything if a ticket was modified after the operation.
+Edits in an editor that detaches (zed) cannot be recorded; undo stops at
+them. Use --drop to discard such an operation and undo the ones before it.`, ticket.JournalMaxOps),
+		Exec: func(_ context.Context, io *IO, _ []string) error {
+			list, _ := fs.GetBool("list")
+			if list {
+				return execUndoList(io, cfg)
+			}
+
+			drop, _ := fs.GetBool("drop")
+			if drop {
+				return execUndoDrop(io, cfg)
+			}
+
+			return execUndo(io, cfg)
+		},
+	}
+}
+
+func execUndo(io *IO, cfg *ticket.Config) error {
+	op, err := ticket.UndoLastOp(cfg.TicketDirAbs)
+	if errors.Is(err, ticket.ErrUndoUntracked) {
+		return fmt.Errorf("undo: %w (use --drop to discard it)", err)
+	}
+
+	if err != nil {
+		return fmt.Errorf("undo: %w", err)
+	}
+
+	var restored []string
+
+	for _, jt := range op.Tickets {
+		if jt.Before != nil {
+			restored = append(restored, jt.ID)
+
+			continue
+		}
+
+		cacheErr := ticket.DeleteCacheEntry(cfg.TicketDirAbs, jt.ID+".md")
+		if cacheErr != nil {
+			return fmt.Errorf("update cache: %w", cacheErr)
+		}
+	}
+
+	cacheErr := updateCacheForTickets(cfg, restored)
+	if cacheErr != nil {
+		return cacheErr
+	}
+
+	io.Println("Undid", op.Command)
+
+	for _, jt := range op.Tickets {
+		if jt.Before == nil {
+			io.Println("Removed", jt.ID)
+		} else {
+			io.Println("Restored", jt.ID)
+		}
+	}
+
+	return nil
+}
+
+func execUndoDrop(io *IO, cfg *ticket.Config) error {
+	op, err := ticket.DropLastOp(cfg.TicketDirAbs)
+	if err != nil {
+		return fmt.Errorf("undo: %w", err)
+	}
+
+	io.Println("Dropped", op.Command)
+
+	return nil
+}
+
+func execUndoList(io *IO, cfg *ticket.Config) error {
+	ops, err := ticket.ReadJournal(cfg.TicketDirAbs)
+	if err != nil {
+		return fmt.Errorf("undo: %w", err)
+	}
+
+	if len(ops) == 0 {
+		io.Println("Nothing to undo")
+
+		return nil
+	}
+
+	for _, op := range slices.Backward(ops) {
+		ids := make([]string, 0, len(op.Tickets))
+		for _, jt := range op.Tickets {
+			ids = append(ids, jt.ID)
+		}
+
+		line := fmt.Sprintf("%s  %-7s %s", op.Time.Format(time.RFC3339), op.Command, strings.Join(ids, ", "))
+		if op.Untracked {
+			line += " (untracked)"
+		}
+
+		if op.Pending {
+			line += " (incomplete)"
+		}
+
+		io.Println(line)
+	}
+
+	return nil
+}
+
+// recordUndo records a command in the undo journal. before maps each existing
+// ticket the command may have written to its prior content; created lists the
+// tickets the command created.
+func recordUndo(cfg *ticket.Config, command string, before map[string][]byte, created []string) error {
+	err := ticket.RecordJournalOp(cfg.TicketDirAbs, command, before, created)
+	if err != nil {
+		return fmt.Errorf("record undo: %w", err)
+	}
+
+	return nil
+}
+
+// recordUntracked records a command whose writes to ticketID cannot be
+// journaled, so undo stops at it instead of undoing older operations.
+func recordUntracked(cfg *ticket.Config, command, ticketID string) error {
+	err := ticket.RecordUntrackedOp(cfg.TicketDirAbs, command, []string{ticketID})
+	if err != nil {
+		return fmt.Errorf("record undo: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package cli_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/calvinalkan/agent-task/internal/cli"
+	"github.com/calvinalkan/agent-task/internal/ticket"
+)
+
+func Test_Undo_Restores_Status_When_Last_Op_Was_Close(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketID := c.MustRun("create", "Ticket")
+	c.MustRun("start", ticketID)
+	c.MustRun("close", ticketID)
+
+	stdout := c.MustRun("undo")
+	cli.AssertContains(t, stdout, "Undid close")
+	cli.AssertContains(t, stdout, "Restored "+ticketID)
+
+	content := c.ReadTicket(ticketID)
+	cli.AssertContains(t, content, "status: in_progress")
+	cli.AssertNotContains(t, content, "closed:")
+
+	cli.AssertContains(t, c.MustRun("ls", "--status", "in_progress"), ticketID)
+}
+
+func Test_Undo_Removes_Ticket_When_Last_Op_Was_Create(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketID := c.MustRun("create", "Ticket")
+
+	stdout := c.MustRun("undo")
+	cli.AssertContains(t, stdout, "Removed "+ticketID)
+
+	_, err := os.Stat(filepath.Join(c.TicketDir(), ticketID+".md"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("ticket file still exists: err=%v", err)
+	}
+
+	stderr := c.MustFail("undo")
+	cli.AssertContains(t, stderr, "nothing to undo")
+}
+
+func Test_Undo_Reverts_Rename_When_Last_Op_Was_Mv(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketID := c.MustRun("create", "Ticket")
+	blockedID := c.MustRun("create", "Blocked")
+	c.MustRun("block", blockedID, ticketID)
+
+	c.MustRun("mv", ticketID, "renamed")
+	c.MustRun("undo")
+
+	cli.AssertContains(t, c.ReadTicket(ticketID), "id: "+ticketID)
+	cli.AssertContains(t, c.ReadTicket(blockedID), "blocked-by: ["+ticketID+"]")
+
+	_, err := os.Stat(filepath.Join(c.TicketDir(), "renamed.md"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("renamed ticket still exists: err=%v", err)
+	}
+}
+
+func Test_Undo_Fails_When_Ticket_Changed_Since_Op(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketID := c.MustRun("create", "Ticket")
+	c.MustRun("start", ticketID)
+	c.WriteTicket(ticketID, c.ReadTicket(ticketID)+"\nEdited by hand.\n")
+
+	stderr := c.MustFail("undo")
+	cli.AssertContains(t, stderr, "ticket changed since the operation")
+	cli.AssertContains(t, c.ReadTicket(ticketID), "status: in_progress")
+}
+
+func Test_Undo_List_Shows_Ops_Newest_First_When_Invoked(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	cli.AssertContains(t, c.MustRun("undo", "--list"), "Nothing to undo")
+
+	ticketID := c.MustRun("create", "Ticket")
+	c.MustRun("start", ticketID)
+
+	stdout := c.MustRun("undo", "--list")
+	cli.AssertContains(t, stdout, "start   "+ticketID+"\n")
+	cli.AssertContains(t, stdout, "create  "+ticketID)
+
+	if start, create := strings.Index(stdout, "start"), strings.Index(stdout, "create"); start > create {
+		t.Fatalf("want start listed before create:\n%s", stdout)
+	}
+}
+
+func Test_Undo_Keeps_Ticket_When_Last_Assign_Was_No_Op(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketID := c.MustRun("create", "Keep me")
+	c.MustRun("assign", ticketID, "alice")
+	c.MustRun("assign", ticketID, "alice")
+
+	stdout := c.MustRun("undo")
+	cli.AssertContains(t, stdout, "Undid assign")
+	cli.AssertContains(t, stdout, "Restored "+ticketID)
+	cli.AssertNotContains(t, c.ReadTicket(ticketID), "assignee: alice")
+}
+
+func Test_Undo_Reverts_Status_Change_When_Last_Op_Was_Status(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	firstID := c.MustRun("create", "First")
+	secondID := c.MustRun("create", "Second")
+	c.MustRun("status", firstID, "in_progress")
+
+	stdout := c.MustRun("undo")
+	cli.AssertContains(t, stdout, "Undid status")
+	cli.AssertContains(t, c.ReadTicket(firstID), "status: open")
+	cli.AssertContains(t, c.ReadTicket(secondID), "# Second")
+}
+
+func Test_Undo_Reverts_Blockers_When_Last_Ops_Were_Block_And_Unblock(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketID := c.MustRun("create", "Ticket")
+	blockerID := c.MustRun("create", "Blocker")
+	c.MustRun("block", ticketID, blockerID)
+	c.MustRun("unblock", ticketID, blockerID)
+
+	cli.AssertContains(t, c.MustRun("undo"), "Undid unblock")
+	cli.AssertContains(t, c.ReadTicket(ticketID), "blocked-by: ["+blockerID+"]")
+
+	cli.AssertContains(t, c.MustRun("undo"), "Undid block")
+	cli.AssertContains(t, c.ReadTicket(ticketID), "blocked-by: []")
+}
+
+func Test_Undo_Drop_Discards_Untracked_Op_When_It_Blocks_Undo(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketID := c.MustRun("create", "Ticket")
+	c.MustRun("start", ticketID)
+
+	err := ticket.RecordUntrackedOp(c.TicketDir(), "edit", []string{ticketID})
+	if err != nil {
+		t.Fatalf("record untracked: %v", err)
+	}
+
+	cli.AssertContains(t, c.MustFail("undo"), "--drop")
+	cli.AssertContains(t, c.MustRun("undo", "--drop"), "Dropped edit")
+	cli.AssertContains(t, c.MustRun("undo"), "Undid start")
+	cli.AssertContains(t, c.ReadTicket(ticketID), "status: open")
+}
@@ -0,0 +1,288 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/calvinalkan/agent-task/internal/ticket"
+
+	flag "github.com/spf13/pflag"
+)
+
+const (
+	defaultWatchInterval = 500 * time.Millisecond
+	defaultWatchDebounce = 200 * time.Millisecond
+)
+
+var errInvalidWatchInterval = errors.New("--interval must be positive")
+
+// WatchCmd returns the watch command.
+func WatchCmd(cfg *ticket.Config) *Command {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	fs.Duration("interval", defaultWatchInterval, "How often to poll the ticket directory")
+	fs.Duration("debounce", defaultWatchDebounce, "Wait until a ticket is unchanged this long before reporting")
+	fs.Int("max-events", 0, "Exit after N change events (0 = until interrupted)")
+
+	return &Command{
+		Flags: fs,
+		Usage: "watch [flags]",
+		Short: "Print ticket changes live",
+		Long: `Watch the ticket directory and print one line per ticket change until
+interrupted (Ctrl-C).
+
+Output:
+  15:04:05 <id> created: [open] Title
+  15:04:05 <id> changed: open→closed
+  15:04:05 <id> changed: priority 2→1, assignee -→alice
+  15:04:05 <id> deleted
+
+Rapid successive writes to the same ticket are reported as one change.`,
+		Exec: func(ctx context.Context, io *IO, _ []string) error {
+			interval, _ := fs.GetDuration("interval")
+			debounce, _ := fs.GetDuration("debounce")
+			maxEvents, _ := fs.GetInt("max-events")
+
+			return execWatch(ctx, io, cfg, interval, debounce, maxEvents)
+		},
+	}
+}
+
+func execWatch(ctx context.Context, io *IO, cfg *ticket.Config, interval, debounce time.Duration, maxEvents int) error {
+	if interval <= 0 {
+		return errInvalidWatchInterval
+	}
+
+	watcher := newTicketWatcher(cfg.TicketDirAbs, &cfg.Workflow, debounce)
+
+	err := watcher.prime(time.Now())
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	events := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			scanErr := watcher.scan(now)
+			if scanErr != nil {
+				return scanErr
+			}
+
+			for _, event := range watcher.flush(now) {
+				if event.err != nil {
+					io.ErrPrintln("warning:", event.id+":", event.err)
+
+					continue
+				}
+
+				io.Println(now.Format(time.TimeOnly), event.id, event.text)
+
+				events++
+				if maxEvents > 0 && events >= maxEvents {
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// fileStamp identifies a version of a ticket file without reading it.
+type fileStamp struct {
+	mtime time.Time
+	size  int64
+}
+
+// watchEvent is a single reported ticket change.
+type watchEvent struct {
+	id   string
+	text string // e.g. "changed: open→closed"
+	err  error  // set if the changed ticket could not be parsed
+}
+
+// ticketWatcher polls a ticket directory and turns file changes into events.
+type ticketWatcher struct {
+	dir      string
+	workflow *ticket.Workflow
+	debounce time.Duration
+	stamps   map[string]fileStamp       // by filename, as of the last scan
+	reported map[string]*ticket.Summary // by filename, as of the last event
+	pending  map[string]time.Time       // filename -> when it last changed
+}
+
+func newTicketWatcher(dir string, workflow *ticket.Workflow, debounce time.Duration) *ticketWatcher {
+	return &ticketWatcher{
+		dir:      dir,
+		workflow: workflow,
+		debounce: debounce,
+		stamps:   make(map[string]fileStamp),
+		reported: make(map[string]*ticket.Summary),
+		pending:  make(map[string]time.Time),
+	}
+}
+
+// scan stats all ticket files and marks new, changed and deleted ones as
+// pending. A missing ticket directory is treated as empty.
+func (w *ticketWatcher) scan(now time.Time) error {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("read ticket dir: %w", err)
+	}
+
+	seen := make(map[string]bool, len(entries))
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".md") {
+			continue
+		}
+
+		info, infoErr := entry.Info()
+		if infoErr != nil {
+			continue // deleted between ReadDir and Info; next scan reports it
+		}
+
+		seen[name] = true
+		stamp := fileStamp{mtime: info.ModTime(), size: info.Size()}
+
+		if old, ok := w.stamps[name]; !ok || old != stamp {
+			w.stamps[name] = stamp
+			w.pending[name] = now
+		}
+	}
+
+	for name := range w.stamps {
+		if !seen[name] {
+			delete(w.stamps, name)
+			w.pending[name] = now
+		}
+	}
+
+	return nil
+}
+
+// prime records the current state of all tickets without reporting it.
+func (w *ticketWatcher) prime(now time.Time) error {
+	err := w.scan(now)
+	if err != nil {
+		return err
+	}
+
+	for name := range w.pending {
+		summary, parseErr := ticket.ParseTicketFrontmatter(filepath.Join(w.dir, name), w.workflow)
+		if parseErr == nil {
+			w.reported[name] = &summary
+		}
+
+		delete(w.pending, name)
+	}
+
+	return nil
+}
+
+// flush returns events for pending files that have been stable for the
+// debounce period, sorted by ticket ID.
+func (w *ticketWatcher) flush(now time.Time) []watchEvent {
+	var names []string
+
+	for name, changed := range w.pending {
+		if now.Sub(changed) >= w.debounce {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	events := make([]watchEvent, 0, len(names))
+
+	for _, name := range names {
+		delete(w.pending, name)
+
+		id := strings.TrimSuffix(name, ".md")
+		old := w.reported[name]
+
+		if _, exists := w.stamps[name]; !exists {
+			delete(w.reported, name)
+
+			if old != nil {
+				events = append(events, watchEvent{id: id, text: "deleted"})
+			}
+
+			continue
+		}
+
+		summary, err := ticket.ParseTicketFrontmatter(filepath.Join(w.dir, name), w.workflow)
+		if err != nil {
+			events = append(events, watchEvent{id: id, err: err})
+
+			continue
+		}
+
+		w.reported[name] = &summary
+
+		if old == nil {
+			events = append(events, watchEvent{id: id, text: "created: [" + summary.Status + "] " + summary.Title})
+
+			continue
+		}
+
+		events = append(events, watchEvent{id: id, text: "changed: " + describeTicketChange(old, &summary)})
+	}
+
+	return events
+}
+
+// describeTicketChange lists the frontmatter fields that differ between two
+// versions of a ticket. A status change is printed bare ("open→closed").
+func describeTicketChange(old, cur *ticket.Summary) string {
+	var parts []string
+
+	if old.Status != cur.Status {
+		parts = append(parts, old.Status+"→"+cur.Status)
+	}
+
+	fields := []struct {
+		name     string
+		old, cur string
+	}{
+		{"title", strconv.Quote(old.Title), strconv.Quote(cur.Title)},
+		{"type", old.Type, cur.Type},
+		{"priority", strconv.Itoa(old.Priority), strconv.Itoa(cur.Priority)},
+		{"assignee", orDash(old.Assignee), orDash(cur.Assignee)},
+		{"parent", orDash(old.Parent), orDash(cur.Parent)},
+		{"blocked-by", "[" + strings.Join(old.BlockedBy, ", ") + "]", "[" + strings.Join(cur.BlockedBy, ", ") + "]"},
+		{"labels", "[" + strings.Join(old.Labels, ", ") + "]", "[" + strings.Join(cur.Labels, ", ") + "]"},
+	}
+
+	for _, field := range fields {
+		if field.old != field.cur {
+			parts = append(parts, field.name+" "+field.old+"→"+field.cur)
+		}
+	}
+
+	if len(parts) == 0 {
+		return "content"
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
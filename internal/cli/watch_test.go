@@ -0,0 +1,86 @@
+package cli_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/calvinalkan/agent-task/internal/cli"
+)
+
+func Test_Watch_Prints_Status_Change_When_Ticket_Transitions(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	ticketID := c.MustRun("create", "Watched")
+
+	stdout := runWatchUntilDone(t, c, func(i int) {
+		// Alternate statuses until the watcher has picked up a change.
+		if i%2 == 0 {
+			c.MustRun("start", ticketID)
+		} else {
+			c.MustRun("status", ticketID, "open")
+		}
+	})
+
+	cli.AssertContains(t, stdout, ticketID+" changed: ")
+	cli.AssertContains(t, stdout, "→")
+}
+
+func Test_Watch_Prints_Created_When_Ticket_Added(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+	c.MustRun("create", "Existing")
+
+	stdout := runWatchUntilDone(t, c, func(int) {
+		c.MustRun("create", "New ticket")
+	})
+
+	cli.AssertContains(t, stdout, " created: [open] New ticket")
+	cli.AssertNotContains(t, stdout, "Existing")
+}
+
+func Test_Watch_Returns_Error_When_Interval_Not_Positive(t *testing.T) {
+	t.Parallel()
+
+	c := cli.NewCLI(t)
+
+	stderr := c.MustFail("watch", "--interval", "0s")
+	cli.AssertContains(t, stderr, "--interval must be positive")
+}
+
+// runWatchUntilDone runs "tk watch --max-events 1" and calls change
+// repeatedly until the watcher exits, since the test cannot tell when the
+// watcher has taken its initial snapshot. Returns watch's stdout.
+func runWatchUntilDone(t *testing.T, c *cli.CLI, change func(i int)) string {
+	t.Helper()
+
+	type result struct {
+		stdout, stderr string
+		code           int
+	}
+
+	done := make(chan result, 1)
+
+	go func() {
+		stdout, stderr, code := c.Run("watch", "--interval", "10ms", "--debounce", "0s", "--max-events", "1")
+		done <- result{stdout: stdout, stderr: stderr, code: code}
+	}()
+
+	deadline := time.After(10 * time.Second)
+
+	for i := 0; ; i++ {
+		select {
+		case res := <-done:
+			if res.code != 0 {
+				t.Fatalf("watch exit=%d, stderr=%s", res.code, res.stderr)
+			}
+
+			return res.stdout
+		case <-deadline:
+			t.Fatal("watch did not report a change in time")
+		case <-time.After(50 * time.Millisecond):
+			change(i)
+		}
+	}
+}
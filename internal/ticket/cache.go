@@ -20,7 +20,7 @@ import (
 // Binary cache format constants.
 const (
 	cacheMagic       = "TKC1"
-	cacheVersionNum  = 6 // Bumped for parent in index
+	cacheVersionNum  = 8 // Bumped for labels in data
 	cacheHeaderSize  = 32
 	indexEntrySize   = 68 // Was 56, added 12 for parent
 	maxFilenameLen   = 32
@@ -33,6 +33,7 @@ const (
 	statusByteOpen       = 0
 	statusByteInProgress = 1
 	statusByteClosed     = 2
+	statusByteCustom     = 3 // Workflow status; name stored in data section
 )
 
 // Type byte values for index.
@@ -218,13 +219,14 @@ func (bc *BinaryCache) GetEntryByIndex(idx int) (string, Summary) {
 
 // FilterEntriesOpts contains filter options for FilterEntries.
 type FilterEntriesOpts struct {
-	Status    int    // -1 = any, otherwise status byte (0=open,1=in_progress,2=closed)
-	Priority  int    // 0 = any, otherwise exact priority (1-4)
-	Type      int    // -1 = any, otherwise type byte (0-4)
-	Parent    string // "" = any, otherwise exact parent ID
-	RootsOnly bool   // true = only entries without parent
-	Limit     int    // 0 = no limit
-	Offset    int    // skip first N matches
+	Status     int    // -1 = any, otherwise status byte (0=open,1=in_progress,2=closed,3=custom)
+	StatusName string // with Status=3: exact custom status name ("" = any custom)
+	Priority   int    // 0 = any, otherwise exact priority (1-4)
+	Type       int    // -1 = any, otherwise type byte (0-4)
+	Parent     string // "" = any, otherwise exact parent ID
+	RootsOnly  bool   // true = only entries without parent
+	Limit      int    // 0 = no limit
+	Offset     int    // skip first N matches
 }
 
 // FilterEntries returns indices of entries matching the given filter criteria.
@@ -278,6 +280,11 @@ func (bc *BinaryCache) FilterEntriesWithOpts(opts FilterEntriesOpts) []int {
 			continue
 		}
 
+		if opts.StatusName != "" && entryStatus == statusByteCustom &&
+			bc.readDataEntry(bc.readIndexEntry(i)).Status != opts.StatusName {
+			continue
+		}
+
 		if opts.Priority != 0 && entryPriority != opts.Priority {
 			continue
 		}
@@ -453,8 +460,23 @@ func (bc *BinaryCache) readDataEntry(entry indexEntryData) Summary {
 	// Read Parent (1 byte length + string)
 	parent := readString1()
 
+	// Custom status name (1 byte length + string, empty for built-in statuses)
+	customStatus := readString1()
+
+	// Read Labels (1 byte count + length-prefixed strings)
+	labelCount := int(data[pos])
+	pos++
+
+	var labels []string
+	for range labelCount {
+		labels = append(labels, readString1())
+	}
+
 	// Status from index entry
 	status := statusByteToString(entry.status)
+	if entry.status == statusByteCustom {
+		status = customStatus
+	}
 
 	return Summary{
 		SchemaVersion: schemaVersion,
@@ -468,6 +490,7 @@ func (bc *BinaryCache) readDataEntry(entry indexEntryData) Summary {
 		Created:       created,
 		Closed:        closed,
 		Assignee:      assignee,
+		Labels:        labels,
 		Path:          path,
 	}
 }
@@ -688,6 +711,9 @@ var (
 	errTitleTooLong      = errors.New("title too long (max 65535 chars)")
 	errPathTooLong       = errors.New("path too long (max 65535 chars)")
 	errParentTooLong     = errors.New("parent too long (max 255 chars)")
+	errStatusTooLong     = errors.New("status too long (max 255 chars)")
+	errTooManyLabels     = errors.New("too many labels (max 255)")
+	errLabelTooLong      = errors.New("label too long (max 255 chars)")
 )
 
 func encodeSummaryData(summary *Summary) ([]byte, error) {
@@ -712,6 +738,25 @@ func encodeSummaryData(summary *Summary) ([]byte, error) {
 		return nil, errParentTooLong
 	}
 
+	customStatus := ""
+	if statusStringToByte(summary.Status) == statusByteCustom {
+		customStatus = summary.Status
+	}
+
+	if len(customStatus) > uint8Max {
+		return nil, errStatusTooLong
+	}
+
+	if len(summary.Labels) > uint8Max {
+		return nil, errTooManyLabels
+	}
+
+	for _, label := range summary.Labels {
+		if len(label) > uint8Max {
+			return nil, fmt.Errorf("%w: %s", errLabelTooLong, label)
+		}
+	}
+
 	// Validate 2-byte length strings
 	if len(summary.Title) > uint16Max {
 		return nil, errTitleTooLong
@@ -783,6 +828,15 @@ func encodeSummaryData(summary *Summary) ([]byte, error) {
 	// Write Parent (1 byte length + string)
 	writeString1(summary.Parent)
 
+	// Write custom status name (1 byte length + string)
+	writeString1(customStatus)
+
+	dataBuf.WriteByte(byte(len(summary.Labels)))
+
+	for _, label := range summary.Labels {
+		writeString1(label)
+	}
+
 	if dataBuf.Len() > uint16Max {
 		return nil, errEntryTooLarge
 	}
@@ -807,8 +861,10 @@ func statusStringToByte(s string) uint8 {
 		return statusByteInProgress
 	case StatusClosed:
 		return statusByteClosed
-	default:
+	case StatusOpen:
 		return statusByteOpen
+	default:
+		return statusByteCustom
 	}
 }
 
@@ -844,17 +900,25 @@ func typeByteToString(b uint8) string {
 
 // UpdateCacheEntry updates or inserts a single cache entry. Uses a lock to avoid
 // lost updates when multiple tk commands run concurrently.
-func UpdateCacheEntry(ticketDir, filename string, summary *Summary) error {
+func UpdateCacheEntry(ticketDir, filename string, summary *Summary, workflow *Workflow) error {
+	return UpdateCacheEntries(ticketDir, map[string]*Summary{filename: summary}, workflow)
+}
+
+// UpdateCacheEntries updates or inserts several cache entries (keyed by filename)
+// under a single cache lock and a single cache write. Other tickets parsed while
+// rebuilding or reconciling the cache are checked against workflow as in
+// [ParseTicketFrontmatter].
+func UpdateCacheEntries(ticketDir string, summaries map[string]*Summary, workflow *Workflow) error {
 	cachePath := filepath.Join(ticketDir, CacheFileName)
 
 	// Lock on cache file path (creates .cache.lock)
 	return WithLock(cachePath, func() error {
-		// If cache is missing or invalid, rebuild from scratch (includes this file).
+		// If cache is missing or invalid, rebuild from scratch (includes these files).
 		cache, err := LoadBinaryCache(ticketDir)
 		if err != nil {
 			if errors.Is(err, errCacheNotFound) || errors.Is(err, errVersionMismatch) || errors.Is(err, errInvalidMagic) ||
 				errors.Is(err, errFileTooSmall) || errors.Is(err, errCacheCorrupt) {
-				_, rebuildErr := buildCacheParallel(ticketDir, nil)
+				_, rebuildErr := buildCacheParallel(ticketDir, nil, workflow)
 
 				return rebuildErr
 			}
@@ -873,46 +937,57 @@ func UpdateCacheEntry(ticketDir, filename string, summary *Summary) error {
 		}
 
 		if needReconcile {
-			reconcileErr := reconcileRawCacheEntries(ticketDir, entries)
+			reconcileErr := reconcileRawCacheEntries(ticketDir, entries, workflow)
 			if reconcileErr != nil {
 				return reconcileErr
 			}
 		}
 
-		// Update the requested entry.
-		if len(filename) > maxFilenameLen {
-			return fmt.Errorf("caching ticket: %w (max %d chars): %s", errFilenameTooLong, maxFilenameLen, filename)
+		// Update the requested entries.
+		for filename, summary := range summaries {
+			entry, entryErr := rawCacheEntryFor(ticketDir, filename, summary)
+			if entryErr != nil {
+				return entryErr
+			}
+
+			entries[filename] = entry
 		}
 
-		ticketPath := filepath.Join(ticketDir, filename)
+		return writeBinaryCacheRaw(cachePath, entries)
+	})
+}
 
-		info, statErr := os.Stat(ticketPath)
-		if statErr != nil {
-			return fmt.Errorf("stat ticket: %w", statErr)
-		}
+func rawCacheEntryFor(ticketDir, filename string, summary *Summary) (rawCacheEntry, error) {
+	if len(filename) > maxFilenameLen {
+		return rawCacheEntry{}, fmt.Errorf("caching ticket: %w (max %d chars): %s", errFilenameTooLong, maxFilenameLen, filename)
+	}
 
-		data, encErr := encodeSummaryData(summary)
-		if encErr != nil {
-			return fmt.Errorf("caching ticket: %w", encErr)
-		}
+	ticketPath := filepath.Join(ticketDir, filename)
 
-		prio, prioErr := priorityToUint8(summary.Priority)
-		if prioErr != nil {
-			return fmt.Errorf("caching ticket: %w", prioErr)
-		}
+	info, statErr := os.Stat(ticketPath)
+	if statErr != nil {
+		return rawCacheEntry{}, fmt.Errorf("stat ticket: %w", statErr)
+	}
 
-		entries[filename] = rawCacheEntry{
-			filename:   filename,
-			mtime:      info.ModTime().UnixNano(),
-			status:     statusStringToByte(summary.Status),
-			priority:   prio,
-			ticketType: typeStringToByte(summary.Type),
-			parent:     summary.Parent,
-			data:       data,
-		}
+	data, encErr := encodeSummaryData(summary)
+	if encErr != nil {
+		return rawCacheEntry{}, fmt.Errorf("caching ticket: %w", encErr)
+	}
 
-		return writeBinaryCacheRaw(cachePath, entries)
-	})
+	prio, prioErr := priorityToUint8(summary.Priority)
+	if prioErr != nil {
+		return rawCacheEntry{}, fmt.Errorf("caching ticket: %w", prioErr)
+	}
+
+	return rawCacheEntry{
+		filename:   filename,
+		mtime:      info.ModTime().UnixNano(),
+		status:     statusStringToByte(summary.Status),
+		priority:   prio,
+		ticketType: typeStringToByte(summary.Type),
+		parent:     summary.Parent,
+		data:       data,
+	}, nil
 }
 
 // DeleteCacheEntry removes a single cache entry. No-op if cache or entry doesn't exist.
@@ -976,7 +1051,7 @@ func dirMtimeNewerThanCache(ticketDir, cachePath string) (bool, error) {
 	return dirInfo.ModTime().After(cacheInfo.ModTime()), nil
 }
 
-func reconcileRawCacheEntries(ticketDir string, entries map[string]rawCacheEntry) error {
+func reconcileRawCacheEntries(ticketDir string, entries map[string]rawCacheEntry, workflow *Workflow) error {
 	dirEntries, err := os.ReadDir(ticketDir)
 	if err != nil {
 		return fmt.Errorf("reading ticket directory: %w", err)
@@ -1005,7 +1080,7 @@ func reconcileRawCacheEntries(ticketDir string, entries map[string]rawCacheEntry
 		// New file: parse and add.
 		path := filepath.Join(ticketDir, name)
 
-		summary, parseErr := ParseTicketFrontmatter(path)
+		summary, parseErr := ParseTicketFrontmatter(path, workflow)
 		if parseErr != nil {
 			return fmt.Errorf("parsing %s: %w", path, parseErr)
 		}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -296,12 +297,12 @@ func Test_Update_And_Delete_Cache_Entry_When_Invoked(t *testing.T) {
 	createTestTicketFullCT(t, ticketDir, "a-001", ticket.StatusOpen, "A", "task", 2, nil)
 	path := filepath.Join(ticketDir, "a-001.md")
 
-	summary, err := ticket.ParseTicketFrontmatter(path)
+	summary, err := ticket.ParseTicketFrontmatter(path, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	updateErr := ticket.UpdateCacheEntry(ticketDir, "a-001.md", &summary)
+	updateErr := ticket.UpdateCacheEntry(ticketDir, "a-001.md", &summary, nil)
 	if updateErr != nil {
 		t.Fatalf("UpdateCacheEntry failed: %v", updateErr)
 	}
@@ -323,12 +324,12 @@ func Test_Update_And_Delete_Cache_Entry_When_Invoked(t *testing.T) {
 	createTestTicketFullCT(t, ticketDir, "b-002", ticket.StatusOpen, "B", "bug", 1, nil)
 	path = filepath.Join(ticketDir, "b-002.md")
 
-	summary, err = ticket.ParseTicketFrontmatter(path)
+	summary, err = ticket.ParseTicketFrontmatter(path, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	updateErr = ticket.UpdateCacheEntry(ticketDir, "b-002.md", &summary)
+	updateErr = ticket.UpdateCacheEntry(ticketDir, "b-002.md", &summary, nil)
 	if updateErr != nil {
 		t.Fatalf("UpdateCacheEntry (new file) failed: %v", updateErr)
 	}
@@ -372,6 +373,99 @@ func Test_Update_And_Delete_Cache_Entry_When_Invoked(t *testing.T) {
 	_ = cache.Close()
 }
 
+func Test_Update_Cache_Entries_Writes_All_Summaries_When_Invoked(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	ticketDir := filepath.Join(tmpDir, ".tickets")
+
+	mkdirErr := os.MkdirAll(ticketDir, 0o750)
+	if mkdirErr != nil {
+		t.Fatal(mkdirErr)
+	}
+
+	writeCacheFileCT(t, ticketDir, map[string]ticket.CacheEntry{})
+
+	summaries := make(map[string]*ticket.Summary)
+
+	for _, id := range []string{"a-001", "b-002", "c-003"} {
+		createTestTicketFullCT(t, ticketDir, id, ticket.StatusInProgress, id, "task", 2, nil)
+
+		summary, err := ticket.ParseTicketFrontmatter(filepath.Join(ticketDir, id+".md"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		summaries[id+".md"] = &summary
+	}
+
+	updateErr := ticket.UpdateCacheEntries(ticketDir, summaries, nil)
+	if updateErr != nil {
+		t.Fatalf("UpdateCacheEntries failed: %v", updateErr)
+	}
+
+	cache, err := ticket.LoadBinaryCache(ticketDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = cache.Close() }()
+
+	for filename := range summaries {
+		entry := cache.Lookup(filename)
+		if entry == nil {
+			t.Fatalf("expected %s in cache after update", filename)
+		}
+
+		if got, want := entry.Summary.Status, ticket.StatusInProgress; got != want {
+			t.Fatalf("%s status=%q, want=%q", filename, got, want)
+		}
+	}
+}
+
+func Test_Cache_Roundtrips_Labels_When_Summary_Has_Labels(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	ticketDir := filepath.Join(tmpDir, ".tickets")
+
+	mkdirErr := os.MkdirAll(ticketDir, 0o750)
+	if mkdirErr != nil {
+		t.Fatal(mkdirErr)
+	}
+
+	writeCacheFileCT(t, ticketDir, map[string]ticket.CacheEntry{})
+	createTestTicketFullCT(t, ticketDir, "a-001", ticket.StatusOpen, "Labeled", "task", 2, nil)
+
+	summary, err := ticket.ParseTicketFrontmatter(filepath.Join(ticketDir, "a-001.md"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summary.Labels = []string{"backend", "urgent"}
+
+	updateErr := ticket.UpdateCacheEntry(ticketDir, "a-001.md", &summary, nil)
+	if updateErr != nil {
+		t.Fatalf("UpdateCacheEntry failed: %v", updateErr)
+	}
+
+	cache, err := ticket.LoadBinaryCache(ticketDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = cache.Close() }()
+
+	entry := cache.Lookup("a-001.md")
+	if entry == nil {
+		t.Fatal("expected a-001.md in cache after update")
+	}
+
+	if got, want := entry.Summary.Labels, []string{"backend", "urgent"}; !slices.Equal(got, want) {
+		t.Fatalf("labels=%v, want=%v", got, want)
+	}
+}
+
 func Test_Cache_Size_Limit_Validation_When_Invoked(t *testing.T) {
 	t.Parallel()
 
@@ -392,7 +486,7 @@ func Test_Cache_Size_Limit_Validation_When_Invoked(t *testing.T) {
 		createTestTicketFullCT(t, ticketDir, "a-001", ticket.StatusOpen, "A", "task", 2, nil)
 		path := filepath.Join(ticketDir, "a-001.md")
 
-		baseSummary, err := ticket.ParseTicketFrontmatter(path)
+		baseSummary, err := ticket.ParseTicketFrontmatter(path, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -406,7 +500,7 @@ func Test_Cache_Size_Limit_Validation_When_Invoked(t *testing.T) {
 		ticketDir, baseSummary := setup(t)
 		longFilename := strings.Repeat("a", 40) + ".md"
 
-		err := ticket.UpdateCacheEntry(ticketDir, longFilename, &baseSummary)
+		err := ticket.UpdateCacheEntry(ticketDir, longFilename, &baseSummary, nil)
 		if err == nil || !strings.Contains(err.Error(), "filename too long") {
 			t.Fatalf("expected long filename error, got %v", err)
 		}
@@ -418,7 +512,7 @@ func Test_Cache_Size_Limit_Validation_When_Invoked(t *testing.T) {
 		ticketDir, baseSummary := setup(t)
 		baseSummary.Assignee = strings.Repeat("x", 256)
 
-		err := ticket.UpdateCacheEntry(ticketDir, "a-001.md", &baseSummary)
+		err := ticket.UpdateCacheEntry(ticketDir, "a-001.md", &baseSummary, nil)
 		if err == nil || !strings.Contains(err.Error(), "assignee too long") {
 			t.Fatalf("expected assignee too long error, got %v", err)
 		}
@@ -430,7 +524,7 @@ func Test_Cache_Size_Limit_Validation_When_Invoked(t *testing.T) {
 		ticketDir, baseSummary := setup(t)
 		baseSummary.BlockedBy = []string{strings.Repeat("b", 256)}
 
-		err := ticket.UpdateCacheEntry(ticketDir, "a-001.md", &baseSummary)
+		err := ticket.UpdateCacheEntry(ticketDir, "a-001.md", &baseSummary, nil)
 		if err == nil || !strings.Contains(err.Error(), "blocker ID too long") {
 			t.Fatalf("expected blocker ID too long error, got %v", err)
 		}
@@ -446,7 +540,7 @@ func Test_Cache_Size_Limit_Validation_When_Invoked(t *testing.T) {
 			baseSummary.BlockedBy[i] = "x"
 		}
 
-		err := ticket.UpdateCacheEntry(ticketDir, "a-001.md", &baseSummary)
+		err := ticket.UpdateCacheEntry(ticketDir, "a-001.md", &baseSummary, nil)
 		if err == nil || !strings.Contains(err.Error(), "too many blockers") {
 			t.Fatalf("expected too many blockers error, got %v", err)
 		}
@@ -463,7 +557,7 @@ func Test_Cache_Size_Limit_Validation_When_Invoked(t *testing.T) {
 			baseSummary.BlockedBy[i] = strings.Repeat("x", 255)
 		}
 
-		err := ticket.UpdateCacheEntry(ticketDir, "a-001.md", &baseSummary)
+		err := ticket.UpdateCacheEntry(ticketDir, "a-001.md", &baseSummary, nil)
 		if err == nil || !strings.Contains(err.Error(), "entry too large") {
 			t.Fatalf("expected entry too large error, got %v", err)
 		}
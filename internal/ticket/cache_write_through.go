@@ -6,8 +6,14 @@ import (
 )
 
 // UpdateCacheAfterTicketWrite updates the cache after writing a ticket.
-func UpdateCacheAfterTicketWrite(ticketDir, filename string, summary *Summary) error {
-	cacheErr := UpdateCacheEntry(ticketDir, filename, summary)
+func UpdateCacheAfterTicketWrite(ticketDir, filename string, summary *Summary, workflow *Workflow) error {
+	return UpdateCacheAfterTicketWrites(ticketDir, map[string]*Summary{filename: summary}, workflow)
+}
+
+// UpdateCacheAfterTicketWrites updates the cache after writing several tickets.
+// Summaries are keyed by filename and applied in one cache write.
+func UpdateCacheAfterTicketWrites(ticketDir string, summaries map[string]*Summary, workflow *Workflow) error {
+	cacheErr := UpdateCacheEntries(ticketDir, summaries, workflow)
 	if cacheErr != nil {
 		cachePath := filepath.Join(ticketDir, CacheFileName)
 
@@ -1,7 +1,9 @@
 package ticket
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -15,24 +17,67 @@ type Config struct {
 	TicketDir string `json:"ticket_dir"`
 	Editor    string `json:"editor,omitempty"`
 
+	// Ls holds defaults for the ls command. A config file that sets it
+	// replaces any defaults from a lower-precedence file. Nil if unset.
+	Ls *LsDefaults `json:"ls,omitempty"`
+
 	// Resolved paths (computed, not serialized)
 	EffectiveCwd string `json:"-"` // Absolute working directory (from -C flag or os.Getwd)
 	TicketDirAbs string `json:"-"` // Absolute path to ticket directory
 
+	// Workflow is loaded once from WorkflowFileName in the ticket directory and
+	// passed to the parser, which accepts its statuses (not serialized)
+	Workflow Workflow `json:"-"`
+
 	// Sources tracks which config files were loaded (for diagnostics)
 	Sources ConfigSources `json:"-"`
 }
 
+// LsDefaults holds default flag values for the ls command.
+// Each value applies only when the matching flag is not given.
+// Unknown keys are rejected when the config is loaded.
+type LsDefaults struct {
+	Status   string   `json:"status,omitempty"`
+	Priority int      `json:"priority,omitempty"`
+	Type     string   `json:"type,omitempty"`
+	Roots    bool     `json:"roots,omitempty"`
+	Ready    bool     `json:"ready,omitempty"`
+	Limit    int      `json:"limit,omitempty"`
+	Columns  []string `json:"columns,omitempty"`
+	Sort     string   `json:"sort,omitempty"`
+}
+
+// UnmarshalJSON decodes LsDefaults, failing on unknown keys.
+func (d *LsDefaults) UnmarshalJSON(data []byte) error {
+	type plain LsDefaults
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var decoded plain
+
+	err := decoder.Decode(&decoded)
+	if err != nil {
+		return fmt.Errorf("ls: %w", err)
+	}
+
+	*d = LsDefaults(decoded)
+
+	return nil
+}
+
 // ConfigSources tracks which config files were loaded.
 type ConfigSources struct {
-	Global  string // Path to global config if loaded, empty otherwise
-	Project string // Path to project config if loaded, empty otherwise
+	Global   string // Path to global config if loaded, empty otherwise
+	Project  string // Path to project config if loaded, empty otherwise
+	Workflow string // Path to workflow file if loaded, empty otherwise
 }
 
 // DefaultConfig returns the default configuration.
 func DefaultConfig() Config {
 	return Config{
 		TicketDir: ".tickets",
+		Workflow:  DefaultWorkflow(),
 	}
 }
 
@@ -122,6 +167,23 @@ func LoadConfig(input LoadConfigInput) (Config, error) {
 		cfg.TicketDirAbs = filepath.Join(workDir, cfg.TicketDir)
 	}
 
+	// Load and validate the ticket workflow
+	workflow, workflowErr := LoadWorkflow(cfg.TicketDirAbs)
+	if workflowErr != nil {
+		return Config{}, workflowErr
+	}
+
+	cfg.Workflow = workflow
+
+	if cfg.Ls != nil && cfg.Ls.Status != "" && !workflow.HasStatus(cfg.Ls.Status) {
+		return Config{}, fmt.Errorf("%w: ls.status: unknown status %q", ErrConfigInvalid, cfg.Ls.Status)
+	}
+
+	workflowPath := filepath.Join(cfg.TicketDirAbs, WorkflowFileName)
+	if _, statErr := os.Stat(workflowPath); statErr == nil {
+		cfg.Sources.Workflow = workflowPath
+	}
+
 	return cfg, nil
 }
 
@@ -234,6 +296,10 @@ func mergeConfig(base, overlay *Config) Config {
 		base.Editor = overlay.Editor
 	}
 
+	if overlay.Ls != nil {
+		base.Ls = overlay.Ls
+	}
+
 	return *base
 }
 
@@ -242,5 +308,34 @@ func validateConfig(cfg *Config) error {
 		return ErrTicketDirEmpty
 	}
 
+	if cfg.Ls != nil {
+		err := validateLsDefaults(cfg.Ls)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrConfigInvalid, err)
+		}
+	}
+
+	return nil
+}
+
+// validateLsDefaults checks the values that do not depend on the workflow.
+// Column and sort names are checked by the ls command.
+func validateLsDefaults(d *LsDefaults) error {
+	if d.Priority < 0 || d.Priority > 4 {
+		return fmt.Errorf("ls.priority must be 1-4, got %d", d.Priority)
+	}
+
+	if d.Type != "" && !IsValidTicketType(d.Type) {
+		return fmt.Errorf("ls.type: invalid type %q", d.Type)
+	}
+
+	if d.Limit < 0 {
+		return fmt.Errorf("ls.limit must be non-negative, got %d", d.Limit)
+	}
+
+	if d.Ready && d.Status != "" {
+		return errors.New("ls.ready and ls.status cannot be used together")
+	}
+
 	return nil
 }
@@ -63,4 +63,10 @@ var (
 	ErrParentClosed               = errors.New("parent ticket is closed")
 	ErrParentNotStarted           = errors.New("parent ticket must be started first")
 	ErrHasOpenChildren            = errors.New("ticket has open children")
+	ErrNewIDRequired              = errors.New("new ticket ID is required")
+	ErrInvalidTicketID            = errors.New("invalid ticket ID (use letters, digits, '-' or '_')")
+	ErrNothingToUndo              = errors.New("nothing to undo")
+	ErrUndoConflict               = errors.New("ticket changed since the operation")
+	ErrUndoUntracked              = errors.New("operation was not recorded and cannot be undone")
+	ErrOffsetOutOfBounds          = errors.New("offset out of bounds")
 )
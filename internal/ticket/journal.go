@@ -0,0 +1,354 @@
+package ticket
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/natefinch/atomic"
+)
+
+// journalDirName is the subdirectory holding the undo journal.
+// Like locksDirName, a subdirectory keeps journal writes from modifying the
+// ticket directory's mtime, which would invalidate the cache.
+const journalDirName = ".journal"
+
+// journalFileName is the name of the journal file inside journalDirName.
+const journalFileName = "ops.json"
+
+// errJournalNoContent reports a journal entry for an existing ticket that
+// carries no prior content; created tickets are passed separately.
+var errJournalNoContent = errors.New("no prior content for ticket")
+
+// JournalMaxOps is the number of operations kept in the undo journal.
+// Older operations are dropped when a new one is recorded.
+const JournalMaxOps = 20
+
+// JournalOp is one recorded state-changing command.
+//
+// Untracked marks a command whose writes could not be recorded, such as an
+// editor that keeps running after tk exits. Its tickets carry no content,
+// and undo stops at it until it is discarded with [DropLastOp].
+//
+// Pending marks an operation recorded before its writes, such as
+// [MoveTicket], that has not completed. Each of its tickets may hold either
+// its Before or its After content; undo accepts both.
+type JournalOp struct {
+	Command   string          `json:"command"`
+	Time      time.Time       `json:"time"`
+	Tickets   []JournalTicket `json:"tickets"`
+	Untracked bool            `json:"untracked,omitempty"`
+	Pending   bool            `json:"pending,omitempty"`
+}
+
+// JournalTicket holds a ticket file's content before and after an operation.
+// A nil Before means the operation created the file; a nil After means the
+// operation removed it.
+type JournalTicket struct {
+	ID     string `json:"id"`
+	Before []byte `json:"before"`
+	After  []byte `json:"after"`
+}
+
+// RecordJournalOp reads the current content of each ticket in before and
+// created and appends an operation to the undo journal. before maps ticket
+// IDs to their content prior to the command; created lists the tickets the
+// command created, which undo removes.
+// Tickets whose content did not change are not recorded; if none changed,
+// nothing is written.
+func RecordJournalOp(ticketDir, command string, before map[string][]byte, created []string) error {
+	op := JournalOp{Command: command, Time: time.Now().UTC()}
+
+	record := func(ticketID string, content []byte) error {
+		after, readErr := os.ReadFile(Path(ticketDir, ticketID))
+		if readErr != nil && !os.IsNotExist(readErr) {
+			return fmt.Errorf("reading %s: %w", ticketID, readErr)
+		}
+
+		if !sameContent(content, after) {
+			op.Tickets = append(op.Tickets, JournalTicket{ID: ticketID, Before: content, After: after})
+		}
+
+		return nil
+	}
+
+	for ticketID, content := range before {
+		// A nil Before would make undo remove the ticket.
+		if content == nil {
+			return fmt.Errorf("%w: %s", errJournalNoContent, ticketID)
+		}
+
+		err := record(ticketID, content)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, ticketID := range created {
+		err := record(ticketID, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(op.Tickets) == 0 {
+		return nil
+	}
+
+	slices.SortFunc(op.Tickets, func(a, b JournalTicket) int { return strings.Compare(a.ID, b.ID) })
+
+	return appendJournalOp(ticketDir, op)
+}
+
+// RecordUntrackedOp appends an untracked operation on ticketIDs to the undo
+// journal. Use it for commands that write tickets in ways the journal cannot
+// capture, so that undo refuses to reach past them.
+func RecordUntrackedOp(ticketDir, command string, ticketIDs []string) error {
+	op := JournalOp{Command: command, Time: time.Now().UTC(), Untracked: true}
+
+	for _, ticketID := range ticketIDs {
+		op.Tickets = append(op.Tickets, JournalTicket{ID: ticketID})
+	}
+
+	return appendJournalOp(ticketDir, op)
+}
+
+// appendJournalOp appends op to the journal, dropping the oldest operations
+// beyond [JournalMaxOps].
+func appendJournalOp(ticketDir string, op JournalOp) error {
+	return withJournal(ticketDir, func(ops []JournalOp) ([]JournalOp, error) {
+		return appendTrimmed(ops, op), nil
+	})
+}
+
+// appendTrimmed appends op to ops and drops the oldest operations beyond
+// [JournalMaxOps].
+func appendTrimmed(ops []JournalOp, op JournalOp) []JournalOp {
+	ops = append(ops, op)
+	if len(ops) > JournalMaxOps {
+		ops = ops[len(ops)-JournalMaxOps:]
+	}
+
+	return ops
+}
+
+// ReadJournal returns the recorded operations, oldest first.
+// Returns nil if nothing was recorded yet.
+func ReadJournal(ticketDir string) ([]JournalOp, error) {
+	return readJournalFile(journalPath(ticketDir))
+}
+
+// UndoLastOp restores the tickets of the most recent journal operation to
+// their content from before it and removes the operation from the journal.
+//
+// Fails with [ErrNothingToUndo] if the journal is empty, with
+// [ErrUndoUntracked] if the operation is untracked, and with
+// [ErrUndoConflict] if any of the tickets changed after the operation; in
+// those cases nothing is restored. A pending operation is reverted from
+// whatever point it stopped at. If restoring a ticket fails, the tickets
+// restored before it are written back to their content after the operation
+// and the operation stays in the journal.
+func UndoLastOp(ticketDir string) (JournalOp, error) {
+	var undone JournalOp
+
+	err := withJournal(ticketDir, func(ops []JournalOp) ([]JournalOp, error) {
+		if len(ops) == 0 {
+			return nil, ErrNothingToUndo
+		}
+
+		undone = ops[len(ops)-1]
+
+		if undone.Untracked {
+			return nil, fmt.Errorf("%w: %s", ErrUndoUntracked, undone.Command)
+		}
+
+		for _, jt := range undone.Tickets {
+			current, readErr := os.ReadFile(Path(ticketDir, jt.ID))
+			if readErr != nil && !os.IsNotExist(readErr) {
+				return nil, fmt.Errorf("reading %s: %w", jt.ID, readErr)
+			}
+
+			if !sameContent(current, jt.After) && (!undone.Pending || !sameContent(current, jt.Before)) {
+				return nil, fmt.Errorf("%w: %s", ErrUndoConflict, jt.ID)
+			}
+		}
+
+		for i, jt := range undone.Tickets {
+			restoreErr := restoreJournalTicket(ticketDir, jt)
+			if restoreErr != nil {
+				// Put the tickets restored so far back to their content after
+				// the operation, so the operation can be undone again.
+				redoErr := redoJournalTickets(ticketDir, undone.Tickets[:i])
+
+				return nil, errors.Join(fmt.Errorf("restoring %s: %w", jt.ID, restoreErr), redoErr)
+			}
+		}
+
+		return ops[:len(ops)-1], nil
+	})
+	if err != nil {
+		return JournalOp{}, err
+	}
+
+	return undone, nil
+}
+
+// DropLastOp removes the most recent operation from the undo journal without
+// restoring anything, so that undo can continue with the one before it.
+// Use it to get past an untracked operation; undoing older operations still
+// fails with [ErrUndoConflict] for tickets it changed.
+//
+// Fails with [ErrNothingToUndo] if the journal is empty.
+func DropLastOp(ticketDir string) (JournalOp, error) {
+	var dropped JournalOp
+
+	err := withJournal(ticketDir, func(ops []JournalOp) ([]JournalOp, error) {
+		if len(ops) == 0 {
+			return nil, ErrNothingToUndo
+		}
+
+		dropped = ops[len(ops)-1]
+
+		return ops[:len(ops)-1], nil
+	})
+	if err != nil {
+		return JournalOp{}, err
+	}
+
+	return dropped, nil
+}
+
+// redoJournalTickets writes each ticket's After content back, reverting a
+// partial undo. Returns the errors of tickets that could not be reverted.
+func redoJournalTickets(ticketDir string, tickets []JournalTicket) error {
+	var errs []error
+
+	for _, jt := range tickets {
+		redoErr := restoreJournalTicket(ticketDir, JournalTicket{ID: jt.ID, Before: jt.After})
+		if redoErr != nil {
+			errs = append(errs, fmt.Errorf("reverting %s: %w", jt.ID, redoErr))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// restoreJournalTicket writes jt.Before back, or removes the ticket file if
+// jt.Before is nil (the operation created it).
+func restoreJournalTicket(ticketDir string, jt JournalTicket) error {
+	path := Path(ticketDir, jt.ID)
+
+	return WithLock(path, func() error {
+		return writeTicketContent(path, jt.Before)
+	})
+}
+
+// writeTicketContent writes content to the ticket file at path, or removes
+// the file if content is nil. The caller must hold the ticket's lock.
+func writeTicketContent(path string, content []byte) error {
+	if content == nil {
+		removeErr := os.Remove(path)
+		if removeErr != nil && !os.IsNotExist(removeErr) {
+			return fmt.Errorf("removing ticket: %w", removeErr)
+		}
+
+		return nil
+	}
+
+	writeErr := atomic.WriteFile(path, bytes.NewReader(content))
+	if writeErr != nil {
+		return fmt.Errorf("writing ticket: %w", writeErr)
+	}
+
+	chmodErr := os.Chmod(path, filePerms)
+	if chmodErr != nil {
+		return fmt.Errorf("chmod ticket: %w", chmodErr)
+	}
+
+	return nil
+}
+
+// withJournal runs handler on the journal under its lock and writes the
+// returned operations back. If handler returns an error, nothing is written.
+func withJournal(ticketDir string, handler func(ops []JournalOp) ([]JournalOp, error)) error {
+	return withJournalLock(ticketDir, func(path string) error {
+		ops, readErr := readJournalFile(path)
+		if readErr != nil {
+			return readErr
+		}
+
+		ops, handleErr := handler(ops)
+		if handleErr != nil {
+			return handleErr
+		}
+
+		return writeJournalFile(path, ops)
+	})
+}
+
+// withJournalLock runs handler with the journal locked, passing it the
+// journal file's path.
+func withJournalLock(ticketDir string, handler func(path string) error) error {
+	path := journalPath(ticketDir)
+
+	mkdirErr := os.MkdirAll(filepath.Dir(path), dirPerms)
+	if mkdirErr != nil {
+		return fmt.Errorf("creating journal dir: %w", mkdirErr)
+	}
+
+	// Lock the journal dir rather than the file, so the lock lands in the
+	// ticket dir's .locks instead of a nested one.
+	return WithLock(filepath.Dir(path), func() error {
+		return handler(path)
+	})
+}
+
+func writeJournalFile(path string, ops []JournalOp) error {
+	data, err := json.Marshal(ops)
+	if err != nil {
+		return fmt.Errorf("encoding journal: %w", err)
+	}
+
+	err = atomic.WriteFile(path, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("writing journal: %w", err)
+	}
+
+	return nil
+}
+
+func readJournalFile(path string) ([]JournalOp, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("reading journal: %w", err)
+	}
+
+	var ops []JournalOp
+
+	err = json.Unmarshal(data, &ops)
+	if err != nil {
+		return nil, fmt.Errorf("decoding journal %s: %w", path, err)
+	}
+
+	return ops, nil
+}
+
+// sameContent reports whether a and b hold the same content, treating a
+// missing file (nil) as different from an empty one.
+func sameContent(a, b []byte) bool {
+	return (a == nil) == (b == nil) && bytes.Equal(a, b)
+}
+
+func journalPath(ticketDir string) string {
+	return filepath.Join(ticketDir, journalDirName, journalFileName)
+}
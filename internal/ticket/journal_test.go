@@ -0,0 +1,235 @@
+package ticket_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/calvinalkan/agent-task/internal/ticket"
+)
+
+func Test_RecordJournalOp_Keeps_Last_Ops_When_Journal_Is_Full(t *testing.T) {
+	t.Parallel()
+
+	ticketDir := t.TempDir()
+	path := ticket.Path(ticketDir, "a")
+
+	for i := range ticket.JournalMaxOps + 3 {
+		before := []byte("rev " + strconv.Itoa(i))
+
+		writeFile(t, path, "rev "+strconv.Itoa(i+1))
+
+		err := ticket.RecordJournalOp(ticketDir, "edit", map[string][]byte{"a": before}, nil)
+		if err != nil {
+			t.Fatalf("record %d: %v", i, err)
+		}
+	}
+
+	ops, err := ticket.ReadJournal(ticketDir)
+	if err != nil {
+		t.Fatalf("read journal: %v", err)
+	}
+
+	if got, want := len(ops), ticket.JournalMaxOps; got != want {
+		t.Fatalf("len(ops)=%d, want=%d", got, want)
+	}
+
+	if got, want := string(ops[0].Tickets[0].Before), "rev 3"; got != want {
+		t.Fatalf("oldest before=%q, want=%q", got, want)
+	}
+}
+
+func Test_UndoLastOp_Restores_Nothing_When_Ticket_Changed_After_Op(t *testing.T) {
+	t.Parallel()
+
+	ticketDir := t.TempDir()
+	pathA := ticket.Path(ticketDir, "a")
+	pathB := ticket.Path(ticketDir, "b")
+
+	writeFile(t, pathA, "a after")
+	writeFile(t, pathB, "b after")
+
+	err := ticket.RecordJournalOp(ticketDir, "close", map[string][]byte{
+		"a": []byte("a before"),
+		"b": []byte("b before"),
+	}, nil)
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	writeFile(t, pathB, "b edited")
+
+	_, err = ticket.UndoLastOp(ticketDir)
+	if !errors.Is(err, ticket.ErrUndoConflict) {
+		t.Fatalf("undo: got %v, want ErrUndoConflict", err)
+	}
+
+	if got := readFile(t, pathA); got != "a after" {
+		t.Fatalf("a=%q, want unchanged", got)
+	}
+
+	ops, err := ticket.ReadJournal(ticketDir)
+	if err != nil {
+		t.Fatalf("read journal: %v", err)
+	}
+
+	if len(ops) != 1 {
+		t.Fatalf("len(ops)=%d, want op kept after conflict", len(ops))
+	}
+}
+
+func Test_UndoLastOp_Fails_When_Last_Op_Is_Untracked(t *testing.T) {
+	t.Parallel()
+
+	ticketDir := t.TempDir()
+	path := ticket.Path(ticketDir, "a")
+
+	writeFile(t, path, "a after")
+
+	err := ticket.RecordJournalOp(ticketDir, "close", map[string][]byte{"a": []byte("a before")}, nil)
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	err = ticket.RecordUntrackedOp(ticketDir, "edit", []string{"b"})
+	if err != nil {
+		t.Fatalf("record untracked: %v", err)
+	}
+
+	_, err = ticket.UndoLastOp(ticketDir)
+	if !errors.Is(err, ticket.ErrUndoUntracked) {
+		t.Fatalf("undo: got %v, want ErrUndoUntracked", err)
+	}
+
+	if got := readFile(t, path); got != "a after" {
+		t.Fatalf("a=%q, want older op left alone", got)
+	}
+}
+
+func Test_UndoLastOp_Reverts_Restored_Tickets_When_A_Restore_Fails(t *testing.T) {
+	t.Parallel()
+
+	ticketDir := t.TempDir()
+	pathA := ticket.Path(ticketDir, "a")
+	pathB := ticket.Path(ticketDir, "b")
+
+	writeFile(t, pathA, "a after")
+	writeFile(t, pathB, "b after")
+
+	err := ticket.RecordJournalOp(ticketDir, "close", map[string][]byte{
+		"a": []byte("a before"),
+		"b": []byte("b before"),
+	}, nil)
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	// A directory in place of b's lock file makes restoring b fail after a
+	// was restored.
+	lockPath := filepath.Join(ticketDir, ".locks", "b.md.lock")
+
+	err = os.MkdirAll(lockPath, 0o750)
+	if err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	_, err = ticket.UndoLastOp(ticketDir)
+	if err == nil {
+		t.Fatal("undo: want error when restoring b fails")
+	}
+
+	if got := readFile(t, pathA); got != "a after" {
+		t.Fatalf("a=%q, want reverted to content after op", got)
+	}
+
+	err = os.Remove(lockPath)
+	if err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+
+	_, err = ticket.UndoLastOp(ticketDir)
+	if err != nil {
+		t.Fatalf("undo after failure: %v", err)
+	}
+
+	if gotA, gotB := readFile(t, pathA), readFile(t, pathB); gotA != "a before" || gotB != "b before" {
+		t.Fatalf("a=%q b=%q, want both restored", gotA, gotB)
+	}
+}
+
+func Test_DropLastOp_Lets_Undo_Continue_When_Last_Op_Is_Untracked(t *testing.T) {
+	t.Parallel()
+
+	ticketDir := t.TempDir()
+	path := ticket.Path(ticketDir, "a")
+
+	writeFile(t, path, "a after")
+
+	err := ticket.RecordJournalOp(ticketDir, "close", map[string][]byte{"a": []byte("a before")}, nil)
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	err = ticket.RecordUntrackedOp(ticketDir, "edit", []string{"b"})
+	if err != nil {
+		t.Fatalf("record untracked: %v", err)
+	}
+
+	dropped, err := ticket.DropLastOp(ticketDir)
+	if err != nil || dropped.Command != "edit" {
+		t.Fatalf("drop: got %+v, %v; want the untracked edit", dropped, err)
+	}
+
+	_, err = ticket.UndoLastOp(ticketDir)
+	if err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+
+	if got := readFile(t, path); got != "a before" {
+		t.Fatalf("a=%q, want restored", got)
+	}
+
+	_, err = ticket.DropLastOp(ticketDir)
+	if !errors.Is(err, ticket.ErrNothingToUndo) {
+		t.Fatalf("drop on empty journal: got %v, want ErrNothingToUndo", err)
+	}
+}
+
+func Test_RecordJournalOp_Fails_When_Existing_Ticket_Has_No_Content(t *testing.T) {
+	t.Parallel()
+
+	ticketDir := t.TempDir()
+	writeFile(t, ticket.Path(ticketDir, "a"), "a")
+
+	err := ticket.RecordJournalOp(ticketDir, "assign", map[string][]byte{"a": nil}, nil)
+	if err == nil {
+		t.Fatal("record: want error for nil prior content")
+	}
+
+	ops, err := ticket.ReadJournal(ticketDir)
+	if err != nil || len(ops) != 0 {
+		t.Fatalf("journal = %v, %v; want empty", ops, err)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	err := os.WriteFile(path, []byte(content), 0o600)
+	if err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+
+	return string(data)
+}
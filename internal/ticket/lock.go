@@ -39,6 +39,19 @@ func WithLock(path string, handler func() error) error {
 	return handler()
 }
 
+// withLocks runs handler while holding the locks of all paths, acquired in
+// order. Callers that lock several tickets must hold the journal lock, so
+// that two of them never wait on each other.
+func withLocks(paths []string, handler func() error) error {
+	if len(paths) == 0 {
+		return handler()
+	}
+
+	return WithLock(paths[0], func() error {
+		return withLocks(paths[1:], handler)
+	})
+}
+
 // WithTicketLock provides atomic access to a ticket file with file locking.
 // The function handler receives the current file content and returns the new content.
 // If handler returns nil content, no write is performed (read-only operation).
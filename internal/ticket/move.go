@@ -0,0 +1,266 @@
+package ticket
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MoveTicket renames ticket oldID to newID, rewrites its id field and points
+// the blocked-by and parent references of other tickets at newID. It returns
+// the IDs of the referrers it rewrote, sorted.
+//
+// The journal, both ticket paths and every referrer stay locked for the whole
+// move. Before any file is written, the move is recorded in the undo journal
+// as a pending "mv" operation holding each ticket's content before and after,
+// and it is marked done once every write succeeded. If a write fails, the
+// files written so far are restored and the operation is dropped. If the
+// process dies midway or the restore fails, the operation stays pending and
+// [UndoLastOp] reverts it.
+//
+// Fails with [ErrTicketNotFound] if oldID does not exist and with
+// [ErrTicketFileExists] if newID does; nothing is written in those cases.
+func MoveTicket(ticketDir, oldID, newID string) ([]string, error) {
+	for range moveAttempts {
+		referrers, err := moveTicketOnce(ticketDir, oldID, newID)
+		if !errors.Is(err, errReferrersChanged) {
+			return referrers, err
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", errReferrersChanged, oldID)
+}
+
+// moveAttempts bounds how often [MoveTicket] rescans when the tickets
+// referencing oldID change while their locks are taken.
+const moveAttempts = 3
+
+// errReferrersChanged reports that the tickets referencing the moved ticket
+// changed between scanning for them and locking them.
+var errReferrersChanged = errors.New("tickets referencing the ticket changed during the move")
+
+func moveTicketOnce(ticketDir, oldID, newID string) ([]string, error) {
+	var referrers []string
+
+	err := withJournalLock(ticketDir, func(journalPath string) error {
+		return WithLock(Path(ticketDir, oldID), func() error {
+			return WithLock(Path(ticketDir, newID), func() error {
+				op, planErr := planMove(ticketDir, oldID, newID)
+				if planErr != nil {
+					return planErr
+				}
+
+				referrers = moveReferrers(op)
+
+				paths := make([]string, 0, len(referrers))
+				for _, ticketID := range referrers {
+					paths = append(paths, Path(ticketDir, ticketID))
+				}
+
+				return withLocks(paths, func() error {
+					// Referrers were read before their locks were taken; plan
+					// again now that they cannot change.
+					op, planErr = planMove(ticketDir, oldID, newID)
+					if planErr != nil {
+						return planErr
+					}
+
+					if !slices.Equal(moveReferrers(op), referrers) {
+						return errReferrersChanged
+					}
+
+					return applyMove(ticketDir, journalPath, op)
+				})
+			})
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return referrers, nil
+}
+
+// moveReferrers returns the IDs of the tickets op rewrites, sorted.
+func moveReferrers(op JournalOp) []string {
+	var referrers []string
+
+	for _, jt := range op.Tickets {
+		if jt.Before != nil && jt.After != nil {
+			referrers = append(referrers, jt.ID)
+		}
+	}
+
+	return referrers
+}
+
+// planMove reads the tickets a move touches and returns the pending journal
+// operation describing it. The caller must hold the locks of oldID and newID.
+func planMove(ticketDir, oldID, newID string) (JournalOp, error) {
+	oldContent, err := os.ReadFile(Path(ticketDir, oldID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return JournalOp{}, fmt.Errorf("%w: %s", ErrTicketNotFound, oldID)
+		}
+
+		return JournalOp{}, fmt.Errorf("reading ticket: %w", err)
+	}
+
+	_, err = os.Stat(Path(ticketDir, newID))
+	if err == nil {
+		return JournalOp{}, fmt.Errorf("%w: %s", ErrTicketFileExists, newID)
+	}
+
+	newContent, err := SetFieldInContent(oldContent, "id", newID)
+	if err != nil {
+		return JournalOp{}, fmt.Errorf("setting id: %w", err)
+	}
+
+	op := JournalOp{
+		Command: "mv",
+		Time:    time.Now().UTC(),
+		Tickets: []JournalTicket{
+			{ID: oldID, Before: oldContent},
+			{ID: newID, After: newContent},
+		},
+		Pending: true,
+	}
+
+	entries, err := os.ReadDir(ticketDir)
+	if err != nil {
+		return JournalOp{}, fmt.Errorf("reading ticket directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".md") {
+			continue
+		}
+
+		ticketID := strings.TrimSuffix(name, ".md")
+		if ticketID == oldID || ticketID == newID {
+			continue
+		}
+
+		content, readErr := os.ReadFile(filepath.Join(ticketDir, name))
+		if readErr != nil {
+			return JournalOp{}, fmt.Errorf("reading %s: %w", ticketID, readErr)
+		}
+
+		updated, replaceErr := replaceTicketReference(content, oldID, newID)
+		if replaceErr != nil {
+			return JournalOp{}, fmt.Errorf("update %s: %w", ticketID, replaceErr)
+		}
+
+		if !sameContent(content, updated) {
+			op.Tickets = append(op.Tickets, JournalTicket{ID: ticketID, Before: content, After: updated})
+		}
+	}
+
+	slices.SortFunc(op.Tickets, func(a, b JournalTicket) int { return strings.Compare(a.ID, b.ID) })
+
+	return op, nil
+}
+
+// applyMove records op as pending, writes its tickets and marks it done.
+// The new ticket is written first and the old one removed last, so that a
+// crash never leaves the ticket without a file. The caller must hold the
+// journal lock and the locks of every ticket in op.
+func applyMove(ticketDir, journalPath string, op JournalOp) error {
+	ops, err := readJournalFile(journalPath)
+	if err != nil {
+		return err
+	}
+
+	err = writeJournalFile(journalPath, appendTrimmed(ops, op))
+	if err != nil {
+		return err
+	}
+
+	// Created tickets first, removed ones last.
+	order := slices.Clone(op.Tickets)
+	slices.SortStableFunc(order, func(a, b JournalTicket) int {
+		return moveStep(a) - moveStep(b)
+	})
+
+	for i, jt := range order {
+		writeErr := writeTicketContent(Path(ticketDir, jt.ID), jt.After)
+		if writeErr == nil {
+			continue
+		}
+
+		writeErr = fmt.Errorf("update %s: %w", jt.ID, writeErr)
+
+		var revertErrs []error
+
+		for _, done := range order[:i] {
+			revertErr := writeTicketContent(Path(ticketDir, done.ID), done.Before)
+			if revertErr != nil {
+				revertErrs = append(revertErrs, fmt.Errorf("reverting %s: %w", done.ID, revertErr))
+			}
+		}
+
+		if len(revertErrs) > 0 {
+			return fmt.Errorf("%w; rollback: %w (run undo to revert the move)", writeErr, errors.Join(revertErrs...))
+		}
+
+		return errors.Join(writeErr, writeJournalFile(journalPath, ops))
+	}
+
+	op.Pending = false
+
+	err = writeJournalFile(journalPath, appendTrimmed(ops, op))
+	if err != nil {
+		return fmt.Errorf("completing journal entry: %w", err)
+	}
+
+	return nil
+}
+
+// moveStep orders the writes of a move: created tickets, then rewritten
+// ones, then removed ones.
+func moveStep(jt JournalTicket) int {
+	switch {
+	case jt.Before == nil:
+		return 0
+	case jt.After == nil:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// replaceTicketReference rewrites oldID to newID in the blocked-by and parent
+// fields of content.
+func replaceTicketReference(content []byte, oldID, newID string) ([]byte, error) {
+	blockedBy, err := GetBlockedByFromContent(content)
+	if err != nil {
+		return nil, fmt.Errorf("reading blocked-by: %w", err)
+	}
+
+	if slices.Contains(blockedBy, oldID) {
+		for i, blockerID := range blockedBy {
+			if blockerID == oldID {
+				blockedBy[i] = newID
+			}
+		}
+
+		content, err = UpdateBlockedByInContent(content, blockedBy)
+		if err != nil {
+			return nil, fmt.Errorf("updating blocked-by: %w", err)
+		}
+	}
+
+	if GetParentFromContent(content) == oldID {
+		content, err = SetFieldInContent(content, "parent", newID)
+		if err != nil {
+			return nil, fmt.Errorf("updating parent: %w", err)
+		}
+	}
+
+	return content, nil
+}
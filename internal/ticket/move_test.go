@@ -0,0 +1,141 @@
+package ticket_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/calvinalkan/agent-task/internal/ticket"
+)
+
+func Test_MoveTicket_Rewrites_Referrers_And_Records_Undo_When_Invoked(t *testing.T) {
+	t.Parallel()
+
+	ticketDir := t.TempDir()
+	createTestTicketFull(t, ticketDir, "a", ticket.StatusOpen, "A", "task", 2, nil)
+	createTestTicketFull(t, ticketDir, "b", ticket.StatusOpen, "B", "task", 2, []string{"a"})
+	createTestTicketFull(t, ticketDir, "c", ticket.StatusOpen, "C", "task", 2, nil)
+
+	before := map[string]string{
+		"a": readFile(t, ticket.Path(ticketDir, "a")),
+		"b": readFile(t, ticket.Path(ticketDir, "b")),
+	}
+
+	referrers, err := ticket.MoveTicket(ticketDir, "a", "z")
+	if err != nil {
+		t.Fatalf("move: %v", err)
+	}
+
+	if len(referrers) != 1 || referrers[0] != "b" {
+		t.Fatalf("referrers=%v, want [b]", referrers)
+	}
+
+	_, err = os.Stat(ticket.Path(ticketDir, "a"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("old ticket: got %v, want not exist", err)
+	}
+
+	if got := readFile(t, ticket.Path(ticketDir, "z")); !strings.Contains(got, "id: z\n") {
+		t.Fatalf("z=%q, want id rewritten", got)
+	}
+
+	if got := readFile(t, ticket.Path(ticketDir, "b")); !strings.Contains(got, "blocked-by: [z]\n") {
+		t.Fatalf("b=%q, want reference rewritten", got)
+	}
+
+	ops, err := ticket.ReadJournal(ticketDir)
+	if err != nil || len(ops) != 1 || ops[0].Pending {
+		t.Fatalf("journal = %+v, %v; want one completed op", ops, err)
+	}
+
+	_, err = ticket.UndoLastOp(ticketDir)
+	if err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+
+	for id, content := range before {
+		if got := readFile(t, ticket.Path(ticketDir, id)); got != content {
+			t.Fatalf("%s=%q, want %q", id, got, content)
+		}
+	}
+
+	_, err = os.Stat(ticket.Path(ticketDir, "z"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("new ticket after undo: got %v, want not exist", err)
+	}
+}
+
+func Test_UndoLastOp_Reverts_Move_When_It_Stopped_Midway(t *testing.T) {
+	t.Parallel()
+
+	ticketDir := t.TempDir()
+	createTestTicketFull(t, ticketDir, "a", ticket.StatusOpen, "A", "task", 2, nil)
+	createTestTicketFull(t, ticketDir, "b", ticket.StatusOpen, "B", "task", 2, []string{"a"})
+	createTestTicketFull(t, ticketDir, "c", ticket.StatusOpen, "C", "task", 2, []string{"a"})
+
+	oldA := readFile(t, ticket.Path(ticketDir, "a"))
+	oldB := readFile(t, ticket.Path(ticketDir, "b"))
+	oldC := readFile(t, ticket.Path(ticketDir, "c"))
+
+	// The state a crash leaves once z and b were written: both copies of
+	// the ticket exist and c still points at a.
+	newZ := "---\nid: z\n---\n# A\n"
+	newB := "---\nid: b\nblocked-by: [z]\n---\n# B\n"
+	newC := "---\nid: c\nblocked-by: [z]\n---\n# C\n"
+
+	writeFile(t, ticket.Path(ticketDir, "z"), newZ)
+	writeFile(t, ticket.Path(ticketDir, "b"), newB)
+
+	writeJournal(t, ticketDir, []ticket.JournalOp{{
+		Command: "mv",
+		Tickets: []ticket.JournalTicket{
+			{ID: "a", Before: []byte(oldA)},
+			{ID: "b", Before: []byte(oldB), After: []byte(newB)},
+			{ID: "c", Before: []byte(oldC), After: []byte(newC)},
+			{ID: "z", After: []byte(newZ)},
+		},
+		Pending: true,
+	}})
+
+	_, err := ticket.UndoLastOp(ticketDir)
+	if err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+
+	if got := readFile(t, ticket.Path(ticketDir, "a")); got != oldA {
+		t.Fatalf("a=%q, want %q", got, oldA)
+	}
+
+	if got := readFile(t, ticket.Path(ticketDir, "b")); got != oldB {
+		t.Fatalf("b=%q, want %q", got, oldB)
+	}
+
+	if got := readFile(t, ticket.Path(ticketDir, "c")); got != oldC {
+		t.Fatalf("c=%q, want %q", got, oldC)
+	}
+
+	_, err = os.Stat(ticket.Path(ticketDir, "z"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("z: got %v, want not exist", err)
+	}
+}
+
+func writeJournal(t *testing.T, ticketDir string, ops []ticket.JournalOp) {
+	t.Helper()
+
+	data, err := json.Marshal(ops)
+	if err != nil {
+		t.Fatalf("encode journal: %v", err)
+	}
+
+	dir := filepath.Join(ticketDir, ".journal")
+
+	err = os.MkdirAll(dir, 0o750)
+	if err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	writeFile(t, filepath.Join(dir, "ops.json"), string(data))
+}
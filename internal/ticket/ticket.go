@@ -28,6 +28,7 @@ type Ticket struct {
 	Type          string
 	Priority      int
 	Assignee      string
+	Labels        []string
 	ExternalRef   string
 	Title         string
 	Description   string
@@ -173,6 +174,7 @@ func FormatTicket(ticket *Ticket) string {
 		{key: "blocked-by", value: formatBlockedBy(ticket.BlockedBy)},
 		{key: "created", value: ticket.Created.UTC().Format(time.RFC3339)},
 		{key: "external-ref", value: ticket.ExternalRef, omitEmpty: true},
+		{key: "labels", value: formatLabels(ticket.Labels), omitEmpty: true},
 		{key: "parent", value: ticket.Parent, omitEmpty: true},
 		{key: "priority", value: strconv.Itoa(ticket.Priority)},
 		{key: "status", value: ticket.Status},
@@ -222,6 +224,16 @@ func formatBlockedBy(blockedBy []string) string {
 	return "[" + strings.Join(blockedBy, ", ") + "]"
 }
 
+// formatLabels formats labels like blocked-by. Returns "" for no labels,
+// since the labels field is omitted when empty.
+func formatLabels(labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	return formatBlockedBy(labels)
+}
+
 // WriteTicket writes a ticket to the specified directory.
 // Returns the full path of the created file.
 func WriteTicket(ticketDir string, ticket *Ticket) (string, error) {
@@ -312,6 +324,24 @@ func Path(ticketDir, ticketID string) string {
 	return filepath.Join(ticketDir, ticketID+".md")
 }
 
+// IsValidID reports whether id can be used as a ticket ID: non-empty and
+// made of ASCII letters, digits, '-' and '_'. This keeps IDs safe as file
+// names and inside blocked-by lists.
+func IsValidID(id string) bool {
+	if id == "" {
+		return false
+	}
+
+	for _, r := range id {
+		isAlnum := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+		if !isAlnum && r != '-' && r != '_' {
+			return false
+		}
+	}
+
+	return true
+}
+
 // GetStatusFromContent extracts the status field from ticket content.
 func GetStatusFromContent(content []byte) (string, error) {
 	lines := strings.Split(string(content), "\n")
@@ -561,6 +591,7 @@ type Summary struct {
 	Type          string
 	Priority      int
 	Assignee      string
+	Labels        []string
 	Closed        string // Optional, only if status=closed
 	Title         string
 	Path          string
@@ -585,7 +616,6 @@ var (
 	errUnclosedFrontmatter = errors.New("unclosed frontmatter")
 	errFrontmatterTooLong  = errors.New("frontmatter exceeds maximum line limit")
 	errNoTitle             = errors.New("no title found")
-	errOffsetOutOfBounds   = errors.New("offset out of bounds")
 )
 
 // Valid ticket statuses.
@@ -609,6 +639,10 @@ type ListTicketsOptions struct {
 	RootsOnly bool   // only tickets without parent
 	Limit     int    // max tickets to return (0 = no limit)
 	Offset    int    // skip first N matching tickets
+
+	// Workflow supplies the statuses accepted besides the built-in ones
+	// when ticket files are parsed (nil = built-in only).
+	Workflow *Workflow
 }
 
 // ListTickets reads all ticket files from a directory and returns parsed summaries.
@@ -620,6 +654,11 @@ func ListTickets(ticketDir string, opts *ListTicketsOptions, diagOut io.Writer)
 		diagOut = io.Discard
 	}
 
+	var workflow *Workflow
+	if opts != nil {
+		workflow = opts.Workflow
+	}
+
 	// Ticket directory missing => no tickets.
 	_, statErr := os.Stat(ticketDir)
 	if os.IsNotExist(statErr) {
@@ -639,7 +678,7 @@ func ListTickets(ticketDir string, opts *ListTicketsOptions, diagOut io.Writer)
 			_, _ = fmt.Fprintln(diagOut, "loading cache: invalid format, rebuilding")
 		}
 
-		results, rebuildErr := BuildCacheParallelLocked(ticketDir, nil)
+		results, rebuildErr := BuildCacheParallelLocked(ticketDir, nil, workflow)
 		if rebuildErr != nil {
 			return nil, rebuildErr
 		}
@@ -663,7 +702,7 @@ func ListTickets(ticketDir string, opts *ListTicketsOptions, diagOut io.Writer)
 
 		var reconcileErr error
 
-		reconcileResults, reconcileErr = reconcileCacheOnDisk(ticketDir)
+		reconcileResults, reconcileErr = reconcileCacheOnDisk(ticketDir, workflow)
 		if reconcileErr != nil {
 			return nil, reconcileErr
 		}
@@ -673,7 +712,7 @@ func ListTickets(ticketDir string, opts *ListTicketsOptions, diagOut io.Writer)
 			// Cache should exist after reconcile; fall back to full rebuild.
 			_, _ = fmt.Fprintln(diagOut, "loading cache: invalid format, rebuilding")
 
-			results, rebuildErr := BuildCacheParallelLocked(ticketDir, nil)
+			results, rebuildErr := BuildCacheParallelLocked(ticketDir, nil, workflow)
 			if rebuildErr != nil {
 				return nil, rebuildErr
 			}
@@ -690,8 +729,13 @@ func ListTickets(ticketDir string, opts *ListTicketsOptions, diagOut io.Writer)
 	}
 
 	statusFilter := -1
+	statusName := ""
+
 	if opts.Status != "" {
 		statusFilter = int(statusStringToByte(opts.Status))
+		if statusFilter == statusByteCustom {
+			statusName = opts.Status
+		}
 	}
 
 	priorityFilter := opts.Priority
@@ -702,16 +746,17 @@ func ListTickets(ticketDir string, opts *ListTicketsOptions, diagOut io.Writer)
 	}
 
 	indexes := cache.FilterEntriesWithOpts(FilterEntriesOpts{
-		Status:    statusFilter,
-		Priority:  priorityFilter,
-		Type:      typeFilter,
-		Parent:    opts.Parent,
-		RootsOnly: opts.RootsOnly,
-		Limit:     opts.Limit,
-		Offset:    opts.Offset,
+		Status:     statusFilter,
+		StatusName: statusName,
+		Priority:   priorityFilter,
+		Type:       typeFilter,
+		Parent:     opts.Parent,
+		RootsOnly:  opts.RootsOnly,
+		Limit:      opts.Limit,
+		Offset:     opts.Offset,
 	})
 	if indexes == nil {
-		return nil, errOffsetOutOfBounds
+		return nil, ErrOffsetOutOfBounds
 	}
 
 	results := make([]Result, 0, len(indexes)+len(reconcileResults))
@@ -768,7 +813,7 @@ func filterTicketResults(results []Result, opts *ListTicketsOptions) ([]Result,
 	}
 
 	if opts.Offset > 0 && matchCount <= opts.Offset {
-		return nil, errOffsetOutOfBounds
+		return nil, ErrOffsetOutOfBounds
 	}
 
 	return filtered, nil
@@ -809,7 +854,7 @@ const cacheBuildWorkers = 16
 
 // buildCacheParallel builds the cache from scratch by parsing all ticket files in parallel.
 // If entries is nil, it reads the directory.
-func buildCacheParallel(ticketDir string, entries []os.DirEntry) ([]Result, error) {
+func buildCacheParallel(ticketDir string, entries []os.DirEntry, workflow *Workflow) ([]Result, error) {
 	if entries == nil {
 		var err error
 
@@ -853,7 +898,7 @@ func buildCacheParallel(ticketDir string, entries []os.DirEntry) ([]Result, erro
 		defer waitGroup.Done()
 
 		for job := range jobCh {
-			summary, parseErr := ParseTicketFrontmatter(job.path)
+			summary, parseErr := ParseTicketFrontmatter(job.path, workflow)
 			if parseErr != nil {
 				results[job.idx] = Result{Path: job.path, Err: parseErr}
 
@@ -903,7 +948,8 @@ func buildCacheParallel(ticketDir string, entries []os.DirEntry) ([]Result, erro
 }
 
 // BuildCacheParallelLocked rebuilds the cache in parallel while holding the lock.
-func BuildCacheParallelLocked(ticketDir string, entries []os.DirEntry) ([]Result, error) {
+// Statuses are checked against workflow as in [ParseTicketFrontmatter].
+func BuildCacheParallelLocked(ticketDir string, entries []os.DirEntry, workflow *Workflow) ([]Result, error) {
 	cachePath := filepath.Join(ticketDir, CacheFileName)
 
 	var results []Result
@@ -911,7 +957,7 @@ func BuildCacheParallelLocked(ticketDir string, entries []os.DirEntry) ([]Result
 	err := WithLock(cachePath, func() error {
 		var buildErr error
 
-		results, buildErr = buildCacheParallel(ticketDir, entries)
+		results, buildErr = buildCacheParallel(ticketDir, entries, workflow)
 
 		return buildErr
 	})
@@ -922,7 +968,7 @@ func BuildCacheParallelLocked(ticketDir string, entries []os.DirEntry) ([]Result
 	return results, nil
 }
 
-func reconcileCacheOnDisk(ticketDir string) ([]Result, error) {
+func reconcileCacheOnDisk(ticketDir string, workflow *Workflow) ([]Result, error) {
 	cachePath := filepath.Join(ticketDir, CacheFileName)
 
 	var reconcileResults []Result
@@ -931,7 +977,7 @@ func reconcileCacheOnDisk(ticketDir string) ([]Result, error) {
 		cache, err := LoadBinaryCache(ticketDir)
 		if err != nil {
 			// Missing or invalid cache - just rebuild from scratch.
-			results, rebuildErr := buildCacheParallel(ticketDir, nil)
+			results, rebuildErr := buildCacheParallel(ticketDir, nil, workflow)
 			if rebuildErr != nil {
 				return rebuildErr
 			}
@@ -975,7 +1021,7 @@ func reconcileCacheOnDisk(ticketDir string) ([]Result, error) {
 			// New file: parse and add.
 			path := filepath.Join(ticketDir, name)
 
-			summary, parseErr := ParseTicketFrontmatter(path)
+			summary, parseErr := ParseTicketFrontmatter(path, workflow)
 			if parseErr != nil {
 				reconcileResults = append(reconcileResults, Result{Path: path, Err: parseErr})
 
@@ -1034,8 +1080,10 @@ func reconcileCacheOnDisk(ticketDir string) ([]Result, error) {
 }
 
 // ParseTicketFrontmatter parses a ticket file and extracts the summary.
-// Reads only until the title line for efficiency.
-func ParseTicketFrontmatter(path string) (Summary, error) {
+// Reads only until the title line for efficiency. Besides the built-in
+// statuses, the status may be any status of workflow; pass nil to accept
+// only the built-in ones.
+func ParseTicketFrontmatter(path string, workflow *Workflow) (Summary, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return Summary{}, fmt.Errorf("opening ticket: %w", err)
@@ -1130,7 +1178,7 @@ func ParseTicketFrontmatter(path string) (Summary, error) {
 						return Summary{}, fmt.Errorf("%w: status (empty)", errInvalidFieldValue)
 					}
 
-					if !isValidTicketStatus(value) {
+					if !isValidTicketStatus(value) && (workflow == nil || !workflow.HasStatus(value)) {
 						return Summary{}, fmt.Errorf("%w: status %q", errInvalidFieldValue, value)
 					}
 
@@ -1197,6 +1245,16 @@ func ParseTicketFrontmatter(path string) (Summary, error) {
 
 					summary.Assignee = value
 
+				case "labels":
+					labels, parseErr := parseLabelsValue(value)
+					if parseErr != nil {
+						return Summary{}, parseErr
+					}
+
+					if len(labels) > 0 {
+						summary.Labels = labels
+					}
+
 				case StatusClosed:
 					if value == "" {
 						return Summary{}, fmt.Errorf("%w: closed (empty)", errInvalidFieldValue)
@@ -1294,10 +1352,30 @@ func parseInt(s string) (int, error) {
 }
 
 func parseBlockedByValue(value string) ([]string, error) {
+	return parseListValue("blocked-by", value)
+}
+
+func parseLabelsValue(value string) ([]string, error) {
+	labels, err := parseListValue("labels", value)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, label := range labels {
+		if !IsValidLabel(label) {
+			return nil, fmt.Errorf("%w: labels %q", errInvalidFieldValue, label)
+		}
+	}
+
+	return labels, nil
+}
+
+// parseListValue parses a "[a, b]" frontmatter list value of the given field.
+func parseListValue(field, value string) ([]string, error) {
 	value = strings.TrimSpace(value)
 
 	if value == "" {
-		return nil, fmt.Errorf("%w: blocked-by (invalid format)", errInvalidFieldValue)
+		return nil, fmt.Errorf("%w: %s (invalid format)", errInvalidFieldValue, field)
 	}
 
 	if value == "[]" {
@@ -1305,7 +1383,7 @@ func parseBlockedByValue(value string) ([]string, error) {
 	}
 
 	if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
-		return nil, fmt.Errorf("%w: blocked-by (missing brackets)", errInvalidFieldValue)
+		return nil, fmt.Errorf("%w: %s (missing brackets)", errInvalidFieldValue, field)
 	}
 
 	inner := strings.TrimPrefix(strings.TrimSuffix(value, "]"), "[")
@@ -1363,6 +1441,93 @@ func ReadTicketBlockedBy(path string) ([]string, error) {
 	return GetBlockedByFromContent(content)
 }
 
+// IsValidLabel reports whether label can be stored in the labels list:
+// non-empty, without whitespace, commas or brackets.
+func IsValidLabel(label string) bool {
+	return label != "" && !strings.ContainsAny(label, " \t\r\n,[]")
+}
+
+// GetFieldFromContent extracts a single-line frontmatter field from ticket
+// content. Returns "" if the field is not present.
+func GetFieldFromContent(content []byte, field string) string {
+	lines := strings.Split(string(content), "\n")
+	inFrontmatter := false
+	prefix := field + ": "
+
+	for _, line := range lines {
+		if line == frontmatterDelimiter {
+			if inFrontmatter {
+				break // End of frontmatter
+			}
+
+			inFrontmatter = true
+
+			continue
+		}
+
+		if inFrontmatter && strings.HasPrefix(line, prefix) {
+			return strings.TrimPrefix(line, prefix)
+		}
+	}
+
+	return ""
+}
+
+// SetFieldInContent sets a frontmatter field, replacing the existing line or
+// adding the field (after status) if it is not present yet.
+// Returns the new content or an error.
+func SetFieldInContent(content []byte, field, value string) ([]byte, error) {
+	lines := strings.Split(string(content), "\n")
+	inFrontmatter := false
+	prefix := field + ": "
+
+	for lineIdx, line := range lines {
+		if line == frontmatterDelimiter {
+			if inFrontmatter {
+				break // End of frontmatter
+			}
+
+			inFrontmatter = true
+
+			continue
+		}
+
+		if inFrontmatter && strings.HasPrefix(line, prefix) {
+			lines[lineIdx] = prefix + value
+
+			return []byte(strings.Join(lines, "\n")), nil
+		}
+	}
+
+	return AddFieldToContent(content, field, value)
+}
+
+// GetLabelsFromContent extracts the labels list from ticket content.
+// Returns nil if the ticket has no labels field.
+func GetLabelsFromContent(content []byte) ([]string, error) {
+	value := GetFieldFromContent(content, "labels")
+	if value == "" {
+		return nil, nil
+	}
+
+	return parseLabelsValue(value)
+}
+
+// UpdateLabelsInContent sets the labels list in ticket content.
+// An empty list removes the labels field.
+func UpdateLabelsInContent(content []byte, labels []string) ([]byte, error) {
+	if len(labels) == 0 {
+		result := RemoveFieldFromContent(content, "labels")
+		if result == nil {
+			return content, nil
+		}
+
+		return result, nil
+	}
+
+	return SetFieldInContent(content, "labels", formatLabels(labels))
+}
+
 // GetParentFromContent extracts the parent field from ticket content.
 func GetParentFromContent(content []byte) string {
 	lines := strings.Split(string(content), "\n")
@@ -0,0 +1,188 @@
+package ticket
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/tailscale/hujson"
+)
+
+// WorkflowFileName is the workflow config file inside the ticket directory.
+const WorkflowFileName = ".workflow.json"
+
+// Workflow errors.
+var (
+	ErrWorkflowInvalid      = errors.New("invalid workflow file")
+	ErrTransitionNotAllowed = errors.New("status transition not allowed")
+)
+
+// Workflow defines the ticket statuses and which status changes are allowed.
+//
+// The built-in statuses open, in_progress and closed always exist, because
+// commands like start, close and ready depend on them. A workflow file can
+// add statuses (e.g. "blocked", "review") and replace the transitions:
+//
+//	{
+//	  "statuses": ["open", "in_progress", "review", "closed"],
+//	  "transitions": {
+//	    "open": ["in_progress"],
+//	    "in_progress": ["review"],
+//	    "review": ["in_progress", "closed"],
+//	    "closed": ["open"]
+//	  }
+//	}
+type Workflow struct {
+	Statuses    []string            `json:"statuses"`
+	Transitions map[string][]string `json:"transitions"`
+}
+
+// DefaultWorkflow returns the built-in workflow:
+// open -> in_progress -> closed -> open.
+func DefaultWorkflow() Workflow {
+	return Workflow{
+		Statuses: []string{StatusOpen, StatusInProgress, StatusClosed},
+		Transitions: map[string][]string{
+			StatusOpen:       {StatusInProgress},
+			StatusInProgress: {StatusClosed},
+			StatusClosed:     {StatusOpen},
+		},
+	}
+}
+
+// LoadWorkflow loads the workflow file from ticketDir.
+// Returns DefaultWorkflow if the file doesn't exist.
+func LoadWorkflow(ticketDir string) (Workflow, error) {
+	path := filepath.Join(ticketDir, WorkflowFileName)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return DefaultWorkflow(), nil
+		}
+
+		return Workflow{}, fmt.Errorf("reading workflow: %w", err)
+	}
+
+	workflow, parseErr := parseWorkflow(data)
+	if parseErr != nil {
+		return Workflow{}, fmt.Errorf("%w %s: %w", ErrWorkflowInvalid, path, parseErr)
+	}
+
+	return workflow, nil
+}
+
+func parseWorkflow(data []byte) (Workflow, error) {
+	standardized, err := hujson.Standardize(data)
+	if err != nil {
+		return Workflow{}, fmt.Errorf("invalid JSONC: %w", err)
+	}
+
+	var workflow Workflow
+
+	unmarshalErr := json.Unmarshal(standardized, &workflow)
+	if unmarshalErr != nil {
+		return Workflow{}, fmt.Errorf("invalid JSON: %w", unmarshalErr)
+	}
+
+	validateErr := workflow.validate()
+	if validateErr != nil {
+		return Workflow{}, validateErr
+	}
+
+	return workflow, nil
+}
+
+// isStatusToken reports whether s is usable as a status in frontmatter.
+func isStatusToken(s string) bool {
+	if s == "" || s[0] < 'a' || s[0] > 'z' {
+		return false
+	}
+
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' && r != '-' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// HasStatus reports whether status is defined by the workflow.
+func (w *Workflow) HasStatus(status string) bool {
+	return slices.Contains(w.Statuses, status)
+}
+
+// Next returns the statuses a ticket in status "from" may move to.
+func (w *Workflow) Next(from string) []string {
+	return w.Transitions[from]
+}
+
+// Allows reports whether the workflow permits moving from one status to another.
+func (w *Workflow) Allows(from, to string) bool {
+	return slices.Contains(w.Transitions[from], to)
+}
+
+// FormatNext returns the allowed next statuses of "from" for error messages.
+func (w *Workflow) FormatNext(from string) string {
+	next := w.Next(from)
+	if len(next) == 0 {
+		return "none"
+	}
+
+	return strings.Join(next, ", ")
+}
+
+// CheckTransition returns ErrTransitionNotAllowed, naming the allowed next
+// statuses, if the workflow doesn't permit moving from one status to another.
+func (w *Workflow) CheckTransition(from, to string) error {
+	if w.Allows(from, to) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s -> %s (allowed next: %s)", ErrTransitionNotAllowed, from, to, w.FormatNext(from))
+}
+
+func (w *Workflow) validate() error {
+	seen := make(map[string]bool, len(w.Statuses))
+
+	for _, status := range w.Statuses {
+		if !isStatusToken(status) {
+			return fmt.Errorf("status %q must be lowercase letters, digits, '_' or '-'", status)
+		}
+
+		if seen[status] {
+			return fmt.Errorf("duplicate status %q", status)
+		}
+
+		seen[status] = true
+	}
+
+	for _, required := range validStatuses {
+		if !seen[required] {
+			return fmt.Errorf("missing built-in status %q", required)
+		}
+	}
+
+	for from, targets := range w.Transitions {
+		if !seen[from] {
+			return fmt.Errorf("transition from unknown status %q", from)
+		}
+
+		for _, to := range targets {
+			if !seen[to] {
+				return fmt.Errorf("transition %s -> %s: unknown status %q", from, to, to)
+			}
+
+			if to == from {
+				return fmt.Errorf("transition %s -> %s: status cannot transition to itself", from, to)
+			}
+		}
+	}
+
+	return nil
+}
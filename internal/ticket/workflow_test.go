@@ -0,0 +1,151 @@
+package ticket_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/calvinalkan/agent-task/internal/ticket"
+)
+
+func Test_Load_Workflow_Returns_Default_When_File_Missing(t *testing.T) {
+	t.Parallel()
+
+	workflow, err := ticket.LoadWorkflow(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadWorkflow failed: %v", err)
+	}
+
+	if !workflow.Allows(ticket.StatusOpen, ticket.StatusInProgress) {
+		t.Error("default workflow should allow open -> in_progress")
+	}
+
+	if workflow.Allows(ticket.StatusOpen, ticket.StatusClosed) {
+		t.Error("default workflow should not allow open -> closed")
+	}
+}
+
+func Test_Load_Workflow_Rejects_Invalid_Definitions_When_Loaded(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "missing built-in status",
+			content: `{"statuses": ["open", "closed"]}`,
+			wantErr: `missing built-in status "in_progress"`,
+		},
+		{
+			name:    "duplicate status",
+			content: `{"statuses": ["open", "in_progress", "closed", "open"]}`,
+			wantErr: `duplicate status "open"`,
+		},
+		{
+			name:    "invalid status token",
+			content: `{"statuses": ["open", "in_progress", "closed", "In Review"]}`,
+			wantErr: `status "In Review" must be lowercase`,
+		},
+		{
+			name:    "unknown transition source",
+			content: `{"statuses": ["open", "in_progress", "closed"], "transitions": {"review": ["open"]}}`,
+			wantErr: `transition from unknown status "review"`,
+		},
+		{
+			name:    "self transition",
+			content: `{"statuses": ["open", "in_progress", "closed"], "transitions": {"open": ["open"]}}`,
+			wantErr: "status cannot transition to itself",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+
+			err := os.WriteFile(filepath.Join(dir, ticket.WorkflowFileName), []byte(tt.content), 0o600)
+			if err != nil {
+				t.Fatalf("failed to write workflow: %v", err)
+			}
+
+			_, err = ticket.LoadWorkflow(dir)
+			if !errors.Is(err, ticket.ErrWorkflowInvalid) {
+				t.Fatalf("err=%v, want ErrWorkflowInvalid", err)
+			}
+
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err=%q, want substring %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_Check_Transition_Names_Allowed_Next_When_Not_Allowed(t *testing.T) {
+	t.Parallel()
+
+	workflow := ticket.DefaultWorkflow()
+
+	err := workflow.CheckTransition(ticket.StatusOpen, ticket.StatusClosed)
+	if !errors.Is(err, ticket.ErrTransitionNotAllowed) {
+		t.Fatalf("err=%v, want ErrTransitionNotAllowed", err)
+	}
+
+	if got, want := err.Error(), "status transition not allowed: open -> closed (allowed next: in_progress)"; got != want {
+		t.Errorf("err=%q, want=%q", got, want)
+	}
+}
+
+func Test_List_Tickets_Filters_Custom_Status_When_Workflow_Defines_It(t *testing.T) {
+	t.Parallel()
+
+	ticketDir := t.TempDir()
+	workflow := ticket.Workflow{Statuses: []string{"open", "in_progress", "review", "blocked", "closed"}}
+
+	createTestTicketFullCT(t, ticketDir, "a-review", "review", "Review", "task", 2, nil)
+	createTestTicketFullCT(t, ticketDir, "b-blocked", "blocked", "Blocked", "task", 2, nil)
+	createTestTicketFullCT(t, ticketDir, "c-open", ticket.StatusOpen, "Open", "task", 2, nil)
+
+	// First call builds the cache, second reads from it.
+	for range 2 {
+		results, listErr := ticket.ListTickets(ticketDir, &ticket.ListTicketsOptions{Status: "review", Workflow: &workflow}, nil)
+		if listErr != nil {
+			t.Fatalf("ListTickets failed: %v", listErr)
+		}
+
+		var ids []string
+
+		for _, result := range results {
+			if result.Err != nil {
+				t.Fatalf("unexpected parse error: %v", result.Err)
+			}
+
+			if result.Summary.Status != "review" {
+				t.Errorf("status=%q, want review", result.Summary.Status)
+			}
+
+			ids = append(ids, result.Summary.ID)
+		}
+
+		if !slices.Equal(ids, []string{"a-review"}) {
+			t.Errorf("ids=%v, want [a-review]", ids)
+		}
+	}
+}
+
+func Test_Parse_Ticket_Frontmatter_Rejects_Custom_Status_When_No_Workflow(t *testing.T) {
+	t.Parallel()
+
+	ticketDir := t.TempDir()
+	createTestTicketFullCT(t, ticketDir, "a-review", "review", "Review", "task", 2, nil)
+
+	_, err := ticket.ParseTicketFrontmatter(filepath.Join(ticketDir, "a-review.md"), nil)
+	if err == nil || !strings.Contains(err.Error(), `invalid field value: status "review"`) {
+		t.Fatalf("err=%v, want invalid status error", err)
+	}
+}
@@ -1,6 +1,7 @@
 package fs
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -30,7 +31,7 @@ func NewAtomicWriter(fs FS) *AtomicWriter {
 	return &AtomicWriter{fs: fs}
 }
 
-// AtomicWriteOptions configures WriteFile behavior.
+// AtomicWriteOptions configures [AtomicWriter.Write].
 type AtomicWriteOptions struct {
 	// SyncDir controls whether the parent directory is synced after rename.
 	// Default: true.
@@ -121,6 +122,19 @@ func (w *AtomicWriter) Write(path string, reader io.Reader, opts AtomicWriteOpti
 	return nil
 }
 
+// WriteFile writes data to path atomically and durably with permissions perm,
+// like [os.WriteFile]: temp file in the same directory, fsync, rename over
+// path, fsync of the parent directory. See [AtomicWriter.Write].
+//
+// On failure before the rename, path keeps its previous content and the temp
+// file is removed.
+func (w *AtomicWriter) WriteFile(path string, data []byte, perm os.FileMode) error {
+	return w.Write(path, bytes.NewReader(data), AtomicWriteOptions{
+		SyncDir: true,
+		Perm:    perm,
+	})
+}
+
 // WriteWithDefaults writes content atomically using default options.
 func (w *AtomicWriter) WriteWithDefaults(path string, r io.Reader) error {
 	return w.Write(path, r, w.DefaultOptions())
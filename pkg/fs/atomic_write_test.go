@@ -1,6 +1,8 @@
 package fs_test
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -36,3 +38,69 @@ func TestAtomicWriteFile_DurableAfterCrash(t *testing.T) {
 		t.Fatalf("content=%q, want %q", string(got), testContentHello)
 	}
 }
+
+func Test_AtomicWriter_WriteFile_Keeps_Old_Content_When_A_Step_Fails(t *testing.T) {
+	t.Parallel()
+
+	steps := map[string]fs.ChaosConfig{
+		"CreateTemp": {OpenFailRate: 1},
+		"Write":      {WriteFailRate: 1},
+		"Sync":       {SyncFailRate: 1},
+		"Rename":     {RenameFailRate: 1},
+	}
+
+	for name, config := range steps {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			path := filepath.Join(dir, "final.txt")
+
+			err := os.WriteFile(path, []byte("old"), 0o600)
+			if err != nil {
+				t.Fatalf("seed: %v", err)
+			}
+
+			writer := fs.NewAtomicWriter(fs.NewChaos(fs.NewReal(), 1, &config))
+
+			err = writer.WriteFile(path, []byte(testContentHello), 0o600)
+			if err == nil {
+				t.Fatal("WriteFile: want error")
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil || string(got) != "old" {
+				t.Fatalf("content=%q, %v; want old", got, err)
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil || len(entries) != 1 {
+				t.Fatalf("dir entries=%v, %v; want only final.txt", entries, err)
+			}
+		})
+	}
+}
+
+func Test_AtomicWriter_WriteFile_Replaces_Content_With_Perm(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "final.txt")
+	writer := fs.NewAtomicWriter(fs.NewReal())
+
+	for _, content := range []string{"old", testContentHello} {
+		err := writer.WriteFile(path, []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil || string(got) != testContentHello {
+		t.Fatalf("content=%q, %v; want %q", got, err, testContentHello)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("stat=%v, %v; want perm 0600", info, err)
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"math"
 	"math/rand/v2"
 	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -108,6 +111,48 @@ type ChaosConfig struct {
 	// EIO error, simulating a directory read that fails partway through.
 	ReadDirPartialRate float64
 
+	// DiskUsageFailRate controls how often FS.DiskUsage fails. Returns EACCES
+	// or EIO.
+	DiskUsageFailRate float64
+
+	// DiskNearlyFullRate controls how often FS.DiskUsage reports a nearly
+	// full filesystem: free space is replaced by a random value of at most 1%
+	// of the total (never more than the real free space), with a nil error.
+	// Use it to exercise capacity preflight checks.
+	DiskNearlyFullRate float64
+
+	// DiskQuotaBytes caps the total bytes File.Write (and so FS.WriteFile)
+	// may write through this Chaos. Once the writes add up to the quota, the
+	// write crossing it stores only the bytes that fit and returns ENOSPC, like
+	// write(2) on a full disk; later writes fail with ENOSPC without writing.
+	// Deterministic: independent of the rates and the seed.
+	//
+	// Bytes are counted cumulatively: Remove, truncation and overwrites do not
+	// free quota. Writes in [ChaosModeNoOp] are neither counted nor limited.
+	// 0 (default) disables the quota.
+	DiskQuotaBytes int64
+
+	// ErrnoWeights overrides which errno is injected, per operation. Keys are
+	// operation names as in [TraceEvent.Op] ("open", "create", "readfile",
+	// "file.write", ...; FS.Exists uses "stat"). Each value maps errnos to
+	// relative weights; an errno is picked with probability weight/sum.
+	// Operations without an entry keep their default uniform errno set.
+	//
+	// Weights only shape which error appears, not how often: rates still
+	// decide whether a fault is injected. Errnos outside the default set are
+	// allowed, except ENOENT and EINTR which Chaos never injects.
+	//
+	// Example (force EDQUOT on writes):
+	//
+	//	ErrnoWeights: map[string]map[syscall.Errno]float64{
+	//		"file.write": {syscall.EDQUOT: 1},
+	//	}
+	ErrnoWeights map[string]map[syscall.Errno]float64
+
+	// Triggers inject deterministic faults independent of the rates above.
+	// See [ChaosTrigger].
+	Triggers []ChaosTrigger
+
 	// TraceCapacity is the max number of operations to keep in the trace log.
 	// Set to 0 (default) to disable tracing. Tracing records all operations
 	// including those where Chaos modified behavior without returning an error
@@ -115,6 +160,46 @@ type ChaosConfig struct {
 	TraceCapacity int
 }
 
+// ChaosTrigger fails one specific operation instead of a random one.
+//
+// A trigger is armed once a File.Sync on a path matching AfterSync succeeds
+// (or immediately if AfterSync is empty). Once armed, it counts operations Op
+// on paths matching Path; the operation after the first Skip matches fails
+// with Errno. A trigger fires at most once.
+//
+// This targets crash points like "the WAL was fsynced, then the first data
+// file write failed" that random rates reach only by luck.
+//
+// Patterns use [filepath.Match] syntax. A pattern without a separator is
+// matched against the base name, otherwise against the full path. An empty
+// pattern matches every path.
+//
+// Triggers only fire in [ChaosModeActive]; syncs in [ChaosModeNoOp] do not
+// arm them. Fired triggers are recorded in the trace with kind "trigger" and
+// counted in [ChaosStats.TriggerFails].
+type ChaosTrigger struct {
+	// Op is the operation to fail, named as in [TraceEvent.Op]: "open",
+	// "create", "readfile", "readdir", "mkdirall", "stat" (also FS.Exists),
+	// "remove", "removeall", "rename", or "file.read", "file.write",
+	// "file.close", "file.seek", "file.stat", "file.sync", "file.chmod", or
+	// "diskusage".
+	// For rename, Path is matched against the old path.
+	Op string
+
+	// Path selects which operations on Op count.
+	Path string
+
+	// AfterSync, if set, delays arming until a successful File.Sync on a
+	// matching path.
+	AfterSync string
+
+	// Skip is the number of matching operations to let through after arming.
+	Skip int
+
+	// Errno is the injected error. Default: EIO.
+	Errno syscall.Errno
+}
+
 // ChaosMode controls how [Chaos] behaves.
 type ChaosMode uint8
 
@@ -145,6 +230,10 @@ type ChaosStats struct {
 	SyncFails       int64
 	CloseFails      int64
 	ChmodFails      int64
+	DiskUsageFails  int64
+	DiskNearlyFull  int64
+	TriggerFails    int64
+	DiskQuotaFails  int64
 }
 
 // chaosError marks an error as intentionally injected by [Chaos].
@@ -232,14 +321,29 @@ func IsChaosErr(err error) bool {
 // Use [Chaos.SetMode] to control behavior and [Chaos.Stats] to inspect how many
 // faults were injected.
 type Chaos struct {
+	*chaosState
+
 	fs     FS
-	rng    *rand.Rand
 	config ChaosConfig
-	mode   atomic.Uint32
-	trace  *chaosTrace
+
+	// overrides are the [Chaos.WithPathConfig] configs, latest last.
+	overrides []chaosOverride
+}
+
+// chaosState is the state a [Chaos] shares with the views derived by
+// [Chaos.WithPathConfig].
+type chaosState struct {
+	rng   *rand.Rand
+	mode  atomic.Uint32
+	trace *chaosTrace
 
 	rngMu sync.Mutex
 
+	errnoWeights map[string]errnoTable
+
+	triggerMu sync.Mutex
+	triggers  []chaosTriggerState
+
 	// Counters for testing verification
 	openFails       atomic.Int64
 	readFails       atomic.Int64
@@ -257,21 +361,65 @@ type Chaos struct {
 	syncFails       atomic.Int64
 	closeFails      atomic.Int64
 	chmodFails      atomic.Int64
+	diskUsageFails  atomic.Int64
+	diskNearlyFull  atomic.Int64
+	triggerFails    atomic.Int64
+	diskQuotaFails  atomic.Int64
+
+	// quotaUsed is the number of bytes charged against DiskQuotaBytes.
+	quotaUsed atomic.Int64
+}
+
+// chaosOverride is one [Chaos.WithPathConfig] override.
+type chaosOverride struct {
+	pattern string
+	config  ChaosConfig
 }
 
 // NewChaos creates a new [Chaos] filesystem wrapping the given [FS].
 // The seed controls random fault injection for reproducibility.
-// Panics if underlying is nil.
+// Panics if underlying is nil, [ChaosConfig.ErrnoWeights] is invalid, or a
+// [ChaosTrigger] is invalid.
 func NewChaos(underlying FS, seed int64, config *ChaosConfig) *Chaos {
 	if underlying == nil {
 		panic("underlying fs is nil")
 	}
 
+	errnoWeights := make(map[string]errnoTable, len(config.ErrnoWeights))
+
+	for op, weights := range config.ErrnoWeights {
+		table, err := newErrnoTable(op, weights)
+		if err != nil {
+			panic(fmt.Sprintf("chaos errno weights for %q: %v", op, err))
+		}
+
+		errnoWeights[op] = table
+	}
+
+	triggers := make([]chaosTriggerState, 0, len(config.Triggers))
+
+	for i, trigger := range config.Triggers {
+		err := validateTrigger(trigger)
+		if err != nil {
+			panic(fmt.Sprintf("chaos trigger %d: %v", i, err))
+		}
+
+		if trigger.Errno == 0 {
+			trigger.Errno = syscall.EIO
+		}
+
+		triggers = append(triggers, chaosTriggerState{ChaosTrigger: trigger, armed: trigger.AfterSync == ""})
+	}
+
 	return &Chaos{
+		chaosState: &chaosState{
+			rng:          rand.New(rand.NewPCG(uint64(seed), uint64(seed))),
+			trace:        newChaosTrace(config.TraceCapacity),
+			errnoWeights: errnoWeights,
+			triggers:     triggers,
+		},
 		fs:     underlying,
-		rng:    rand.New(rand.NewPCG(uint64(seed), uint64(seed))),
 		config: *config,
-		trace:  newChaosTrace(config.TraceCapacity),
 	}
 }
 
@@ -285,6 +433,39 @@ func NewChaos(underlying FS, seed int64, config *ChaosConfig) *Chaos {
 //   - [ChaosModeNoOp]: pass all operations to the underlying filesystem.
 func (c *Chaos) SetMode(m ChaosMode) { c.mode.Store(uint32(m)) }
 
+// WithPathConfig returns a view of c whose fault rates are taken from config
+// for paths matching pattern, and from c's configuration otherwise. Use it
+// to make one file flaky (say, the WAL) while others stay healthy.
+//
+// pattern is matched like [ChaosTrigger.Path]: [filepath.Match] syntax,
+// against the base name unless it contains a separator; empty matches every
+// path. File handles use the path they were opened with; Rename uses the old
+// path. Views can be derived further; the latest matching override wins.
+//
+// Only the rates of config are used. Its ErrnoWeights, Triggers,
+// TraceCapacity and DiskQuotaBytes are ignored: the view shares c's RNG (so
+// runs stay deterministic per seed), mode, trace, triggers, quota and
+// [ChaosStats] counters.
+//
+// Panics if config is nil or pattern is malformed.
+func (c *Chaos) WithPathConfig(pattern string, config *ChaosConfig) *Chaos {
+	if config == nil {
+		panic("chaos path config is nil")
+	}
+
+	_, err := filepath.Match(pattern, "")
+	if err != nil {
+		panic(fmt.Sprintf("chaos path config pattern %q: %v", pattern, err))
+	}
+
+	return &Chaos{
+		chaosState: c.chaosState,
+		fs:         c.fs,
+		config:     c.config,
+		overrides:  append(slices.Clip(c.overrides), chaosOverride{pattern: pattern, config: *config}),
+	}
+}
+
 // Trace returns a formatted string of recent FS operations.
 // Returns an empty string if tracing is disabled (TraceCapacity == 0).
 func (c *Chaos) Trace() string {
@@ -297,6 +478,56 @@ func (c *Chaos) TraceEvents() []TraceEvent {
 	return c.trace.snapshot()
 }
 
+// AssertSequence checks that the trace contains ops, named as in
+// [TraceEvent.Op], as an ordered but not necessarily contiguous subsequence,
+// e.g. AssertSequence("file.write", "file.sync", "rename") for a durable
+// replace. Events match by name whatever their outcome, so a failed sync
+// still counts as a sync.
+//
+// Returns nil on a match, otherwise an error listing the ops that matched,
+// the first one missing, and the trace. Tracing must be enabled
+// (TraceCapacity > 0) and large enough to hold the events of interest, since
+// older events are dropped.
+func (c *Chaos) AssertSequence(ops ...string) error {
+	if c.trace == nil {
+		return errors.New("fs: AssertSequence: tracing disabled (TraceCapacity == 0)")
+	}
+
+	events := c.trace.snapshot()
+	next := 0
+
+	var lastSeq uint64
+
+	for _, e := range events {
+		if next == len(ops) {
+			break
+		}
+
+		if e.Op == ops[next] {
+			next++
+			lastSeq = e.Seq
+		}
+	}
+
+	if next == len(ops) {
+		return nil
+	}
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "fs: trace does not contain sequence %v: ", ops)
+
+	if next == 0 {
+		fmt.Fprintf(&sb, "no %q", ops[0])
+	} else {
+		fmt.Fprintf(&sb, "matched %v (last #%d), then no %q", ops[:next], lastSeq, ops[next])
+	}
+
+	fmt.Fprintf(&sb, "\ntrace:\n%s", c.trace.String())
+
+	return errors.New(sb.String())
+}
+
 // Stats returns the current fault injection counts.
 func (c *Chaos) Stats() ChaosStats {
 	return ChaosStats{
@@ -316,6 +547,10 @@ func (c *Chaos) Stats() ChaosStats {
 		SyncFails:       c.syncFails.Load(),
 		CloseFails:      c.closeFails.Load(),
 		ChmodFails:      c.chmodFails.Load(),
+		DiskUsageFails:  c.diskUsageFails.Load(),
+		DiskNearlyFull:  c.diskNearlyFull.Load(),
+		TriggerFails:    c.triggerFails.Load(),
+		DiskQuotaFails:  c.diskQuotaFails.Load(),
 	}
 }
 
@@ -327,7 +562,8 @@ func (c *Chaos) TotalFaults() int64 {
 		stats.PartialWrites + stats.ReadDirFails + stats.PartialReadDirs +
 		stats.RemoveFails + stats.RenameFails + stats.StatFails + stats.MkdirAllFails +
 		stats.FileStatFails + stats.SeekFails + stats.SyncFails + stats.CloseFails +
-		stats.ChmodFails
+		stats.ChmodFails + stats.DiskUsageFails + stats.DiskNearlyFull + stats.TriggerFails +
+		stats.DiskQuotaFails
 }
 
 // Open opens a file for reading with fault injection.
@@ -347,7 +583,7 @@ func (c *Chaos) Create(path string) (File, error) {
 // OpenFile opens a file with the specified flags and permissions with fault injection.
 func (c *Chaos) OpenFile(path string, flag int, perm os.FileMode) (File, error) {
 	op := chaosOpOpen
-	if flag&(os.O_WRONLY|os.O_RDWR|os.O_APPEND|os.O_CREATE|os.O_TRUNC) != 0 {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_APPEND|os.O_CREATE|os.O_EXCL|os.O_TRUNC) != 0 {
 		op = chaosOpCreate
 	}
 
@@ -368,7 +604,12 @@ func (c *Chaos) ReadFile(path string) ([]byte, error) {
 		return data, err
 	}
 
-	if c.should(mode, c.config.ReadFailRate) {
+	triggerErr := c.triggerFault("readfile", "read", path)
+	if triggerErr != nil {
+		return nil, triggerErr
+	}
+
+	if c.should(mode, c.configFor(path).ReadFailRate) {
 		op, errno := c.pickReadFileError()
 		c.readFails.Add(1)
 
@@ -388,7 +629,7 @@ func (c *Chaos) ReadFile(path string) ([]byte, error) {
 
 	// Partial read - return truncated data + error (like os.ReadFile returning
 	// bytes read so far after a later Read fails).
-	if c.should(mode, c.config.PartialReadRate) && len(data) > 1 {
+	if c.should(mode, c.configFor(path).PartialReadRate) && len(data) > 1 {
 		c.partialReads.Add(1)
 		cutoff := c.randIntn(len(data)-1) + 1
 		err := pathError("read", path, syscall.EIO)
@@ -457,7 +698,12 @@ func (c *Chaos) ReadDir(path string) ([]os.DirEntry, error) {
 		return entries, err
 	}
 
-	if c.should(mode, c.config.ReadDirFailRate) {
+	triggerErr := c.triggerFault("readdir", "readdir", path)
+	if triggerErr != nil {
+		return nil, triggerErr
+	}
+
+	if c.should(mode, c.configFor(path).ReadDirFailRate) {
 		errno := c.pickError("readdir")
 		c.readDirFails.Add(1)
 
@@ -477,7 +723,7 @@ func (c *Chaos) ReadDir(path string) ([]os.DirEntry, error) {
 
 	// Partial listing - return subset + error (like os.ReadDir returning entries
 	// read so far after a later directory read fails).
-	if c.should(mode, c.config.ReadDirPartialRate) && len(entries) > 1 {
+	if c.should(mode, c.configFor(path).ReadDirPartialRate) && len(entries) > 1 {
 		c.partialReadDirs.Add(1)
 		cutoff := c.randIntn(len(entries)-1) + 1
 		err := pathError("readdir", path, syscall.EIO)
@@ -495,6 +741,14 @@ func (c *Chaos) ReadDir(path string) ([]os.DirEntry, error) {
 	return entries, nil
 }
 
+// WalkDir walks a tree with fault injection. Every directory is listed via
+// [Chaos.ReadDir] and the root is checked via [Chaos.Stat], so ReadDirFailRate
+// and ReadDirPartialRate fail a walk midway or hide part of a directory, and
+// each listing shows up in the trace.
+func (c *Chaos) WalkDir(root string, fn fs.WalkDirFunc) error {
+	return walkDir(c, root, fn)
+}
+
 // MkdirAll creates a directory and parents with fault injection.
 func (c *Chaos) MkdirAll(path string, perm os.FileMode) error {
 	err := c.introduceChaos(path, faultMkdirAll)
@@ -583,7 +837,16 @@ func (c *Chaos) Rename(oldpath, newpath string) error {
 		return err
 	}
 
-	if c.should(mode, c.config.RenameFailRate) {
+	if trigger, ok := c.matchTrigger("rename", oldpath); ok {
+		err := linkError("rename", oldpath, newpath, trigger.Errno)
+
+		c.trace.add("rename", oldpath, "trigger", err, true,
+			TraceAttr{"newpath", newpath}, TraceAttr{"errno", trigger.Errno.Error()})
+
+		return err
+	}
+
+	if c.should(mode, c.configFor(oldpath).RenameFailRate) {
 		errno := c.pickError("rename")
 		c.renameFails.Add(1)
 
@@ -603,6 +866,63 @@ func (c *Chaos) Rename(oldpath, newpath string) error {
 	return err
 }
 
+// DiskUsage reports filesystem capacity with fault injection.
+func (c *Chaos) DiskUsage(path string) (uint64, uint64, error) {
+	mode := c.getMode()
+	if mode == ChaosModeNoOp {
+		free, total, err := c.fs.DiskUsage(path)
+
+		c.trace.add("diskusage", path, boolKind(err == nil), err, false,
+			TraceAttr{"free", strconv.FormatUint(free, 10)},
+			TraceAttr{"total", strconv.FormatUint(total, 10)})
+
+		return free, total, err
+	}
+
+	triggerErr := c.triggerFault("diskusage", "statfs", path)
+	if triggerErr != nil {
+		return 0, 0, triggerErr
+	}
+
+	if c.should(mode, c.configFor(path).DiskUsageFailRate) {
+		// EACCES: search permission denied on a path component
+		// EIO: I/O error (device/filesystem failure)
+		errno := c.pickErrno("diskusage", []syscall.Errno{syscall.EACCES, syscall.EIO})
+		c.diskUsageFails.Add(1)
+
+		err := pathError("statfs", path, errno)
+
+		c.trace.add("diskusage", path, "fail", err, true, TraceAttr{"errno", errno.Error()})
+
+		return 0, 0, err
+	}
+
+	free, total, err := c.fs.DiskUsage(path)
+	if err != nil {
+		c.trace.add("diskusage", path, "fail", err, false)
+
+		return 0, 0, err
+	}
+
+	if c.should(mode, c.configFor(path).DiskNearlyFullRate) && total > 0 {
+		c.diskNearlyFull.Add(1)
+		reported := min(free, c.randUint64N(total/100+1))
+
+		c.trace.add("diskusage", path, "nearly_full", nil, true,
+			TraceAttr{"free", strconv.FormatUint(reported, 10)},
+			TraceAttr{"real_free", strconv.FormatUint(free, 10)},
+			TraceAttr{"total", strconv.FormatUint(total, 10)})
+
+		return reported, total, nil
+	}
+
+	c.trace.add("diskusage", path, "ok", nil, false,
+		TraceAttr{"free", strconv.FormatUint(free, 10)},
+		TraceAttr{"total", strconv.FormatUint(total, 10)})
+
+	return free, total, nil
+}
+
 // getMode returns the current ChaosMode safely.
 func (c *Chaos) getMode() ChaosMode {
 	v := c.mode.Load()
@@ -631,7 +951,12 @@ func (c *Chaos) openWithChaos(path, op string, openFn func() (File, error)) (Fil
 		return &chaosFile{f: file, chaos: c, path: path}, nil
 	}
 
-	if c.should(mode, c.config.OpenFailRate) {
+	triggerErr := c.triggerFault(op, "open", path)
+	if triggerErr != nil {
+		return nil, triggerErr
+	}
+
+	if c.should(mode, c.configFor(path).OpenFailRate) {
 		errno := c.pickError(op)
 		c.openFails.Add(1)
 
@@ -697,6 +1022,11 @@ func (c *Chaos) introduceChaos(path string, kind faultKind) error {
 		return nil
 	}
 
+	triggerErr := c.triggerFault(string(kind), string(kind), path)
+	if triggerErr != nil {
+		return triggerErr
+	}
+
 	var (
 		rate    float64
 		counter *atomic.Int64
@@ -707,7 +1037,7 @@ func (c *Chaos) introduceChaos(path string, kind faultKind) error {
 	case faultStat:
 		// EACCES: permission denied (file/directory permissions or ACLs)
 		// EIO: I/O error (device/filesystem failure)
-		rate = c.config.StatFailRate
+		rate = c.configFor(path).StatFailRate
 		counter = &c.statFails
 		errnos = []syscall.Errno{syscall.EACCES, syscall.EIO}
 
@@ -717,7 +1047,7 @@ func (c *Chaos) introduceChaos(path string, kind faultKind) error {
 		// EBUSY: resource/device busy (in use)
 		// EIO: I/O error (device/filesystem failure)
 		// EROFS: read-only filesystem (writes/mutations are rejected)
-		rate = c.config.RemoveFailRate
+		rate = c.configFor(path).RemoveFailRate
 		counter = &c.removeFails
 		errnos = []syscall.Errno{syscall.EACCES, syscall.EPERM, syscall.EBUSY, syscall.EIO, syscall.EROFS}
 
@@ -728,7 +1058,7 @@ func (c *Chaos) introduceChaos(path string, kind faultKind) error {
 		// EDQUOT: disk quota exceeded
 		// EROFS: read-only filesystem (writes/mutations are rejected)
 		// ENOTDIR: a path component is not a directory
-		rate = c.config.MkdirAllFailRate
+		rate = c.configFor(path).MkdirAllFailRate
 		counter = &c.mkdirAllFails
 		errnos = []syscall.Errno{syscall.EACCES, syscall.EIO, syscall.ENOSPC, syscall.EDQUOT, syscall.EROFS, syscall.ENOTDIR}
 
@@ -739,7 +1069,7 @@ func (c *Chaos) introduceChaos(path string, kind faultKind) error {
 	if c.should(mode, rate) {
 		counter.Add(1)
 
-		errno := errnos[c.randIntn(len(errnos))]
+		errno := c.pickErrno(string(kind), errnos)
 		err := pathError(string(kind), path, errno)
 
 		c.trace.add(string(kind), path, "fail", err, true, TraceAttr{"errno", errno.Error()})
@@ -750,6 +1080,18 @@ func (c *Chaos) introduceChaos(path string, kind faultKind) error {
 	return nil
 }
 
+// configFor returns the configuration whose rates apply to path; see
+// [Chaos.WithPathConfig].
+func (c *Chaos) configFor(path string) *ChaosConfig {
+	for i := len(c.overrides) - 1; i >= 0; i-- {
+		if triggerPathMatches(c.overrides[i].pattern, path) {
+			return &c.overrides[i].config
+		}
+	}
+
+	return &c.config
+}
+
 // should returns true with the given probability when chaos is injecting.
 func (c *Chaos) should(mode ChaosMode, rate float64) bool {
 	if mode != ChaosModeActive {
@@ -759,6 +1101,113 @@ func (c *Chaos) should(mode ChaosMode, rate float64) bool {
 	return c.randFloat() < rate
 }
 
+// chaosTriggerState tracks the progress of one [ChaosTrigger].
+type chaosTriggerState struct {
+	ChaosTrigger
+
+	armed bool
+	seen  int
+	fired bool
+}
+
+// validateTrigger rejects triggers that could never fire.
+func validateTrigger(trigger ChaosTrigger) error {
+	if trigger.Op == "" {
+		return errors.New("op is empty")
+	}
+
+	if trigger.Skip < 0 {
+		return errors.New("skip is negative")
+	}
+
+	for _, pattern := range []string{trigger.Path, trigger.AfterSync} {
+		_, err := filepath.Match(pattern, "")
+		if err != nil {
+			return fmt.Errorf("pattern %q: %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// triggerPathMatches reports whether path matches a [ChaosTrigger] or
+// [Chaos.WithPathConfig] pattern.
+func triggerPathMatches(pattern, path string) bool {
+	if pattern == "" {
+		return true
+	}
+
+	if !strings.ContainsRune(pattern, filepath.Separator) {
+		path = filepath.Base(path)
+	}
+
+	ok, _ := filepath.Match(pattern, path) // validated in NewChaos
+
+	return ok
+}
+
+// observeSync arms triggers waiting for a sync on path.
+func (c *Chaos) observeSync(path string) {
+	if c.getMode() != ChaosModeActive || len(c.triggers) == 0 {
+		return
+	}
+
+	c.triggerMu.Lock()
+	defer c.triggerMu.Unlock()
+
+	for i := range c.triggers {
+		trigger := &c.triggers[i]
+		if !trigger.armed && triggerPathMatches(trigger.AfterSync, path) {
+			trigger.armed = true
+		}
+	}
+}
+
+// matchTrigger counts op on path against armed triggers and returns the
+// trigger that fires, if any. Callers must only call it in [ChaosModeActive].
+func (c *Chaos) matchTrigger(op, path string) (ChaosTrigger, bool) {
+	if len(c.triggers) == 0 {
+		return ChaosTrigger{}, false
+	}
+
+	c.triggerMu.Lock()
+	defer c.triggerMu.Unlock()
+
+	for i := range c.triggers {
+		trigger := &c.triggers[i]
+		if !trigger.armed || trigger.fired || trigger.Op != op || !triggerPathMatches(trigger.Path, path) {
+			continue
+		}
+
+		trigger.seen++
+		if trigger.seen <= trigger.Skip {
+			continue
+		}
+
+		trigger.fired = true
+		c.triggerFails.Add(1)
+
+		return trigger.ChaosTrigger, true
+	}
+
+	return ChaosTrigger{}, false
+}
+
+// triggerFault returns an injected [*fs.PathError] if a trigger fires for op
+// on path, nil otherwise. errOp is the operation name in the error.
+func (c *Chaos) triggerFault(op, errOp, path string) error {
+	trigger, ok := c.matchTrigger(op, path)
+	if !ok {
+		return nil
+	}
+
+	err := pathError(errOp, path, trigger.Errno)
+
+	c.trace.add(op, path, "trigger", err, true, TraceAttr{"errno", trigger.Errno.Error()})
+
+	return err
+}
+
 // randFloat returns a random float64 in [0.0, 1.0) (thread-safe).
 func (c *Chaos) randFloat() float64 {
 	c.rngMu.Lock()
@@ -777,6 +1226,15 @@ func (c *Chaos) randIntn(n int) int {
 	return result
 }
 
+// randUint64N returns a random uint64 in [0, n) (thread-safe).
+func (c *Chaos) randUint64N(n uint64) uint64 {
+	c.rngMu.Lock()
+	result := c.rng.Uint64N(n)
+	c.rngMu.Unlock()
+
+	return result
+}
+
 // pathError creates an injected [*fs.PathError] with the given operation, path, and errno.
 // The error is wrapped in [chaosError] so [IsChaosErr] can identify it, while
 // [errors.As] and helpers like [os.IsPermission] still work via unwrapping.
@@ -795,18 +1253,34 @@ func linkError(op, oldpath, newpath string, errno syscall.Errno) error {
 	return &chaosError{Err: le}
 }
 
-// pickRandom selects a random error from the slice.
-func (c *Chaos) pickRandom(errs []syscall.Errno) syscall.Errno {
-	return errs[c.randIntn(len(errs))]
+// pickErrno selects an injected errno for op: by [ChaosConfig.ErrnoWeights]
+// if configured for op, otherwise uniformly from defaults.
+func (c *Chaos) pickErrno(op string, defaults []syscall.Errno) syscall.Errno {
+	if table, ok := c.errnoWeights[op]; ok {
+		return table.pick(c.randFloat())
+	}
+
+	return defaults[c.randIntn(len(defaults))]
 }
 
 // pickReadFileError returns an injected error consistent with os.ReadFile:
 // the failure can be either an open-time error or a later read-time error.
 func (c *Chaos) pickReadFileError() (string, syscall.Errno) {
+	// With custom weights, EIO is a read-phase failure and anything else
+	// fails the open.
+	if _, ok := c.errnoWeights["readfile"]; ok {
+		errno := c.pickErrno("readfile", nil)
+		if errno == syscall.EIO {
+			return "read", errno
+		}
+
+		return chaosOpOpen, errno
+	}
+
 	// Only include errors that keep os.Is* classification working and avoid
 	// injecting ENOENT (missing-path errors should come from the wrapped FS).
 	if c.randFloat() < 0.5 {
-		return chaosOpOpen, c.pickRandom([]syscall.Errno{
+		return chaosOpOpen, c.pickErrno("readfile", []syscall.Errno{
 			syscall.EACCES,
 			syscall.EMFILE,
 			syscall.ENFILE,
@@ -821,15 +1295,16 @@ func (c *Chaos) pickReadFileError() (string, syscall.Errno) {
 //
 // Note: Some operations are handled by [Chaos.introduceChaos] or
 // [chaosFile.introduceChaos] instead, which have inline errno documentation.
+// [ChaosConfig.ErrnoWeights] overrides the defaults below.
 //
 // Operation → injected errnos:
 //   - open: EACCES, EIO, EMFILE, ENFILE, ENOTDIR
 //   - create: EACCES, EIO, ENOSPC, EDQUOT, EROFS, EMFILE, ENFILE, ENOTDIR
 //   - readdir: EACCES, EIO, ENOTDIR, EMFILE, ENFILE
 //   - rename: EACCES, EIO, ENOSPC, EXDEV, EROFS, EPERM
-//   - fdread: EIO only (avoid EACCES/ENOENT post-open; match os.File.Read shape)
-//   - fdwrite: EIO, ENOSPC, EDQUOT, EROFS (avoid EACCES/ENOENT post-open)
-//   - fdclose: EIO only (avoid EACCES/ENOENT post-open)
+//   - file.read: EIO only (avoid EACCES/ENOENT post-open; match os.File.Read shape)
+//   - file.write: EIO, ENOSPC, EDQUOT, EROFS (avoid EACCES/ENOENT post-open)
+//   - file.close: EIO only (avoid EACCES/ENOENT post-open)
 func (c *Chaos) pickError(op string) syscall.Errno {
 	switch op {
 	case chaosOpOpen:
@@ -838,7 +1313,7 @@ func (c *Chaos) pickError(op string) syscall.Errno {
 		// EMFILE: too many open files for this process (per-process FD limit)
 		// ENFILE: too many open files in the system (system-wide FD limit)
 		// ENOTDIR: expected a directory, but a path component is not a directory
-		return c.pickRandom([]syscall.Errno{
+		return c.pickErrno(op, []syscall.Errno{
 			syscall.EACCES,
 			syscall.EIO,
 			syscall.EMFILE,
@@ -855,7 +1330,7 @@ func (c *Chaos) pickError(op string) syscall.Errno {
 		// EMFILE: too many open files for this process (per-process FD limit)
 		// ENFILE: too many open files in the system (system-wide FD limit)
 		// ENOTDIR: expected a directory, but a path component is not a directory
-		return c.pickRandom([]syscall.Errno{
+		return c.pickErrno(op, []syscall.Errno{
 			syscall.EACCES,
 			syscall.EIO,
 			syscall.ENOSPC,
@@ -872,7 +1347,7 @@ func (c *Chaos) pickError(op string) syscall.Errno {
 		// ENOTDIR: expected a directory, but a path component is not a directory
 		// EMFILE: too many open files for this process (per-process FD limit)
 		// ENFILE: too many open files in the system (system-wide FD limit)
-		return c.pickRandom([]syscall.Errno{
+		return c.pickErrno(op, []syscall.Errno{
 			syscall.EACCES,
 			syscall.EIO,
 			syscall.ENOTDIR,
@@ -887,7 +1362,7 @@ func (c *Chaos) pickError(op string) syscall.Errno {
 		// EXDEV: cross-device link (rename across filesystems/mount points)
 		// EROFS: read-only filesystem (writes/mutations are rejected)
 		// EPERM: operation not permitted (policy/flags disallow the operation)
-		return c.pickRandom([]syscall.Errno{
+		return c.pickErrno(op, []syscall.Errno{
 			syscall.EACCES,
 			syscall.EIO,
 			syscall.ENOSPC,
@@ -896,13 +1371,13 @@ func (c *Chaos) pickError(op string) syscall.Errno {
 			syscall.EPERM,
 		})
 
-	case "fdwrite":
+	case "file.write":
 		// EIO: I/O error (device/filesystem failure)
 		// ENOSPC: no space left on device
 		// EDQUOT: disk quota exceeded
 		// EROFS: read-only filesystem (writes/mutations are rejected)
 		// Avoid EACCES/ENOENT post-open.
-		return c.pickRandom([]syscall.Errno{
+		return c.pickErrno(op, []syscall.Errno{
 			syscall.EIO,
 			syscall.ENOSPC,
 			syscall.EDQUOT,
@@ -910,11 +1385,82 @@ func (c *Chaos) pickError(op string) syscall.Errno {
 		})
 
 	default:
-		// For fdread/fdclose: EIO only to avoid EACCES/ENOENT post-open; match os.File.Read shape
+		// For file.read/file.close: EIO only to avoid EACCES/ENOENT post-open; match os.File.Read shape
+		if _, ok := c.errnoWeights[op]; ok {
+			return c.pickErrno(op, nil)
+		}
+
 		return syscall.EIO
 	}
 }
 
+// chaosOps lists the operation names accepted as [ChaosConfig.ErrnoWeights] keys.
+var chaosOps = map[string]bool{
+	chaosOpOpen: true, chaosOpCreate: true, "readfile": true, "readdir": true,
+	"rename": true, "diskusage": true, "stat": true, "remove": true,
+	"removeall": true, "mkdirall": true, "file.read": true, "file.write": true,
+	"file.close": true, "file.seek": true, "file.stat": true, "file.sync": true,
+	"file.chmod": true,
+}
+
+// errnoTable is a weighted errno distribution. Errnos are sorted so a pick
+// depends only on the random value, not on map iteration order.
+type errnoTable struct {
+	errnos     []syscall.Errno
+	cumulative []float64 // running weight sums, last element is the total
+}
+
+func newErrnoTable(op string, weights map[syscall.Errno]float64) (errnoTable, error) {
+	if !chaosOps[op] {
+		return errnoTable{}, errors.New("unknown operation")
+	}
+
+	errnos := make([]syscall.Errno, 0, len(weights))
+
+	for errno, weight := range weights {
+		if errno == syscall.ENOENT || errno == syscall.EINTR {
+			return errnoTable{}, fmt.Errorf("%v is never injected", errno)
+		}
+
+		if math.IsNaN(weight) || math.IsInf(weight, 0) || weight < 0 {
+			return errnoTable{}, fmt.Errorf("invalid weight %v for %v", weight, errno)
+		}
+
+		if weight > 0 {
+			errnos = append(errnos, errno)
+		}
+	}
+
+	if len(errnos) == 0 {
+		return errnoTable{}, errors.New("no positive weights")
+	}
+
+	slices.Sort(errnos)
+
+	table := errnoTable{errnos: errnos, cumulative: make([]float64, len(errnos))}
+	total := 0.0
+
+	for i, errno := range errnos {
+		total += weights[errno]
+		table.cumulative[i] = total
+	}
+
+	return table, nil
+}
+
+// pick maps r in [0.0, 1.0) to an errno.
+func (t errnoTable) pick(r float64) syscall.Errno {
+	target := r * t.cumulative[len(t.cumulative)-1]
+
+	for i, sum := range t.cumulative {
+		if target < sum {
+			return t.errnos[i]
+		}
+	}
+
+	return t.errnos[len(t.errnos)-1]
+}
+
 // chaosFile wraps a [File] and injects faults on Read/Write.
 type chaosFile struct {
 	f     File
@@ -936,8 +1482,13 @@ func (cf *chaosFile) Read(buf []byte) (int, error) {
 		return n, err
 	}
 
-	if cf.chaos.should(mode, cf.chaos.config.ReadFailRate) {
-		errno := cf.chaos.pickError("fdread")
+	triggerErr := cf.chaos.triggerFault("file.read", "read", cf.path)
+	if triggerErr != nil {
+		return 0, triggerErr
+	}
+
+	if cf.chaos.should(mode, cf.chaos.configFor(cf.path).ReadFailRate) {
+		errno := cf.chaos.pickError("file.read")
 		cf.chaos.readFails.Add(1)
 		err := pathError("read", cf.path, errno)
 
@@ -950,7 +1501,7 @@ func (cf *chaosFile) Read(buf []byte) (int, error) {
 	// Partial read: return a short read WITHOUT skipping bytes.
 	// This must limit the underlying read, not just shrink the returned count,
 	// otherwise the file offset advances too far and callers silently lose data.
-	if cf.chaos.should(mode, cf.chaos.config.PartialReadRate) && len(buf) > 1 {
+	if cf.chaos.should(mode, cf.chaos.configFor(cf.path).PartialReadRate) && len(buf) > 1 {
 		cf.chaos.partialReads.Add(1)
 		cutoff := cf.chaos.randIntn(len(buf)-1) + 1 // [1, len(buf)-1]
 
@@ -984,8 +1535,22 @@ func (cf *chaosFile) Write(data []byte) (int, error) {
 		return n, err
 	}
 
-	if cf.chaos.should(mode, cf.chaos.config.WriteFailRate) {
-		errno := cf.chaos.pickError("fdwrite")
+	triggerErr := cf.chaos.triggerFault("file.write", "write", cf.path)
+	if triggerErr != nil {
+		return 0, triggerErr
+	}
+
+	allowed := cf.chaos.reserveQuota(len(data))
+	if allowed < len(data) {
+		return cf.writeOverQuota(data[:allowed], len(data))
+	}
+
+	// Charge only what the write below actually stores.
+	wrote := 0
+	defer func() { cf.chaos.refundQuota(len(data) - wrote) }()
+
+	if cf.chaos.should(mode, cf.chaos.configFor(cf.path).WriteFailRate) {
+		errno := cf.chaos.pickError("file.write")
 		cf.chaos.writeFails.Add(1)
 		err := pathError("write", cf.path, errno)
 
@@ -996,11 +1561,13 @@ func (cf *chaosFile) Write(data []byte) (int, error) {
 	}
 
 	// Partial write
-	if cf.chaos.should(mode, cf.chaos.config.PartialWriteRate) && len(data) > 1 {
+	if cf.chaos.should(mode, cf.chaos.configFor(cf.path).PartialWriteRate) && len(data) > 1 {
 		cf.chaos.partialWrites.Add(1)
 		cutoff := cf.chaos.randIntn(len(data)-1) + 1 // [1, len(data)-1]
 
-		wrote, err := cf.f.Write(data[:cutoff])
+		var err error
+
+		wrote, err = cf.f.Write(data[:cutoff])
 		if err != nil {
 			cf.chaos.trace.add("file.write", cf.path, "fail", err, false,
 				TraceAttr{"n", strconv.Itoa(wrote)})
@@ -1011,7 +1578,7 @@ func (cf *chaosFile) Write(data []byte) (int, error) {
 		// Some portion of partial writes should look like a "short write without an errno"
 		// (io.ErrShortWrite). In the stdlib, this is the fallback when a write returns
 		// n != len(b) without a syscall error.
-		if cf.chaos.randFloat() < cf.chaos.config.ShortWriteRate {
+		if cf.chaos.randFloat() < cf.chaos.configFor(cf.path).ShortWriteRate {
 			err := &chaosError{Err: io.ErrShortWrite}
 
 			cf.chaos.trace.add("file.write", cf.path, "short_write", err, true,
@@ -1021,7 +1588,7 @@ func (cf *chaosFile) Write(data []byte) (int, error) {
 			return wrote, err
 		}
 
-		errno := cf.chaos.pickError("fdwrite")
+		errno := cf.chaos.pickError("file.write")
 		err = pathError("write", cf.path, errno)
 
 		cf.chaos.trace.add("file.write", cf.path, "partial_write", err, true,
@@ -1032,12 +1599,43 @@ func (cf *chaosFile) Write(data []byte) (int, error) {
 		return wrote, err
 	}
 
-	n, err := cf.f.Write(data)
+	wrote, err := cf.f.Write(data)
 
 	cf.chaos.trace.add("file.write", cf.path, boolKind(err == nil), err, false,
-		TraceAttr{"n", strconv.Itoa(n)})
+		TraceAttr{"n", strconv.Itoa(wrote)})
 
-	return n, err
+	return wrote, err
+}
+
+// writeOverQuota writes the part of a write that still fits in
+// DiskQuotaBytes and fails the rest with ENOSPC. fits was already charged.
+func (cf *chaosFile) writeOverQuota(fits []byte, requested int) (int, error) {
+	cf.chaos.diskQuotaFails.Add(1)
+
+	wrote := 0
+
+	if len(fits) > 0 {
+		n, err := cf.f.Write(fits)
+		cf.chaos.refundQuota(len(fits) - n)
+
+		if err != nil {
+			cf.chaos.trace.add("file.write", cf.path, "fail", err, false,
+				TraceAttr{"n", strconv.Itoa(n)})
+
+			return n, err
+		}
+
+		wrote = n
+	}
+
+	err := pathError("write", cf.path, syscall.ENOSPC)
+
+	cf.chaos.trace.add("file.write", cf.path, "quota_exceeded", err, true,
+		TraceAttr{"n", strconv.Itoa(wrote)},
+		TraceAttr{"requested", strconv.Itoa(requested)},
+		TraceAttr{"quota", strconv.FormatInt(cf.chaos.config.DiskQuotaBytes, 10)})
+
+	return wrote, err
 }
 
 func (cf *chaosFile) Close() error {
@@ -1050,7 +1648,14 @@ func (cf *chaosFile) Close() error {
 		return err
 	}
 
-	injectClose := cf.chaos.should(mode, cf.chaos.config.CloseFailRate)
+	triggerErr := cf.chaos.triggerFault("file.close", "close", cf.path)
+	if triggerErr != nil {
+		_ = cf.f.Close() // release the descriptor; the injected error wins
+
+		return triggerErr
+	}
+
+	injectClose := cf.chaos.should(mode, cf.chaos.configFor(cf.path).CloseFailRate)
 
 	// Always close the underlying file to avoid descriptor leaks, even when
 	// returning an injected error.
@@ -1063,7 +1668,7 @@ func (cf *chaosFile) Close() error {
 
 	if injectClose {
 		cf.chaos.closeFails.Add(1)
-		errno := cf.chaos.pickError("fdclose")
+		errno := cf.chaos.pickError("file.close")
 		err := pathError("close", cf.path, errno)
 
 		cf.chaos.trace.add("file.close", cf.path, "fail", err, true,
@@ -1124,6 +1729,10 @@ func (cf *chaosFile) Sync() error {
 
 	cf.chaos.trace.add("file.sync", cf.path, boolKind(err == nil), err, false)
 
+	if err == nil {
+		cf.chaos.observeSync(cf.path)
+	}
+
 	return err
 }
 
@@ -1141,6 +1750,31 @@ func (cf *chaosFile) Chmod(mode os.FileMode) error {
 	return err
 }
 
+// reserveQuota charges up to n bytes against DiskQuotaBytes and returns how
+// many were charged (n if no quota is set).
+func (c *Chaos) reserveQuota(n int) int {
+	quota := c.config.DiskQuotaBytes
+	if quota <= 0 {
+		return n
+	}
+
+	for {
+		used := c.quotaUsed.Load()
+		allowed := int(min(int64(n), max(quota-used, 0)))
+
+		if c.quotaUsed.CompareAndSwap(used, used+int64(allowed)) {
+			return allowed
+		}
+	}
+}
+
+// refundQuota returns n charged bytes that were not written.
+func (c *Chaos) refundQuota(n int) {
+	if c.config.DiskQuotaBytes > 0 && n > 0 {
+		c.quotaUsed.Add(-int64(n))
+	}
+}
+
 // introduceChaos checks if a fault should be injected for file handle operations.
 // Returns a non-nil error if a fault was injected, nil otherwise.
 func (cf *chaosFile) introduceChaos(kind fileFaultKind) error {
@@ -1149,6 +1783,11 @@ func (cf *chaosFile) introduceChaos(kind fileFaultKind) error {
 		return nil
 	}
 
+	triggerErr := cf.chaos.triggerFault("file."+string(kind), string(kind), cf.path)
+	if triggerErr != nil {
+		return triggerErr
+	}
+
 	var (
 		rate    float64
 		counter *atomic.Int64
@@ -1158,13 +1797,13 @@ func (cf *chaosFile) introduceChaos(kind fileFaultKind) error {
 	switch kind {
 	case fileFaultSeek:
 		// EIO: I/O error (avoid EACCES/ENOENT post-open)
-		rate = cf.chaos.config.SeekFailRate
+		rate = cf.chaos.configFor(cf.path).SeekFailRate
 		counter = &cf.chaos.seekFails
 		errnos = []syscall.Errno{syscall.EIO}
 
 	case fileFaultStat:
 		// EIO: I/O error (avoid EACCES/ENOENT post-open)
-		rate = cf.chaos.config.FileStatFailRate
+		rate = cf.chaos.configFor(cf.path).FileStatFailRate
 		counter = &cf.chaos.fileStatFails
 		errnos = []syscall.Errno{syscall.EIO}
 
@@ -1174,7 +1813,7 @@ func (cf *chaosFile) introduceChaos(kind fileFaultKind) error {
 		// EDQUOT: disk quota exceeded
 		// EROFS: read-only filesystem (writes/mutations are rejected)
 		// fsync can surface delayed write failures
-		rate = cf.chaos.config.SyncFailRate
+		rate = cf.chaos.configFor(cf.path).SyncFailRate
 		counter = &cf.chaos.syncFails
 		errnos = []syscall.Errno{syscall.EIO, syscall.ENOSPC, syscall.EDQUOT, syscall.EROFS}
 
@@ -1183,7 +1822,7 @@ func (cf *chaosFile) introduceChaos(kind fileFaultKind) error {
 		// EPERM: operation not permitted
 		// EIO: I/O error
 		// EROFS: read-only filesystem
-		rate = cf.chaos.config.ChmodFailRate
+		rate = cf.chaos.configFor(cf.path).ChmodFailRate
 		counter = &cf.chaos.chmodFails
 		errnos = []syscall.Errno{syscall.EACCES, syscall.EPERM, syscall.EIO, syscall.EROFS}
 
@@ -1194,7 +1833,7 @@ func (cf *chaosFile) introduceChaos(kind fileFaultKind) error {
 	if cf.chaos.should(mode, rate) {
 		counter.Add(1)
 
-		errno := errnos[cf.chaos.randIntn(len(errnos))]
+		errno := cf.chaos.pickErrno("file."+string(kind), errnos)
 		err := pathError(string(kind), cf.path, errno)
 
 		cf.chaos.trace.add("file."+string(kind), cf.path, "fail", err, true,
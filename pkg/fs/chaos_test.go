@@ -9,6 +9,7 @@ import (
 	"math/rand/v2"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"syscall"
@@ -424,6 +425,41 @@ func Test_Chaos_OpenFile_Uses_Open_Or_Create_Op_Based_On_Flags(t *testing.T) {
 			t.Fatalf("TraceEvents()[0].Op=%q, want %q\ntrace:\n%s", got, want, chaosFS.Trace())
 		}
 	})
+
+	t.Run("ExclusiveCreateIsFaulted", func(t *testing.T) {
+		t.Parallel()
+
+		exclPath := filepath.Join(dir, "excl.txt")
+		chaosFS := fs.NewChaos(fs.NewReal(), 0, &fs.ChaosConfig{OpenFailRate: 1.0, TraceCapacity: 10})
+
+		_, err := chaosFS.OpenFile(exclPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			t.Fatal("OpenFile unexpectedly succeeded")
+		}
+
+		if got, want := chaosFS.Stats().OpenFails, int64(1); got != want {
+			t.Fatalf("OpenFails=%d, want %d", got, want)
+		}
+
+		_, statErr := os.Stat(exclPath)
+		if !errors.Is(statErr, os.ErrNotExist) {
+			t.Fatalf("faulted exclusive create left a file: stat err=%v", statErr)
+		}
+
+		chaosFS.SetMode(fs.ChaosModeNoOp)
+
+		f, err := chaosFS.OpenFile(exclPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err != nil {
+			t.Fatalf("OpenFile (no-op): %v", err)
+		}
+
+		_ = f.Close()
+
+		_, err = chaosFS.OpenFile(exclPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if !errors.Is(err, os.ErrExist) {
+			t.Fatalf("OpenFile on existing path: err=%v, want os.ErrExist", err)
+		}
+	})
 }
 
 func Test_Chaos_Injects_MkdirAll_Error_When_MkdirAll_Fail_Rate_Is_One(t *testing.T) {
@@ -1578,6 +1614,157 @@ func Test_Chaos_ReadDir_Returns_Subset_And_Error_When_ReadDir_Partial_Rate_Is_On
 	}
 }
 
+func Test_Chaos_WalkDir_Matches_Real_When_Mode_Is_NoOp(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	realFS := fs.NewReal()
+
+	mustWriteFile(t, filepath.Join(dir, "a.txt"), []byte("x"))
+
+	err := os.Mkdir(filepath.Join(dir, "sub"), 0o750)
+	if err != nil {
+		t.Fatalf("Mkdir: %v", err)
+	}
+
+	mustWriteFile(t, filepath.Join(dir, "sub", "b.txt"), []byte("x"))
+
+	chaosFS := fs.NewChaos(realFS, 12345, &fs.ChaosConfig{ReadDirFailRate: 1.0, StatFailRate: 1.0})
+	chaosFS.SetMode(fs.ChaosModeNoOp)
+
+	want, err := walkPaths(realFS, dir)
+	if err != nil {
+		t.Fatalf("WalkDir(real): %v", err)
+	}
+
+	got, err := walkPaths(chaosFS, dir)
+	if err != nil {
+		t.Fatalf("WalkDir(chaos): %v", err)
+	}
+
+	if !slices.Equal(got, want) {
+		t.Fatalf("visited=%v, want %v", got, want)
+	}
+}
+
+func Test_Chaos_WalkDir_Reports_Error_Midway_When_ReadDir_Partial_Rate_Is_One(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	realFS := fs.NewReal()
+
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		mustWriteFile(t, filepath.Join(dir, name), []byte("x"))
+	}
+
+	chaosFS := fs.NewChaos(realFS, 12345, &fs.ChaosConfig{ReadDirPartialRate: 1.0})
+
+	var (
+		files   int
+		readErr error
+	)
+
+	err := chaosFS.WalkDir(dir, func(_ string, d iofs.DirEntry, err error) error {
+		if err != nil {
+			readErr = err
+
+			return nil // keep walking the entries read so far
+		}
+
+		if !d.IsDir() {
+			files++
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkDir: %v", err)
+	}
+
+	if !errors.Is(readErr, syscall.EIO) {
+		t.Fatalf("readErr=%v, want EIO", readErr)
+	}
+
+	if files == 0 || files >= 3 {
+		t.Fatalf("files=%d, want in (0,3)", files)
+	}
+
+	if got := chaosFS.Stats().PartialReadDirs; got != 1 {
+		t.Fatalf("PartialReadDirs=%d, want 1", got)
+	}
+}
+
+func Test_ChaosFile_Write_Returns_ENOSPC_After_Quota_When_DiskQuotaBytes_Set(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.txt")
+
+	chaosFS := fs.NewChaos(fs.NewReal(), 12345, &fs.ChaosConfig{
+		DiskQuotaBytes: 10,
+		TraceCapacity:  10,
+	})
+
+	err := chaosFS.WriteFile(filepath.Join(dir, "first.txt"), []byte("123456"), 0o644)
+	if err != nil {
+		t.Fatalf("WriteFile under quota: %v", err)
+	}
+
+	f, err := chaosFS.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	defer f.Close()
+
+	wrote, err := f.Write([]byte("abcdef"))
+	if !errors.Is(err, syscall.ENOSPC) || !fs.IsChaosErr(err) {
+		t.Fatalf("Write crossing quota: err=%v, want injected ENOSPC", err)
+	}
+
+	if wrote != 4 {
+		t.Fatalf("wrote=%d, want 4 (remaining quota)", wrote)
+	}
+
+	wrote, err = f.Write([]byte("x"))
+	if !errors.Is(err, syscall.ENOSPC) || wrote != 0 {
+		t.Fatalf("Write after quota: wrote=%d err=%v, want 0, ENOSPC", wrote, err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	if string(got) != "abcd" {
+		t.Fatalf("content=%q, want %q", got, "abcd")
+	}
+
+	if got := chaosFS.Stats().DiskQuotaFails; got != 2 {
+		t.Fatalf("DiskQuotaFails=%d, want 2", got)
+	}
+
+	if !strings.Contains(chaosFS.Trace(), "[CHAOS:quota_exceeded]") {
+		t.Fatalf("trace missing quota_exceeded:\n%s", chaosFS.Trace())
+	}
+}
+
+func Test_ChaosFile_Write_Ignores_Quota_When_Mode_Is_NoOp(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	chaosFS := fs.NewChaos(fs.NewReal(), 12345, &fs.ChaosConfig{DiskQuotaBytes: 1})
+	chaosFS.SetMode(fs.ChaosModeNoOp)
+
+	err := chaosFS.WriteFile(filepath.Join(dir, "test.txt"), []byte("over quota"), 0o644)
+	if err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	chaosFS.SetMode(fs.ChaosModeActive)
+
+	err = chaosFS.WriteFile(filepath.Join(dir, "test2.txt"), []byte("x"), 0o644)
+	if err != nil {
+		t.Fatalf("WriteFile within quota after NoOp writes: %v", err)
+	}
+}
+
 func Test_ChaosFile_Write_Returns_Prefix_And_Error_When_Partial_Write_Rate_Is_One(t *testing.T) {
 	t.Parallel()
 	dir := t.TempDir()
@@ -2079,6 +2266,103 @@ func Test_IsChaosErr_Returns_False_When_Error_Is_Real(t *testing.T) {
 	}
 }
 
+func Test_Chaos_AssertSequence_Matches_Ordered_Subsequence_When_Traced(t *testing.T) {
+	t.Parallel()
+
+	chaosFS := fs.NewChaos(fs.NewReal(), 0, &fs.ChaosConfig{TraceCapacity: 100})
+	chaosFS.SetMode(fs.ChaosModeNoOp)
+
+	dir := t.TempDir()
+	tmp := filepath.Join(dir, "a.tmp")
+
+	f, err := chaosFS.Create(tmp)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	_, _ = f.Write([]byte(testContentHello))
+	_ = f.Sync()
+	_ = f.Close()
+
+	err = chaosFS.Rename(tmp, filepath.Join(dir, "a.txt"))
+	if err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+
+	err = chaosFS.AssertSequence("file.write", "file.sync", "rename")
+	if err != nil {
+		t.Fatalf("AssertSequence: %v", err)
+	}
+
+	err = chaosFS.AssertSequence("create", "rename")
+	if err != nil {
+		t.Fatalf("AssertSequence non-contiguous: %v", err)
+	}
+
+	err = chaosFS.AssertSequence("rename", "file.sync")
+	if err == nil {
+		t.Fatal("AssertSequence out of order: want error")
+	}
+
+	if !strings.Contains(err.Error(), `matched [rename]`) || !strings.Contains(err.Error(), `no "file.sync"`) {
+		t.Fatalf("AssertSequence diff = %v", err)
+	}
+
+	untraced := fs.NewChaos(fs.NewReal(), 0, &fs.ChaosConfig{})
+
+	err = untraced.AssertSequence("stat")
+	if err == nil || !strings.Contains(err.Error(), "tracing disabled") {
+		t.Fatalf("AssertSequence without trace: got %v, want tracing disabled", err)
+	}
+}
+
+func Test_Chaos_WithPathConfig_Faults_Only_Matching_Paths_When_Override_Set(t *testing.T) {
+	t.Parallel()
+
+	base := fs.NewChaos(fs.NewReal(), 1, &fs.ChaosConfig{TraceCapacity: 100})
+	flaky := base.WithPathConfig("wal", &fs.ChaosConfig{WriteFailRate: 1})
+
+	dir := t.TempDir()
+
+	err := flaky.WriteFile(filepath.Join(dir, "wal"), []byte(testContentHello), 0o600)
+	if err == nil {
+		t.Fatal("WriteFile wal: want injected error")
+	}
+
+	err = flaky.WriteFile(filepath.Join(dir, "doc.md"), []byte(testContentHello), 0o600)
+	if err != nil {
+		t.Fatalf("WriteFile doc.md: %v", err)
+	}
+
+	err = base.WriteFile(filepath.Join(dir, "wal"), []byte(testContentHello), 0o600)
+	if err != nil {
+		t.Fatalf("WriteFile wal through base: %v", err)
+	}
+
+	if got := base.Stats().WriteFails; got != 1 {
+		t.Fatalf("base WriteFails=%d, want 1 (shared with view)", got)
+	}
+
+	base.SetMode(fs.ChaosModeNoOp)
+
+	err = flaky.WriteFile(filepath.Join(dir, "wal"), []byte(testContentHello), 0o600)
+	if err != nil {
+		t.Fatalf("WriteFile wal in NoOp: %v", err)
+	}
+
+	if !strings.Contains(base.Trace(), "doc.md") {
+		t.Fatalf("base trace misses view ops:\n%s", base.Trace())
+	}
+
+	healed := flaky.WithPathConfig("*", &fs.ChaosConfig{})
+	base.SetMode(fs.ChaosModeActive)
+
+	err = healed.WriteFile(filepath.Join(dir, "wal"), []byte(testContentHello), 0o600)
+	if err != nil {
+		t.Fatalf("WriteFile wal with later override: %v", err)
+	}
+}
+
 func mustWriteFile(t *testing.T, path string, data []byte) {
 	t.Helper()
 
@@ -2913,3 +3197,293 @@ func Test_Chaos_Same_Seeds_Produce_Identical_Results_With_Random_Ops(t *testing.
 		}
 	}
 }
+
+func Test_Chaos_Fails_Op_Once_When_Trigger_Armed_By_Prior_Sync(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	chaos := fs.NewChaos(fs.NewReal(), 0, &fs.ChaosConfig{
+		TraceCapacity: 100,
+		Triggers: []fs.ChaosTrigger{
+			{Op: "file.write", Path: "*.md", AfterSync: "wal"},
+		},
+	})
+
+	// Not armed yet: nothing synced.
+	err := writeFileOnce(chaos, filepath.Join(dir, "before.md"), []byte(testContentHello))
+	if err != nil {
+		t.Fatalf("write before sync: %v", err)
+	}
+
+	wal, err := chaos.Create(filepath.Join(dir, "wal"))
+	if err != nil {
+		t.Fatalf("Create(wal): %v", err)
+	}
+
+	_, err = wal.Write([]byte(testContentHello))
+	if err != nil {
+		t.Fatalf("Write(wal): %v", err)
+	}
+
+	err = wal.Sync()
+	if err != nil {
+		t.Fatalf("Sync(wal): %v", err)
+	}
+
+	_ = wal.Close()
+
+	docPath := filepath.Join(dir, "doc.md")
+
+	err = writeFileOnce(chaos, docPath, []byte(testContentHello))
+	if !fs.IsChaosErr(err) || !errors.Is(err, syscall.EIO) {
+		t.Fatalf("write after sync: got %v, want injected EIO", err)
+	}
+
+	err = writeFileOnce(chaos, docPath, []byte(testContentHello))
+	if err != nil {
+		t.Fatalf("write after trigger fired: %v", err)
+	}
+
+	if got, want := chaos.Stats().TriggerFails, int64(1); got != want {
+		t.Fatalf("TriggerFails=%d, want %d", got, want)
+	}
+
+	var triggered []fs.TraceEvent
+
+	for _, event := range chaos.TraceEvents() {
+		if event.Kind == "trigger" {
+			triggered = append(triggered, event)
+		}
+	}
+
+	if len(triggered) != 1 || triggered[0].Op != "file.write" || triggered[0].Path != docPath || !triggered[0].Injected {
+		t.Fatalf("trigger events=%v, want one injected file.write on %s", triggered, docPath)
+	}
+}
+
+func Test_Chaos_Skips_Matches_And_Ignores_NoOp_Syncs_When_Trigger_Configured(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	walPath := filepath.Join(dir, "wal")
+	chaos := fs.NewChaos(fs.NewReal(), 0, &fs.ChaosConfig{
+		Triggers: []fs.ChaosTrigger{
+			{Op: "rename", Path: filepath.Join(dir, "*.tmp"), AfterSync: "wal", Skip: 1, Errno: syscall.ENOSPC},
+		},
+	})
+
+	syncWAL := func() {
+		t.Helper()
+
+		f, err := chaos.Create(walPath)
+		if err != nil {
+			t.Fatalf("Create(wal): %v", err)
+		}
+
+		defer func() { _ = f.Close() }()
+
+		err = f.Sync()
+		if err != nil {
+			t.Fatalf("Sync(wal): %v", err)
+		}
+	}
+
+	rename := func(name string) error {
+		tmp := filepath.Join(dir, name+".tmp")
+		mustWriteFile(t, tmp, []byte(testContentHello))
+
+		return chaos.Rename(tmp, filepath.Join(dir, name))
+	}
+
+	chaos.SetMode(fs.ChaosModeNoOp)
+	syncWAL()
+	chaos.SetMode(fs.ChaosModeActive)
+
+	err := rename("a")
+	if err != nil {
+		t.Fatalf("rename before arming: %v", err)
+	}
+
+	syncWAL()
+
+	err = rename("b")
+	if err != nil {
+		t.Fatalf("skipped rename: %v", err)
+	}
+
+	err = rename("c")
+
+	var linkErr *os.LinkError
+	if !errors.As(err, &linkErr) || !errors.Is(err, syscall.ENOSPC) || !fs.IsChaosErr(err) {
+		t.Fatalf("rename after skip: got %v, want injected ENOSPC *os.LinkError", err)
+	}
+
+	err = rename("d")
+	if err != nil {
+		t.Fatalf("rename after trigger fired: %v", err)
+	}
+}
+
+func Test_Chaos_DiskUsage_Injects_Faults_When_Rates_Are_One(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	_, realTotal, err := fs.NewReal().DiskUsage(dir)
+	if err != nil {
+		t.Fatalf("Real.DiskUsage: %v", err)
+	}
+
+	failing := fs.NewChaos(fs.NewReal(), 0, &fs.ChaosConfig{DiskUsageFailRate: 1.0})
+
+	_, _, err = failing.DiskUsage(dir)
+	if !fs.IsChaosErr(err) || (!errors.Is(err, syscall.EIO) && !errors.Is(err, syscall.EACCES)) {
+		t.Fatalf("DiskUsage: got %v, want injected EIO or EACCES", err)
+	}
+
+	full := fs.NewChaos(fs.NewReal(), 0, &fs.ChaosConfig{DiskNearlyFullRate: 1.0})
+
+	free, total, err := full.DiskUsage(dir)
+	if err != nil {
+		t.Fatalf("DiskUsage nearly full: %v", err)
+	}
+
+	if total != realTotal || free > total/100 {
+		t.Fatalf("free=%d total=%d, want total=%d and free <= 1%%", free, total, realTotal)
+	}
+
+	if got, want := full.Stats().DiskNearlyFull, int64(1); got != want {
+		t.Fatalf("DiskNearlyFull=%d, want %d", got, want)
+	}
+}
+
+func Test_Chaos_Injects_Weighted_Errno_When_ErrnoWeights_Configured(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	t.Run("SingleErrno", func(t *testing.T) {
+		t.Parallel()
+
+		chaos := fs.NewChaos(fs.NewReal(), 0, &fs.ChaosConfig{
+			WriteFailRate: 1.0,
+			StatFailRate:  1.0,
+			ErrnoWeights: map[string]map[syscall.Errno]float64{
+				"file.write": {syscall.EDQUOT: 1, syscall.EIO: 0},
+				"stat":       {syscall.EROFS: 1},
+			},
+		})
+
+		f, err := chaos.Create(filepath.Join(dir, "single.txt"))
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		defer func() { _ = f.Close() }()
+
+		for range 20 {
+			_, err = f.Write([]byte(testContentHello))
+			if !errors.Is(err, syscall.EDQUOT) {
+				t.Fatalf("Write: got %v, want EDQUOT", err)
+			}
+		}
+
+		_, err = chaos.Stat(dir)
+		if !errors.Is(err, syscall.EROFS) {
+			t.Fatalf("Stat: got %v, want EROFS", err)
+		}
+	})
+
+	t.Run("DeterministicMix", func(t *testing.T) {
+		t.Parallel()
+
+		run := func() []syscall.Errno {
+			chaos := fs.NewChaos(fs.NewReal(), 42, &fs.ChaosConfig{
+				RemoveFailRate: 1.0,
+				ErrnoWeights: map[string]map[syscall.Errno]float64{
+					"remove": {syscall.EBUSY: 3, syscall.EPERM: 1},
+				},
+			})
+
+			var got []syscall.Errno
+
+			for range 200 {
+				var errno syscall.Errno
+
+				err := chaos.Remove(filepath.Join(dir, "missing"))
+				if !errors.As(err, &errno) {
+					t.Fatalf("Remove: got %v, want errno", err)
+				}
+
+				got = append(got, errno)
+			}
+
+			return got
+		}
+
+		first, second := run(), run()
+		if !slices.Equal(first, second) {
+			t.Fatal("same seed produced different errno sequences")
+		}
+
+		busy := 0
+
+		for _, errno := range first {
+			switch errno {
+			case syscall.EBUSY:
+				busy++
+			case syscall.EPERM:
+			default:
+				t.Fatalf("unexpected errno %v", errno)
+			}
+		}
+
+		if busy < 100 || busy == len(first) {
+			t.Fatalf("EBUSY=%d of %d, want roughly 3:1 against EPERM", busy, len(first))
+		}
+	})
+}
+
+func Test_NewChaos_Panics_When_ErrnoWeights_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		weights map[string]map[syscall.Errno]float64
+	}{
+		{"UnknownOp", map[string]map[syscall.Errno]float64{"fdwrite": {syscall.EIO: 1}}},
+		{"NoPositiveWeight", map[string]map[syscall.Errno]float64{"file.write": {syscall.EIO: 0}}},
+		{"NegativeWeight", map[string]map[syscall.Errno]float64{"file.write": {syscall.EIO: 1, syscall.ENOSPC: -1}}},
+		{"NeverInjected", map[string]map[syscall.Errno]float64{"open": {syscall.ENOENT: 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatal("expected panic for invalid errno weights")
+				}
+			}()
+
+			_ = fs.NewChaos(fs.NewReal(), 0, &fs.ChaosConfig{ErrnoWeights: tt.weights})
+		})
+	}
+}
+
+func walkPaths(fsys fs.FS, root string) ([]string, error) {
+	var paths []string
+
+	err := fsys.WalkDir(root, func(path string, _ iofs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		paths = append(paths, path)
+
+		return nil
+	})
+
+	return paths, err
+}
@@ -3,6 +3,7 @@ package fs
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"maps"
 	"os"
 	"path/filepath"
@@ -319,6 +320,12 @@ func (c *Crash) ReadDir(path string) ([]os.DirEntry, error) {
 	return c.fs.ReadDir(abs)
 }
 
+// WalkDir implements [FS.WalkDir] via [Crash.Stat] and [Crash.ReadDir], so
+// each directory read is guarded like a direct call.
+func (c *Crash) WalkDir(root string, fn fs.WalkDirFunc) error {
+	return walkDir(c, root, fn)
+}
+
 // MkdirAll implements [FS.MkdirAll].
 func (c *Crash) MkdirAll(path string, perm os.FileMode) error {
 	err := c.guard(CrashOpMkdirAll, path, "", false)
@@ -491,6 +498,23 @@ func (c *Crash) Rename(oldpath, newpath string) error {
 	return nil
 }
 
+// DiskUsage implements [FS.DiskUsage].
+//
+// Capacity is that of the filesystem backing the crashfs work directory.
+func (c *Crash) DiskUsage(path string) (uint64, uint64, error) {
+	err := c.guard(CrashOpDiskUsage, path, "", false)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	abs, err := c.resolveAbs(path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return c.fs.DiskUsage(abs)
+}
+
 func (c *Crash) latchedTerminationLocked(op CrashOp, path, newPath string) (*CrashPanicError, int) {
 	if c.latchedPanic != nil {
 		if c.failpoint.action == CrashFailpointExit {
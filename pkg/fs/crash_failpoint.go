@@ -33,6 +33,7 @@ const (
 	CrashOpFileSync  CrashOp = "file.sync"
 	CrashOpFileClose CrashOp = "file.close"
 	CrashOpFileChmod CrashOp = "file.chmod"
+	CrashOpDiskUsage CrashOp = "diskusage"
 
 	crashOpCrash CrashOp = "crash"
 )
@@ -21,8 +21,11 @@
 package fs
 
 import (
+	"errors"
 	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
 )
 
 // File represents an OS-backed open file descriptor.
@@ -81,6 +84,7 @@ type File interface {
 //   - [Real]: production use, wraps [os] package
 //   - [Chaos]: testing use, injects random failures
 //   - [Crash]: testing use, simulates crash consistency
+//   - [Recorder], [Replayer]: debugging use, record and check operation logs
 //
 // All methods mirror their [os] package equivalents but can be intercepted
 // for testing with fault injection.
@@ -103,6 +107,7 @@ type FS interface {
 	//
 	// Common flags: [os.O_RDONLY], [os.O_WRONLY], [os.O_RDWR],
 	// [os.O_APPEND], [os.O_CREATE], [os.O_EXCL], [os.O_TRUNC].
+	// With O_CREATE|O_EXCL, an existing path fails with [os.ErrExist].
 	OpenFile(path string, flag int, perm os.FileMode) (File, error)
 
 	// ReadFile reads an entire file into memory. See [os.ReadFile].
@@ -122,6 +127,16 @@ type FS interface {
 	// Entries are sorted by name.
 	ReadDir(path string) ([]os.DirEntry, error)
 
+	// WalkDir walks the tree rooted at root, calling fn for each file and
+	// directory, root included. See [filepath.WalkDir]: entries are visited in
+	// lexical order, fn gets [fs.DirEntry] values without a stat per entry,
+	// and [fs.SkipDir] / [fs.SkipAll] prune the walk.
+	//
+	// If reading a directory fails, fn is called a second time for that
+	// directory with the error. If fn returns nil, the walk continues with
+	// the entries read before the failure, if any.
+	WalkDir(root string, fn fs.WalkDirFunc) error
+
 	// MkdirAll creates a directory and all parents. See [os.MkdirAll].
 	// No error if the directory already exists.
 	MkdirAll(path string, perm os.FileMode) error
@@ -145,6 +160,66 @@ type FS interface {
 	// Rename moves/renames a file or directory. See [os.Rename].
 	// Atomic on the same filesystem.
 	Rename(oldpath, newpath string) error
+
+	// DiskUsage returns the bytes available to unprivileged users and the
+	// total size of the filesystem containing path. See statfs(2).
+	// Use it to fail fast before large writes instead of hitting ENOSPC midway.
+	DiskUsage(path string) (free, total uint64, err error)
+}
+
+// walkDir implements [FS.WalkDir] on top of fsys.Stat and fsys.ReadDir,
+// following [filepath.WalkDir]. Used by wrappers so every directory read goes
+// through their ReadDir (fault injection, crash guards).
+func walkDir(fsys FS, root string, fn fs.WalkDirFunc) error {
+	info, err := fsys.Stat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = walkDirEntry(fsys, root, fs.FileInfoToDirEntry(info), fn)
+	}
+
+	if errors.Is(err, fs.SkipDir) || errors.Is(err, fs.SkipAll) {
+		return nil
+	}
+
+	return err
+}
+
+func walkDirEntry(fsys FS, path string, d fs.DirEntry, fn fs.WalkDirFunc) error {
+	err := fn(path, d, nil)
+	if err != nil || !d.IsDir() {
+		if errors.Is(err, fs.SkipDir) && d.IsDir() {
+			err = nil
+		}
+
+		return err
+	}
+
+	entries, err := fsys.ReadDir(path)
+	if err != nil {
+		// Second call, to report the ReadDir error.
+		err = fn(path, d, err)
+		if err != nil {
+			if errors.Is(err, fs.SkipDir) {
+				err = nil
+			}
+
+			return err
+		}
+	}
+
+	for _, entry := range entries {
+		err = walkDirEntry(fsys, filepath.Join(path, entry.Name()), entry, fn)
+		if err != nil {
+			if errors.Is(err, fs.SkipDir) {
+				break
+			}
+
+			return err
+		}
+	}
+
+	return nil
 }
 
 // Compile-time interface checks.
@@ -3,6 +3,7 @@ package fs
 import (
 	"context"
 	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -1041,10 +1042,16 @@ func (stubLockFS) WriteFile(string, []byte, os.FileMode) error {
 func (stubLockFS) ReadDir(string) ([]os.DirEntry, error) {
 	panic("stubLockFS.ReadDir: not implemented")
 }
+func (stubLockFS) WalkDir(string, fs.WalkDirFunc) error {
+	panic("stubLockFS.WalkDir: not implemented")
+}
 func (stubLockFS) Exists(string) (bool, error) { panic("stubLockFS.Exists: not implemented") }
 func (stubLockFS) Remove(string) error         { panic("stubLockFS.Remove: not implemented") }
 func (stubLockFS) RemoveAll(string) error      { panic("stubLockFS.RemoveAll: not implemented") }
 func (stubLockFS) Rename(string, string) error { panic("stubLockFS.Rename: not implemented") }
+func (stubLockFS) DiskUsage(string) (uint64, uint64, error) {
+	panic("stubLockFS.DiskUsage: not implemented")
+}
 func (s stubLockFS) MkdirAll(path string, perm os.FileMode) error {
 	if s.mkdirAll != nil {
 		return s.mkdirAll(path, perm)
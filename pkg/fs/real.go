@@ -1,7 +1,12 @@
 package fs
 
 import (
+	"errors"
+	"io/fs"
 	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
 )
 
 // Real implements [FS] using the real filesystem.
@@ -48,6 +53,12 @@ func (*Real) ReadDir(path string) ([]os.DirEntry, error) {
 	return os.ReadDir(path)
 }
 
+// WalkDir is a passthrough wrapper for [filepath.WalkDir], which reads each
+// directory once and does not stat its entries.
+func (*Real) WalkDir(root string, fn fs.WalkDirFunc) error {
+	return filepath.WalkDir(root, fn)
+}
+
 // MkdirAll is a passthrough wrapper for [os.MkdirAll].
 func (*Real) MkdirAll(path string, perm os.FileMode) error {
 	return os.MkdirAll(path, perm)
@@ -93,5 +104,27 @@ func (*Real) Rename(oldpath, newpath string) error {
 	return os.Rename(oldpath, newpath)
 }
 
+// --- Capacity ---
+
+// DiskUsage reports capacity of the filesystem containing path via statfs(2).
+// Free counts blocks available to unprivileged users (f_bavail), so it may be
+// less than the filesystem's raw free space.
+func (*Real) DiskUsage(path string) (uint64, uint64, error) {
+	var st unix.Statfs_t
+
+	err := unix.Statfs(path, &st)
+	if err != nil {
+		return 0, 0, &os.PathError{Op: "statfs", Path: path, Err: err}
+	}
+
+	if st.Bsize <= 0 {
+		return 0, 0, &os.PathError{Op: "statfs", Path: path, Err: errors.New("invalid block size")}
+	}
+
+	bsize := uint64(st.Bsize)
+
+	return uint64(st.Bavail) * bsize, uint64(st.Blocks) * bsize, nil
+}
+
 // Compile-time interface check.
 var _ FS = (*Real)(nil)
@@ -1,13 +1,62 @@
 package fs_test
 
 import (
+	"errors"
+	iofs "io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/calvinalkan/agent-task/pkg/fs"
 )
 
+func Test_RealFS_WalkDir_Visits_Tree_In_Lexical_Order_When_Nested(t *testing.T) {
+	t.Parallel()
+
+	realFS := fs.NewReal()
+	dir := t.TempDir()
+
+	for _, rel := range []string{"b/2.txt", "a.txt", "b/1.txt", "b/c/3.txt"} {
+		path := filepath.Join(dir, rel)
+
+		err := os.MkdirAll(filepath.Dir(path), 0o750)
+		if err != nil {
+			t.Fatalf("MkdirAll: %v", err)
+		}
+
+		err = os.WriteFile(path, []byte("x"), 0o600)
+		if err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+
+	var got []string
+
+	err := realFS.WalkDir(dir, func(path string, _ iofs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, relErr := filepath.Rel(dir, path)
+		if relErr != nil {
+			return relErr
+		}
+
+		got = append(got, filepath.ToSlash(rel))
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkDir: %v", err)
+	}
+
+	want := []string{".", "a.txt", "b", "b/1.txt", "b/2.txt", "b/c", "b/c/3.txt"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("visited=%v, want %v", got, want)
+	}
+}
+
 func Test_RealFS_Exists_Returns_False_When_Path_Does_Not_Exist(t *testing.T) {
 	t.Parallel()
 
@@ -91,6 +140,25 @@ func Test_RealFS_WriteFile_Creates_New_File(t *testing.T) {
 	}
 }
 
+func Test_RealFS_OpenFile_Returns_ErrExist_When_Exclusive_Create_Hits_Existing_File(t *testing.T) {
+	t.Parallel()
+
+	realFS := fs.NewReal()
+	path := filepath.Join(t.TempDir(), "excl.txt")
+
+	f, err := realFS.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+
+	_ = f.Close()
+
+	_, err = realFS.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if !errors.Is(err, os.ErrExist) {
+		t.Fatalf("OpenFile on existing path: err=%v, want os.ErrExist", err)
+	}
+}
+
 func Test_RealFS_WriteFile_Truncates_Existing_File(t *testing.T) {
 	t.Parallel()
 
@@ -119,3 +187,83 @@ func Test_RealFS_WriteFile_Truncates_Existing_File(t *testing.T) {
 		t.Fatalf("content=%q, want %q", got, "short")
 	}
 }
+
+func Test_RealFS_OpenUncached_Writes_Content_When_Synced_And_Closed(t *testing.T) {
+	t.Parallel()
+
+	realFS := fs.NewReal()
+	path := filepath.Join(t.TempDir(), "bulk.dat")
+
+	f, err := fs.OpenUncached(realFS, path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		t.Fatalf("OpenUncached: %v", err)
+	}
+
+	_, err = f.Write([]byte(testContentHello))
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	err = f.Sync()
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	if string(got) != testContentHello {
+		t.Fatalf("content=%q, want %q", got, testContentHello)
+	}
+}
+
+func Test_OpenUncached_Falls_Back_To_OpenFile_When_FS_Has_No_Uncached_Support(t *testing.T) {
+	t.Parallel()
+
+	chaosFS := fs.NewChaos(fs.NewReal(), 1, &fs.ChaosConfig{})
+	path := filepath.Join(t.TempDir(), "plain.dat")
+
+	f, err := fs.OpenUncached(chaosFS, path, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("OpenUncached: %v", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	_, err = os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+}
+
+func Test_RealFS_DiskUsage_Reports_Capacity_When_Path_Exists(t *testing.T) {
+	t.Parallel()
+
+	free, total, err := fs.NewReal().DiskUsage(t.TempDir())
+	if err != nil {
+		t.Fatalf("DiskUsage: %v", err)
+	}
+
+	if total == 0 || free > total {
+		t.Fatalf("free=%d total=%d, want 0 <= free <= total and total > 0", free, total)
+	}
+}
+
+func Test_RealFS_DiskUsage_Returns_NotExist_When_Path_Is_Missing(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := fs.NewReal().DiskUsage(filepath.Join(t.TempDir(), "missing"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("DiskUsage: got %v, want not-exist error", err)
+	}
+}
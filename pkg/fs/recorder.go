@@ -0,0 +1,337 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// RecordedOp is one operation observed by [Recorder].
+//
+// Fields are exported with JSON tags so a recording can be saved and loaded
+// again for [NewReplayer].
+type RecordedOp struct {
+	// Seq is the position in the recording, starting at 1.
+	Seq uint64 `json:"seq"`
+	// Op is the operation name: the [FS] method in lower case ("open",
+	// "openfile", "readfile", "rename", ...) or "file." plus the [File]
+	// method for handle operations ("file.write", "file.sync", ...).
+	Op string `json:"op"`
+	// Path is the path passed to the operation, or the path the handle
+	// was opened with.
+	Path string `json:"path,omitempty"`
+	// Args are the other inputs (e.g. "flag", "perm", "newpath", "len").
+	// Replay compares Op, Path and Args.
+	Args []TraceAttr `json:"args,omitempty"`
+	// Err is the returned error's text, empty on success. Informational;
+	// not compared by replay.
+	Err string `json:"err,omitempty"`
+}
+
+func (op RecordedOp) String() string {
+	e := TraceEvent{Seq: op.Seq, Op: op.Op, Path: op.Path, Attrs: op.Args, Kind: "ok"}
+	if op.Err != "" {
+		e.Kind = "fail"
+		e.Err = errors.New(op.Err)
+	}
+
+	return e.String()
+}
+
+// sameCall reports whether op and other are the same call, ignoring Seq and
+// the outcome.
+func (op RecordedOp) sameCall(other RecordedOp) bool {
+	return op.Op == other.Op && op.Path == other.Path && slices.Equal(op.Args, other.Args)
+}
+
+// Recorder wraps an [FS] and records every operation, including those on
+// returned [File] handles, without altering them. Use it to capture what an
+// embedder does to the filesystem, e.g. to reproduce a production issue.
+//
+// Unlike [Chaos] tracing, the recording is unbounded and kept in order, and
+// it composes with other wrappers: NewRecorder(NewChaos(...)) records the
+// faults Chaos injects as errors.
+//
+// Safe for concurrent use; concurrent operations are recorded in the order
+// they complete.
+type Recorder struct {
+	fs FS
+
+	mu  sync.Mutex
+	ops []RecordedOp
+}
+
+// NewRecorder returns a [Recorder] wrapping fsys.
+// Panics if fsys is nil.
+func NewRecorder(fsys FS) *Recorder {
+	if fsys == nil {
+		panic("fs: NewRecorder: fsys is nil")
+	}
+
+	return &Recorder{fs: fsys}
+}
+
+// Ops returns a copy of the operations recorded so far, oldest first.
+func (r *Recorder) Ops() []RecordedOp {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.ops)
+}
+
+func (r *Recorder) record(op, path string, err error, args ...TraceAttr) {
+	rec := RecordedOp{Op: op, Path: path, Args: args}
+	if err != nil {
+		rec.Err = err.Error()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec.Seq = uint64(len(r.ops)) + 1
+	r.ops = append(r.ops, rec)
+}
+
+func (r *Recorder) wrapFile(f File, path string, err error) (File, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordedFile{f: f, rec: r, path: path}, nil
+}
+
+// Open implements [FS.Open].
+func (r *Recorder) Open(path string) (File, error) {
+	f, err := r.fs.Open(path)
+	r.record("open", path, err)
+
+	return r.wrapFile(f, path, err)
+}
+
+// Create implements [FS.Create].
+func (r *Recorder) Create(path string) (File, error) {
+	f, err := r.fs.Create(path)
+	r.record("create", path, err)
+
+	return r.wrapFile(f, path, err)
+}
+
+// OpenFile implements [FS.OpenFile].
+func (r *Recorder) OpenFile(path string, flag int, perm os.FileMode) (File, error) {
+	f, err := r.fs.OpenFile(path, flag, perm)
+	r.record("openfile", path, err,
+		TraceAttr{"flag", fmt.Sprintf("%#x", flag)},
+		TraceAttr{"perm", fmt.Sprintf("%#o", perm)})
+
+	return r.wrapFile(f, path, err)
+}
+
+// ReadFile implements [FS.ReadFile].
+func (r *Recorder) ReadFile(path string) ([]byte, error) {
+	data, err := r.fs.ReadFile(path)
+	r.record("readfile", path, err)
+
+	return data, err
+}
+
+// WriteFile implements [FS.WriteFile].
+func (r *Recorder) WriteFile(path string, data []byte, perm os.FileMode) error {
+	err := r.fs.WriteFile(path, data, perm)
+	r.record("writefile", path, err,
+		TraceAttr{"len", strconv.Itoa(len(data))},
+		TraceAttr{"perm", fmt.Sprintf("%#o", perm)})
+
+	return err
+}
+
+// ReadDir implements [FS.ReadDir].
+func (r *Recorder) ReadDir(path string) ([]os.DirEntry, error) {
+	entries, err := r.fs.ReadDir(path)
+	r.record("readdir", path, err)
+
+	return entries, err
+}
+
+// WalkDir implements [FS.WalkDir]. The walk itself is recorded, followed by
+// the Stat and ReadDir calls it makes.
+func (r *Recorder) WalkDir(root string, fn fs.WalkDirFunc) error {
+	r.record("walkdir", root, nil)
+
+	return walkDir(r, root, fn)
+}
+
+// MkdirAll implements [FS.MkdirAll].
+func (r *Recorder) MkdirAll(path string, perm os.FileMode) error {
+	err := r.fs.MkdirAll(path, perm)
+	r.record("mkdirall", path, err, TraceAttr{"perm", fmt.Sprintf("%#o", perm)})
+
+	return err
+}
+
+// Stat implements [FS.Stat].
+func (r *Recorder) Stat(path string) (os.FileInfo, error) {
+	info, err := r.fs.Stat(path)
+	r.record("stat", path, err)
+
+	return info, err
+}
+
+// Exists implements [FS.Exists].
+func (r *Recorder) Exists(path string) (bool, error) {
+	exists, err := r.fs.Exists(path)
+	r.record("exists", path, err)
+
+	return exists, err
+}
+
+// Remove implements [FS.Remove].
+func (r *Recorder) Remove(path string) error {
+	err := r.fs.Remove(path)
+	r.record("remove", path, err)
+
+	return err
+}
+
+// RemoveAll implements [FS.RemoveAll].
+func (r *Recorder) RemoveAll(path string) error {
+	err := r.fs.RemoveAll(path)
+	r.record("removeall", path, err)
+
+	return err
+}
+
+// Rename implements [FS.Rename].
+func (r *Recorder) Rename(oldpath, newpath string) error {
+	err := r.fs.Rename(oldpath, newpath)
+	r.record("rename", oldpath, err, TraceAttr{"newpath", newpath})
+
+	return err
+}
+
+// DiskUsage implements [FS.DiskUsage].
+func (r *Recorder) DiskUsage(path string) (uint64, uint64, error) {
+	free, total, err := r.fs.DiskUsage(path)
+	r.record("diskusage", path, err)
+
+	return free, total, err
+}
+
+// recordedFile records operations on a [File] opened through [Recorder].
+type recordedFile struct {
+	f    File
+	rec  *Recorder
+	path string
+}
+
+func (f *recordedFile) Read(p []byte) (int, error) {
+	n, err := f.f.Read(p)
+	f.rec.record("file.read", f.path, err, TraceAttr{"len", strconv.Itoa(len(p))})
+
+	return n, err
+}
+
+func (f *recordedFile) Write(p []byte) (int, error) {
+	n, err := f.f.Write(p)
+	f.rec.record("file.write", f.path, err, TraceAttr{"len", strconv.Itoa(len(p))})
+
+	return n, err
+}
+
+func (f *recordedFile) Seek(offset int64, whence int) (int64, error) {
+	pos, err := f.f.Seek(offset, whence)
+	f.rec.record("file.seek", f.path, err,
+		TraceAttr{"offset", strconv.FormatInt(offset, 10)},
+		TraceAttr{"whence", strconv.Itoa(whence)})
+
+	return pos, err
+}
+
+func (f *recordedFile) Close() error {
+	err := f.f.Close()
+	f.rec.record("file.close", f.path, err)
+
+	return err
+}
+
+// Fd is not recorded: it has no effect on the filesystem.
+func (f *recordedFile) Fd() uintptr {
+	return f.f.Fd()
+}
+
+func (f *recordedFile) Stat() (os.FileInfo, error) {
+	info, err := f.f.Stat()
+	f.rec.record("file.stat", f.path, err)
+
+	return info, err
+}
+
+func (f *recordedFile) Sync() error {
+	err := f.f.Sync()
+	f.rec.record("file.sync", f.path, err)
+
+	return err
+}
+
+func (f *recordedFile) Chmod(mode os.FileMode) error {
+	err := f.f.Chmod(mode)
+	f.rec.record("file.chmod", f.path, err, TraceAttr{"mode", fmt.Sprintf("%#o", mode)})
+
+	return err
+}
+
+// ErrReplayMismatch indicates a run observed by [Replayer] did not perform
+// the recorded operations.
+var ErrReplayMismatch = errors.New("fs: replay mismatch")
+
+// Replayer wraps an [FS] like [Recorder] and checks that a run performs the
+// same operations, in the same order, as an earlier recording.
+//
+// Operations pass through to the wrapped FS unchanged; call [Replayer.Verify]
+// after the run. Matching compares [RecordedOp.Op], Path and Args, so paths
+// must be stable between runs (e.g. use the same directory). Only meaningful
+// for runs that perform operations from a single goroutine.
+type Replayer struct {
+	*Recorder
+
+	want []RecordedOp
+}
+
+// NewReplayer returns a [Replayer] wrapping fsys that expects the operations
+// in want, typically from [Recorder.Ops].
+// Panics if fsys is nil.
+func NewReplayer(fsys FS, want []RecordedOp) *Replayer {
+	return &Replayer{Recorder: NewRecorder(fsys), want: slices.Clone(want)}
+}
+
+// Verify returns an [ErrReplayMismatch] error describing the first
+// difference between the operations performed so far and the recording, or
+// nil if they match exactly.
+func (r *Replayer) Verify() error {
+	got := r.Ops()
+
+	for i := range min(len(got), len(r.want)) {
+		if !got[i].sameCall(r.want[i]) {
+			return fmt.Errorf("%w: op %d: got %s, want %s", ErrReplayMismatch, i+1, got[i], r.want[i])
+		}
+	}
+
+	switch {
+	case len(got) < len(r.want):
+		return fmt.Errorf("%w: run stopped after %d of %d ops, next want %s", ErrReplayMismatch, len(got), len(r.want), r.want[len(got)])
+	case len(got) > len(r.want):
+		return fmt.Errorf("%w: %d extra ops, first %s", ErrReplayMismatch, len(got)-len(r.want), got[len(r.want)])
+	}
+
+	return nil
+}
+
+// Compile-time interface checks.
+var (
+	_ FS   = (*Recorder)(nil)
+	_ FS   = (*Replayer)(nil)
+	_ File = (*recordedFile)(nil)
+)
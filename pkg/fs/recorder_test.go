@@ -0,0 +1,156 @@
+package fs_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/calvinalkan/agent-task/pkg/fs"
+)
+
+func Test_Recorder_Records_FS_And_File_Ops_When_Wrapping(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	rec := fs.NewRecorder(fs.NewReal())
+
+	runRecorderWorkload(t, rec, dir)
+
+	ops := rec.Ops()
+
+	var got []string
+	for _, op := range ops {
+		got = append(got, op.Op)
+	}
+
+	want := "mkdirall,create,file.write,file.sync,file.close,rename,readfile,stat"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("ops = %v, want %s", got, want)
+	}
+
+	if ops[0].Seq != 1 || ops[len(ops)-1].Seq != uint64(len(ops)) {
+		t.Fatalf("seq = %d..%d, want 1..%d", ops[0].Seq, ops[len(ops)-1].Seq, len(ops))
+	}
+
+	if ops[5].Path != filepath.Join(dir, "sub", "a.tmp") || ops[5].Args[0].Value != filepath.Join(dir, "sub", "a.txt") {
+		t.Fatalf("rename op = %+v", ops[5])
+	}
+
+	if !strings.Contains(ops[7].Err, "no such file") {
+		t.Fatalf("stat of missing file: err = %q, want no such file", ops[7].Err)
+	}
+}
+
+func Test_Replayer_Verifies_Run_When_Recording_Is_Loaded(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	rec := fs.NewRecorder(fs.NewReal())
+
+	runRecorderWorkload(t, rec, dir)
+
+	// A recording survives a JSON round trip.
+	data, err := json.Marshal(rec.Ops())
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var want []fs.RecordedOp
+
+	err = json.Unmarshal(data, &want)
+	if err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	err = os.RemoveAll(filepath.Join(dir, "sub"))
+	if err != nil {
+		t.Fatalf("reset dir: %v", err)
+	}
+
+	replay := fs.NewReplayer(fs.NewReal(), want)
+	runRecorderWorkload(t, replay, dir)
+
+	err = replay.Verify()
+	if err != nil {
+		t.Fatalf("verify same run: %v", err)
+	}
+
+	_, _ = replay.ReadFile(filepath.Join(dir, "sub", "a.txt"))
+
+	err = replay.Verify()
+	if !errors.Is(err, fs.ErrReplayMismatch) || !strings.Contains(err.Error(), "extra") {
+		t.Fatalf("verify extra op: got %v, want extra-op mismatch", err)
+	}
+
+	short := fs.NewReplayer(fs.NewReal(), want)
+
+	_ = short.MkdirAll(filepath.Join(dir, "other"), 0o750)
+
+	err = short.Verify()
+	if !errors.Is(err, fs.ErrReplayMismatch) || !strings.Contains(err.Error(), "op 1") {
+		t.Fatalf("verify different op: got %v, want mismatch at op 1", err)
+	}
+}
+
+func Test_Recorder_Records_Injected_Faults_When_Wrapping_Chaos(t *testing.T) {
+	t.Parallel()
+
+	chaosFS := fs.NewChaos(fs.NewReal(), 1, &fs.ChaosConfig{OpenFailRate: 1.0})
+	rec := fs.NewRecorder(chaosFS)
+
+	_, err := rec.Create(filepath.Join(t.TempDir(), "a.txt"))
+	if err == nil {
+		t.Fatal("Create unexpectedly succeeded")
+	}
+
+	ops := rec.Ops()
+	if len(ops) != 1 || ops[0].Op != "create" || ops[0].Err == "" {
+		t.Fatalf("ops = %+v, want one failed create", ops)
+	}
+}
+
+func runRecorderWorkload(t *testing.T, fsys fs.FS, dir string) {
+	t.Helper()
+
+	sub := filepath.Join(dir, "sub")
+
+	err := fsys.MkdirAll(sub, 0o750)
+	if err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+
+	f, err := fsys.Create(filepath.Join(sub, "a.tmp"))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	_, err = f.Write([]byte(testContentHello))
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	err = f.Sync()
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	err = fsys.Rename(filepath.Join(sub, "a.tmp"), filepath.Join(sub, "a.txt"))
+	if err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+
+	_, err = fsys.ReadFile(filepath.Join(sub, "a.txt"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	_, _ = fsys.Stat(filepath.Join(sub, "missing"))
+}
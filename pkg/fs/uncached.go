@@ -0,0 +1,74 @@
+package fs
+
+import (
+	"os"
+)
+
+// UncachedOpener is implemented by filesystems that can open files whose
+// written pages are dropped from the OS page cache. See [OpenUncached].
+type UncachedOpener interface {
+	OpenUncached(path string, flag int, perm os.FileMode) (File, error)
+}
+
+// OpenUncached opens a file for large sequential I/O that should not evict
+// hot data from the page cache.
+//
+// Uses fsys's [UncachedOpener] implementation if it has one ([Real] does);
+// otherwise it is a plain [FS.OpenFile], so [Chaos] and [Crash] behave as
+// usual.
+func OpenUncached(fsys FS, path string, flag int, perm os.FileMode) (File, error) {
+	if opener, ok := fsys.(UncachedOpener); ok {
+		return opener.OpenUncached(path, flag, perm)
+	}
+
+	return fsys.OpenFile(path, flag, perm)
+}
+
+// OpenUncached is [os.OpenFile] with page-cache eviction.
+//
+// After each successful [File.Sync] and on [File.Close], the file's cached
+// pages are dropped with posix_fadvise(POSIX_FADV_DONTNEED) where supported
+// (Linux); elsewhere the cache behaves normally. Only clean pages can be
+// dropped, so writers should Sync before Close to get the benefit.
+//
+// O_DIRECT is deliberately not used: it requires block-aligned buffers and
+// offsets, which arbitrary [io.Writer] callers cannot guarantee.
+func (*Real) OpenUncached(path string, flag int, perm os.FileMode) (File, error) {
+	f, err := os.OpenFile(path, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+
+	return &uncachedFile{File: f}, nil
+}
+
+// uncachedFile drops the file's pages from the page cache once they are clean.
+type uncachedFile struct {
+	*os.File
+}
+
+// Sync flushes the file, then drops its now-clean pages. The advice is
+// best-effort; failing to drop the cache is not an error.
+func (f *uncachedFile) Sync() error {
+	err := f.File.Sync()
+	if err != nil {
+		return err
+	}
+
+	_ = dropPageCache(f.File)
+
+	return nil
+}
+
+// Close drops the file's clean pages and closes it.
+func (f *uncachedFile) Close() error {
+	_ = dropPageCache(f.File)
+
+	return f.File.Close()
+}
+
+// Compile-time interface checks.
+var (
+	_ UncachedOpener = (*Real)(nil)
+	_ File           = (*uncachedFile)(nil)
+)
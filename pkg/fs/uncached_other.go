@@ -0,0 +1,12 @@
+//go:build !linux
+
+package fs
+
+import (
+	"os"
+)
+
+// dropPageCache is a no-op where posix_fadvise is unavailable.
+func dropPageCache(*os.File) error {
+	return nil
+}
@@ -0,0 +1,210 @@
+package mddb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// attachmentsDirName is the directory under .mddb holding attachments, one
+// subdirectory per document ID.
+const attachmentsDirName = "attachments"
+
+// PutAttachment buffers a binary attachment of document docID for writing on
+// [Tx.Commit], replacing an existing attachment with the same name.
+//
+// Attachments are stored under .mddb/attachments/<id>/<name> and go through
+// the WAL like documents, so a crash after the WAL write is recovered. They
+// are not indexed and are left alone by reindexing. [Tx.Delete] of the
+// document removes all of its attachments.
+//
+// name must be a plain file name not starting with "." (reserved for temp
+// files). Returns [ErrNotFound] if the document neither exists nor is
+// buffered in this transaction. data is copied; no disk I/O until commit.
+func (tx *Tx[T]) PutAttachment(docID, name string, data []byte) error {
+	if tx == nil {
+		return errors.New("tx is nil")
+	}
+
+	if tx.closed {
+		return errors.New("transaction closed")
+	}
+
+	err := validateAttachmentRef(docID, name)
+	if err != nil {
+		return withContext(err, docID, "")
+	}
+
+	if tx.mddb.cfg.RelPathFromID == nil {
+		return errors.New("RelPathFromID is nil")
+	}
+
+	path := tx.mddb.cfg.RelPathFromID(docID)
+
+	err = tx.mddb.validateRelPath(path)
+	if err != nil {
+		return withContext(fmt.Errorf("validating path: %w", err), docID, path)
+	}
+
+	if existing, ok := tx.ops[docID]; ok {
+		if existing.Op == walOpDelete {
+			return withContext(ErrNotFound, docID, path)
+		}
+	} else {
+		exists, existsErr := tx.existsInIndex(docID)
+		if existsErr != nil {
+			return withContext(existsErr, docID, path)
+		}
+
+		if !exists {
+			return withContext(ErrNotFound, docID, path)
+		}
+	}
+
+	tx.setOp(walOp[T]{
+		Op:   walOpAttach,
+		Kind: walKindUpdate,
+		ID:   docID,
+		Path: path,
+		Name: name,
+		Data: slices.Clone(data),
+	})
+
+	return nil
+}
+
+// dropBufferedAttachments removes the attachments buffered for id, recording
+// them so [Tx.RollbackTo] can restore them. Called when id is deleted.
+func (tx *Tx[T]) dropBufferedAttachments(id string) {
+	for key, op := range tx.ops {
+		if op.Op != walOpAttach || op.ID != id {
+			continue
+		}
+
+		tx.undo = append(tx.undo, txUndo[T]{id: key, prev: op, had: true})
+		delete(tx.ops, key)
+	}
+}
+
+// GetAttachment returns the content of attachment name of document docID.
+//
+// Returns [ErrNotFound] if the attachment does not exist.
+// Returns [ErrClosed] if store is closed.
+func (mddb *MDDB[T]) GetAttachment(ctx context.Context, docID, name string) ([]byte, error) {
+	if ctx == nil {
+		return nil, errors.New("context is nil")
+	}
+
+	if mddb == nil || mddb.closed.Load() {
+		return nil, ErrClosed
+	}
+
+	err := validateAttachmentRef(docID, name)
+	if err != nil {
+		return nil, withContext(err, docID, "")
+	}
+
+	release, err := mddb.acquireReadLock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquiring read lock: %w", err)
+	}
+
+	defer func() { _ = release() }()
+
+	data, err := mddb.fs.ReadFile(mddb.attachmentPath(docID, name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, withContext(fmt.Errorf("%w: attachment %q", ErrNotFound, name), docID, "")
+		}
+
+		return nil, withContext(fmt.Errorf("fs: %w", err), docID, "")
+	}
+
+	return data, nil
+}
+
+// ListAttachments returns the attachment names of document docID, sorted.
+// Returns nil if the document has no attachments.
+//
+// Returns [ErrClosed] if store is closed.
+func (mddb *MDDB[T]) ListAttachments(ctx context.Context, docID string) ([]string, error) {
+	if ctx == nil {
+		return nil, errors.New("context is nil")
+	}
+
+	if mddb == nil || mddb.closed.Load() {
+		return nil, ErrClosed
+	}
+
+	err := validatePathComponent(docID)
+	if err != nil {
+		return nil, withContext(fmt.Errorf("id %w", err), docID, "")
+	}
+
+	release, err := mddb.acquireReadLock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquiring read lock: %w", err)
+	}
+
+	defer func() { _ = release() }()
+
+	entries, err := mddb.fs.ReadDir(mddb.attachmentDir(docID))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, withContext(fmt.Errorf("fs: %w", err), docID, "")
+	}
+
+	var names []string
+
+	for _, entry := range entries {
+		// Skips leftover temp files of interrupted writes.
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		names = append(names, entry.Name())
+	}
+
+	slices.Sort(names)
+
+	return names, nil
+}
+
+// attachmentDir returns the absolute directory holding the attachments of id.
+func (mddb *MDDB[T]) attachmentDir(id string) string {
+	return filepath.Join(mddb.dataDir, ".mddb", attachmentsDirName, id)
+}
+
+// attachmentPath returns the absolute path of attachment name of id.
+func (mddb *MDDB[T]) attachmentPath(id, name string) string {
+	return filepath.Join(mddb.attachmentDir(id), name)
+}
+
+// attachmentOpKey is the [Tx] buffer key of an attachment, distinct from any
+// document ID.
+func attachmentOpKey(id, name string) string {
+	return id + "\x00" + name
+}
+
+// validateAttachmentRef checks that docID and name are usable as path
+// components of an attachment.
+func validateAttachmentRef(docID, name string) error {
+	err := validatePathComponent(docID)
+	if err != nil {
+		return fmt.Errorf("id %w", err)
+	}
+
+	err = validatePathComponent(name)
+	if err != nil {
+		return fmt.Errorf("attachment name %w", err)
+	}
+
+	return nil
+}
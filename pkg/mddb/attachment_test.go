@@ -0,0 +1,223 @@
+package mddb_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/calvinalkan/agent-task/pkg/mddb"
+)
+
+func Test_PutAttachment_Stores_Blob_When_Committed(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	s := openTestStore(t, dir)
+
+	defer func() { _ = s.Close() }()
+
+	doc := newTestDoc(t, "With Attachments")
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	_, err = tx.Create(doc)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// Attaching to a document buffered in the same transaction is allowed.
+	err = tx.PutAttachment(doc.DocID, "screen.png", []byte{0x89, 'P', 'N', 'G', 0})
+	if err != nil {
+		t.Fatalf("put attachment: %v", err)
+	}
+
+	err = tx.PutAttachment(doc.DocID, "log.txt", []byte("first"))
+	if err != nil {
+		t.Fatalf("put attachment: %v", err)
+	}
+
+	err = tx.PutAttachment(doc.DocID, "log.txt", []byte("second"))
+	if err != nil {
+		t.Fatalf("put attachment: %v", err)
+	}
+
+	err = tx.Commit(t.Context())
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	got, err := s.GetAttachment(t.Context(), doc.DocID, "log.txt")
+	if err != nil {
+		t.Fatalf("get attachment: %v", err)
+	}
+
+	if string(got) != "second" {
+		t.Fatalf("log.txt = %q, want second", got)
+	}
+
+	// Reindexing leaves attachments alone.
+	_, err = s.Reindex(t.Context())
+	if err != nil {
+		t.Fatalf("reindex: %v", err)
+	}
+
+	names, err := s.ListAttachments(t.Context(), doc.DocID)
+	if err != nil {
+		t.Fatalf("list attachments: %v", err)
+	}
+
+	if !slices.Equal(names, []string{"log.txt", "screen.png"}) {
+		t.Fatalf("names = %v, want [log.txt screen.png]", names)
+	}
+
+	got, err = s.GetAttachment(t.Context(), doc.DocID, "screen.png")
+	if err != nil {
+		t.Fatalf("get attachment: %v", err)
+	}
+
+	if string(got) != "\x89PNG\x00" {
+		t.Fatalf("screen.png = %q", got)
+	}
+
+	_, err = s.GetAttachment(t.Context(), doc.DocID, "missing.txt")
+	if !errors.Is(err, mddb.ErrNotFound) {
+		t.Fatalf("get missing: got %v, want ErrNotFound", err)
+	}
+}
+
+func Test_PutAttachment_Returns_Error_When_Doc_Or_Name_Invalid(t *testing.T) {
+	t.Parallel()
+
+	s := openTestStore(t, t.TempDir())
+
+	defer func() { _ = s.Close() }()
+
+	doc := createTestDoc(t.Context(), t, s, newTestDoc(t, "Target"))
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	missing := newTestDoc(t, "Missing")
+
+	err = tx.PutAttachment(missing.DocID, "a.txt", []byte("x"))
+	if !errors.Is(err, mddb.ErrNotFound) {
+		t.Fatalf("missing doc: got %v, want ErrNotFound", err)
+	}
+
+	for _, name := range []string{"", ".hidden", "../escape", "a/b"} {
+		err = tx.PutAttachment(doc.DocID, name, []byte("x"))
+		if err == nil {
+			t.Fatalf("name %q: want error", name)
+		}
+	}
+
+	err = tx.Delete(doc.DocID)
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	err = tx.PutAttachment(doc.DocID, "a.txt", []byte("x"))
+	if !errors.Is(err, mddb.ErrNotFound) {
+		t.Fatalf("deleted doc: got %v, want ErrNotFound", err)
+	}
+}
+
+func Test_Delete_Removes_Attachments_When_Committed(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	s := openTestStore(t, dir)
+
+	defer func() { _ = s.Close() }()
+
+	doc := createTestDoc(t.Context(), t, s, newTestDoc(t, "Doomed"))
+	putTestAttachment(t, s, doc.DocID, "a.txt", "data")
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	err = tx.Delete(doc.DocID)
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	err = tx.Commit(t.Context())
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	names, err := s.ListAttachments(t.Context(), doc.DocID)
+	if err != nil || names != nil {
+		t.Fatalf("list after delete: got %v, %v, want nil, nil", names, err)
+	}
+
+	_, err = os.Stat(filepath.Join(dir, ".mddb", "attachments", doc.DocID))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("attachment dir: got %v, want not exist", err)
+	}
+}
+
+func Test_Open_Replays_Attachment_When_WAL_Committed(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s := openTestStore(t, dir)
+	doc := createTestDoc(t.Context(), t, s, newTestDoc(t, "Crash"))
+	_ = s.Close()
+
+	walPath := filepath.Join(dir, ".mddb", "wal")
+	writeWalFile(t, walPath, []walRecord{{
+		Op:   "attach",
+		Kind: "update",
+		ID:   doc.DocID,
+		Path: doc.DocPath,
+		Name: "trace.bin",
+		Data: []byte{1, 2, 3},
+	}})
+
+	s = openTestStore(t, dir)
+
+	defer func() { _ = s.Close() }()
+
+	got, err := s.GetAttachment(t.Context(), doc.DocID, "trace.bin")
+	if err != nil {
+		t.Fatalf("get attachment: %v", err)
+	}
+
+	if !slices.Equal(got, []byte{1, 2, 3}) {
+		t.Fatalf("trace.bin = %v, want [1 2 3]", got)
+	}
+}
+
+func putTestAttachment(t *testing.T, s *mddb.MDDB[TestDoc], id, name, data string) {
+	t.Helper()
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	err = tx.PutAttachment(id, name, []byte(data))
+	if err != nil {
+		_ = tx.Rollback()
+
+		t.Fatalf("put attachment: %v", err)
+	}
+
+	err = tx.Commit(t.Context())
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+}
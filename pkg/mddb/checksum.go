@@ -0,0 +1,121 @@
+package mddb
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/calvinalkan/fileproc"
+
+	"github.com/calvinalkan/agent-task/pkg/mddb/frontmatter"
+)
+
+// ErrCorrupt indicates a document file no longer matches its content_sha256
+// checksum; see [Config.Checksums].
+var ErrCorrupt = errors.New("document corrupt")
+
+// frontmatterKeyContentSHA256 is the "content_sha256" frontmatter key holding
+// the checksum written with [Config.Checksums].
+// Do not modify; reuse to avoid per-call allocations in hot paths.
+var frontmatterKeyContentSHA256 = []byte("content_sha256")
+
+// verifyContentSHA256 checks that content, the bytes of a document file,
+// hashes to sum once its content_sha256 line is removed, the form the
+// checksum was computed over at write time.
+//
+// Returns an error wrapping [ErrCorrupt] on mismatch, including when the
+// line was reformatted.
+func verifyContentSHA256(content, sum []byte) error {
+	var fm frontmatter.Frontmatter
+
+	err := fm.Set(frontmatterKeyContentSHA256, frontmatter.StringValue(string(sum)))
+	if err != nil {
+		return fmt.Errorf("frontmatter: %w", err)
+	}
+
+	line, err := fm.MarshalYAML(frontmatter.WithYAMLDelimiters(false))
+	if err != nil {
+		return fmt.Errorf("frontmatter: %w", err)
+	}
+
+	// The line never starts the file (the delimiter does), so anchoring on
+	// the newline before it rules out matches inside other values.
+	i := bytes.Index(content, []byte("\n"+line))
+	if i < 0 {
+		return fmt.Errorf("%w: %s line not found", ErrCorrupt, frontmatterKeyContentSHA256)
+	}
+
+	h := sha256.New()
+	h.Write(content[:i+1])
+	h.Write(content[i+1+len(line):])
+
+	if hex.EncodeToString(h.Sum(nil)) != string(sum) {
+		return fmt.Errorf("%w: %s mismatch", ErrCorrupt, frontmatterKeyContentSHA256)
+	}
+
+	return nil
+}
+
+// Verify reads and parses every document file, checking what reads check:
+// frontmatter, ID, path, referenced bodies and, for files that have one, the
+// content_sha256 checksum (see [Config.Checksums]). The index is not read or
+// modified, so Verify also works with [IndexNone].
+//
+// Returns the number of documents that passed. Documents that failed are
+// returned as [*IndexScanError]; issues for corrupt files wrap [ErrCorrupt].
+// Returns [ErrClosed] if store is closed.
+func (mddb *MDDB[T]) Verify(ctx context.Context) (int, error) {
+	if ctx == nil {
+		return 0, errors.New("context is nil")
+	}
+
+	if mddb == nil || mddb.closed.Load() {
+		return 0, ErrClosed
+	}
+
+	release, err := mddb.acquireReadLock(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("acquiring read lock: %w", err)
+	}
+
+	defer func() { _ = release() }()
+
+	var verified atomic.Int64
+
+	_, errs := fileproc.Process(ctx, mddb.dataDir, func(f *fileproc.File, _ *fileproc.FileWorker) (*struct{}, error) {
+		relPath := f.RelPath()
+		if isInternalPath(relPath) {
+			return nil, fileproc.ErrSkip
+		}
+
+		stat, err := f.Stat()
+		if err != nil {
+			return nil, fmt.Errorf("fs: %w", err)
+		}
+
+		data, err := f.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("fs: %w", err)
+		}
+
+		_, err = mddb.parseIndexable(relPath, data, stat.ModTime, stat.Size, "")
+		if err != nil {
+			return nil, fmt.Errorf("parsing document: %w", err)
+		}
+
+		verified.Add(1)
+
+		return nil, fileproc.ErrSkip
+	}, fileproc.WithRecursive(), fileproc.WithSuffix(".md"))
+
+	err = ctx.Err()
+	if err != nil {
+		return int(verified.Load()), fmt.Errorf("canceled: %w", context.Cause(ctx))
+	}
+
+	return int(verified.Load()), toIndexScanError(errs)
+}
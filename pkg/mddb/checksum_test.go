@@ -0,0 +1,124 @@
+package mddb_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/calvinalkan/agent-task/pkg/mddb"
+)
+
+func Test_Get_Returns_ErrCorrupt_When_File_Altered_With_Checksums(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cfg := testConfig(dir)
+	cfg.Checksums = true
+
+	s, err := mddb.Open(t.Context(), cfg)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	defer func() { _ = s.Close() }()
+
+	intact := newTestDoc(t, "Intact")
+	intact.DocBody = "Intact body\n"
+	altered := newTestDoc(t, "Altered")
+	altered.DocBody = "Original body\n"
+
+	createTestDoc(t.Context(), t, s, intact)
+	createTestDoc(t.Context(), t, s, altered)
+
+	path := filepath.Join(dir, altered.DocPath)
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	if !strings.Contains(string(content), "content_sha256: ") {
+		t.Fatalf("file has no checksum:\n%s", content)
+	}
+
+	err = os.WriteFile(path, []byte(strings.Replace(string(content), "Original", "Tampered", 1)), 0o600)
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	got, err := s.Get(t.Context(), intact.DocID)
+	if err != nil || got.DocBody != "Intact body\n" {
+		t.Fatalf("get intact: got %+v, %v", got, err)
+	}
+
+	_, err = s.Get(t.Context(), altered.DocID)
+	if !errors.Is(err, mddb.ErrCorrupt) {
+		t.Fatalf("get altered: got %v, want ErrCorrupt", err)
+	}
+
+	verified, err := s.Verify(t.Context())
+
+	var scanErr *mddb.IndexScanError
+	if !errors.As(err, &scanErr) || verified != 1 {
+		t.Fatalf("verify: got %d, %v; want 1 and IndexScanError", verified, err)
+	}
+
+	if len(scanErr.Issues) != 1 || scanErr.Issues[0].Path != altered.DocPath || !errors.Is(scanErr.Issues[0].Err, mddb.ErrCorrupt) {
+		t.Fatalf("verify issues = %v, want ErrCorrupt for %s", scanErr.Issues, altered.DocPath)
+	}
+}
+
+func Test_Verify_Passes_When_Documents_Updated_With_Checksums(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig(t.TempDir(), withTestDedupBodies())
+	cfg.Checksums = true
+
+	s, err := mddb.Open(t.Context(), cfg)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	defer func() { _ = s.Close() }()
+
+	doc := newTestDoc(t, "Checked")
+	doc.DocBody = "Shared body\n"
+	empty := newTestDoc(t, "Empty Body")
+
+	createTestDoc(t.Context(), t, s, doc)
+	createTestDoc(t.Context(), t, s, empty)
+
+	got, err := s.Get(t.Context(), doc.DocID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	got.DocStatus = "closed"
+
+	_, err = tx.Update(got)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	err = tx.Commit(t.Context())
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	verified, err := s.Verify(t.Context())
+	if err != nil || verified != 2 {
+		t.Fatalf("verify: got %d, %v; want 2", verified, err)
+	}
+
+	_, err = s.Reindex(t.Context())
+	if err != nil {
+		t.Fatalf("reindex: %v", err)
+	}
+}
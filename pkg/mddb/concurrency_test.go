@@ -390,6 +390,60 @@ func Test_Multiple_Readers_Succeed_When_Called_Concurrently(t *testing.T) {
 	}
 }
 
+func Test_LockDocument_Blocks_Same_ID_When_Held(t *testing.T) {
+	t.Parallel()
+
+	s := openTestStore(t, t.TempDir(), withTestLockTimeout())
+
+	defer func() { _ = s.Close() }()
+
+	first := newTestDoc(t, "First")
+	second := newTestDoc(t, "Second")
+
+	lock, err := s.LockDocument(t.Context(), first.DocID)
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+
+	_, err = s.LockDocument(t.Context(), first.DocID)
+	if !isDeadlineExceeded(err) {
+		t.Fatalf("lock held id: got %v, want timeout", err)
+	}
+
+	// Other documents and store writes are not blocked.
+	other, err := s.LockDocument(t.Context(), second.DocID)
+	if err != nil {
+		t.Fatalf("lock other id: %v", err)
+	}
+
+	createTestDoc(t.Context(), t, s, first)
+
+	_ = other.Close()
+
+	err = lock.Close()
+	if err != nil {
+		t.Fatalf("unlock: %v", err)
+	}
+
+	_ = lock.Close()
+
+	again, err := s.LockDocument(t.Context(), first.DocID)
+	if err != nil {
+		t.Fatalf("lock after release: %v", err)
+	}
+
+	if again.ID() != first.DocID {
+		t.Fatalf("ID() = %q, want %q", again.ID(), first.DocID)
+	}
+
+	_ = again.Close()
+
+	_, err = s.LockDocument(t.Context(), "../escape")
+	if err == nil {
+		t.Fatal("lock with path separator: want error")
+	}
+}
+
 func isDeadlineExceeded(err error) bool {
 	return err != nil && (errors.Is(err, context.DeadlineExceeded) ||
 		(err.Error() != "" && contains(err.Error(), "deadline exceeded")))
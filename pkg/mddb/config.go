@@ -3,6 +3,7 @@ package mddb
 import (
 	"context"
 	"database/sql"
+	"log/slog"
 	"time"
 
 	"github.com/calvinalkan/agent-task/pkg/mddb/frontmatter"
@@ -84,6 +85,32 @@ type Document interface {
 	Body() string
 }
 
+// IndexMode selects how a store indexes its documents. See [Config.IndexMode].
+type IndexMode uint8
+
+// IndexMode values.
+const (
+	// IndexSQLite maintains a SQLite index for queries. This is the default.
+	IndexSQLite IndexMode = iota
+
+	// IndexNone keeps only the files and the WAL, for stores accessed by ID.
+	IndexNone
+)
+
+// Timestamps configures [Config.Timestamps].
+//
+// Times come from [Config.Clock] and are written in UTC as RFC 3339 strings
+// (e.g. 2026-01-28T09:15:00Z). All documents of one commit get the same time.
+type Timestamps struct {
+	// Created is set when a document's file is first written and kept on
+	// later writes. Documents written before it was configured get it on
+	// their next write.
+	Created string
+
+	// Updated is set on every write.
+	Updated string
+}
+
 // Config provides all settings and callbacks for document storage.
 //
 // mddb maintains two representations:
@@ -94,7 +121,7 @@ type Config[T Document] struct {
 	//
 	// Documents are stored as markdown files in this directory (or subdirectories
 	// based on RelPathFromID). mddb creates a ".mddb" subdirectory for internal
-	// files (SQLite index, WAL).
+	// files (WAL, and the SQLite index unless [Config.IndexPath] is set).
 	//
 	// Created automatically if it doesn't exist.
 	BaseDir string
@@ -206,9 +233,129 @@ type Config[T Document] struct {
 	// FrontmatterKeyID/FrontmatterKeySchemaVersion/FrontmatterKeyTitle).
 	SQLColumnValues func(doc IndexableDocument) []any
 
+	// IndexPath is the location of the SQLite index file.
+	//
+	// Lets the derived index live on different storage than the documents,
+	// e.g. fast local disk while BaseDir is on a network mount. SQLite's own
+	// "-wal" and "-shm" files and the temp file used by [MDDB.Reindex] are
+	// created next to it. The parent directory is created if needed.
+	//
+	// The mddb write-ahead log and lock file always stay in BaseDir/.mddb:
+	// they protect the markdown files and must be on the same storage.
+	//
+	// Optional. Default: BaseDir/.mddb/index.sqlite.
+	IndexPath string
+
+	// IndexMode selects whether mddb maintains a SQLite index.
+	//
+	// With [IndexNone], mddb only manages the files and the WAL: commits stay
+	// atomic and crash-safe, but no SQLite database is opened. [MDDB.Get]
+	// reads the file at [Config.RelPathFromID], [MDDB.GetByPrefix] parses every
+	// document file, and [Query], [QueryScan], [MDDB.Reindex] and
+	// [MDDB.ReindexIncremental] return [ErrNoIndex]. Settings that only affect
+	// the index (SQLSchema, SQLColumnValues, IndexPath, SQLitePragmas and the
+	// index lifecycle hooks) are rejected by [Open].
+	//
+	// Optional. Default: [IndexSQLite].
+	IndexMode IndexMode
+
+	// DedupBodies stores document bodies once per distinct content instead of
+	// inline in each file.
+	//
+	// A document with a non-empty body is written with only its frontmatter
+	// plus a body_sha256 field; the body goes to .mddb/bodies/<xx>/<sha256>.
+	// [MDDB.Get], reindexing and the SQLite index see the full body as usual.
+	// The body travels in the WAL with its document, so commits stay
+	// crash-safe.
+	//
+	// Tradeoff: document files are no longer self-contained. Reading or
+	// editing a body by hand means opening the body file; editing it in place
+	// breaks the hash check and fails reads of every document sharing it.
+	// Body files are never removed automatically; run [MDDB.PruneBodies].
+	//
+	// Turning this off later is safe: existing references keep resolving and
+	// new writes are inline again.
+	//
+	// Optional. Default: false (bodies inline).
+	DedupBodies bool
+
+	// Checksums writes a content_sha256 field into each document file: the
+	// SHA-256 of the file without that field. Reads ([MDDB.Get], reindexing,
+	// WAL replay) recompute it and fail with [ErrCorrupt] if the file was
+	// altered outside mddb; [MDDB.Verify] checks every file.
+	//
+	// Tradeoff: hand edits count as corruption too. After editing a file by
+	// hand, remove its content_sha256 line (it is written again on the next
+	// update).
+	//
+	// Turning this off later is safe: files that still have a checksum keep
+	// being verified and new writes have none.
+	//
+	// Optional. Default: false.
+	Checksums bool
+
+	// DeferReindex stops [Open] from rebuilding the index when the schema
+	// fingerprint changed.
+	//
+	// Use [MDDB.NeedsReindex] to detect the mismatch and call [MDDB.Reindex]
+	// when convenient. Until then, queries see the index as built by the old
+	// schema, and [MDDB.Begin] and [MDDB.ReindexIncremental] fail with
+	// [ErrNeedsReindex]: the index cannot take rows for the new schema. A
+	// pending WAL still forces the rebuild, since recovery writes through the
+	// index.
+	//
+	// Optional. Default: false (rebuild on open).
+	DeferReindex bool
+
+	// SQLitePragmas tunes the index connection, e.g.
+	//
+	//	SQLitePragmas: map[string]string{
+	//	    "mmap_size":  "1073741824",
+	//	    "cache_size": "-200000",
+	//	},
+	//
+	// Applied on every connection after mddb's defaults (busy_timeout = 10000,
+	// journal_mode = WAL, synchronous = FULL, mmap_size = 268435456,
+	// cache_size = -20000, temp_store = MEMORY), so they override them.
+	// Not applied to the throwaway database [MDDB.Reindex] builds.
+	//
+	// Rejected at [Open] because they break mddb: user_version (holds the
+	// schema fingerprint), schema_version, writable_schema, query_only, and
+	// locking_mode (EXCLUSIVE locks other processes out of the index).
+	//
+	// Allowed, but weaken guarantees:
+	//   - synchronous below FULL or journal_mode OFF/MEMORY: a crash can lose or
+	//     corrupt index commits while the WAL is already truncated, leaving the
+	//     index out of sync with the files until [MDDB.Reindex].
+	//   - journal_mode other than WAL: readers block while a commit writes.
+	//   - busy_timeout = 0: concurrent processes get SQLITE_BUSY immediately.
+	//
+	// Names and values must be plain tokens (letters, digits, '_'; values may
+	// start with '-'). Optional.
+	SQLitePragmas map[string]string
+
+	// MaxOpenConns limits open index connections. Values above 1 allow
+	// concurrent readers within the process. Default: 1.
+	MaxOpenConns int
+
 	// LockTimeout is max wait for WAL locks. Default: 10s.
 	LockTimeout time.Duration
 
+	// Logger receives structured records of what the store does, for
+	// tracing slow or failed operations:
+	//   - Debug: lock acquired (with wait time), WAL synced, file written or
+	//     removed, index updated
+	//   - Info: reindex finished, WAL replayed
+	//   - Warn: lock failed, uncommitted WAL discarded
+	//   - Error: reindex failed
+	//
+	// Messages are prefixed "mddb: "; documents are identified by doc_id and
+	// doc_path attributes. Logging happens while locks are held, so handlers
+	// should be fast.
+	//
+	// Optional. Default: discards everything.
+	Logger *slog.Logger
+
 	// ParseOptions configures frontmatter parsing behavior.
 	// Use frontmatter.WithLineLimit, frontmatter.WithRequireDelimiter, etc.
 	// Default: no line limit, require opening "---" delimiter.
@@ -252,6 +399,91 @@ type Config[T Document] struct {
 	//   - Hash for uniform length
 	ShortIDFromID func(id string) string
 
+	// NewID mints an ID for a document passed to [Tx.Create] or [Tx.Put]
+	// without one; [Config.SetID] stores it in the document. For example a
+	// UUIDv7 generator. The minted ID is validated like any other.
+	//
+	// Optional. Default: documents must carry their own ID.
+	NewID func() (string, error)
+
+	// SetID sets the ID of doc to id. See [Config.NewID].
+	//
+	// Required if NewID is set.
+	SetID func(doc *T, id string)
+
+	// ValidateID checks that an ID is well-formed, e.g. a UUID. Called for
+	// documents being written and for those read from files ([MDDB.Get],
+	// reindex, WAL replay); an ID it rejects fails with [ErrInvalidID]. Must be
+	// deterministic.
+	//
+	// Optional. Default: any non-empty ID is accepted.
+	ValidateID func(id string) error
+
+	// Normalize enforces document invariants (trimmed titles, lowercase tags,
+	// default values) in one place, whichever code path wrote the document.
+	//
+	// Called by [Tx.Commit] (and [Tx.Plan]) for every buffered create and
+	// update, right before the document is encoded. It may modify the
+	// document in place or return an error to reject it; an error fails the
+	// commit before anything is written. The normalized document is validated
+	// again and must keep its ID. Not called for WAL replay, which writes the
+	// already-encoded bytes.
+	//
+	// [Tx.Plan] normalizes a shallow copy, so the buffered document is
+	// normalized once, on Commit.
+	//
+	// Optional.
+	Normalize func(doc *T) error
+
+	// ReservedFields names additional frontmatter fields managed by mddb
+	// rather than by [Document.Frontmatter] (e.g. "created", "updated").
+	//
+	// On write, values a document sets for these fields are dropped. The
+	// values come from [Config.PopulateReserved] if set; otherwise those in
+	// the document's current file are kept and a new document gets none.
+	// Documents read them back from [IndexableDocument.Frontmatter] like any
+	// other field, so they need not redeclare the reserved set.
+	//
+	// Names must be valid frontmatter keys, unique, and must not repeat the
+	// built-in reserved fields (id, schema_version, title, body_sha256,
+	// content_sha256).
+	//
+	// Optional. Default: none.
+	ReservedFields []string
+
+	// PopulateReserved sets the values of [Config.ReservedFields] for a
+	// document being written, after [Config.Normalize]. current holds the
+	// reserved fields of the document's current file (empty for a new
+	// document); doc must not be modified.
+	//
+	// Fields set in the returned frontmatter are written
+	// ([frontmatter.DeleteValue] removes one); fields left out keep their
+	// current value. Setting a field not listed in ReservedFields is an
+	// error. Return an error to reject the document; the commit then fails
+	// before anything is written. Like Normalize, it runs again on Commit
+	// after [Tx.Plan].
+	//
+	// Optional. Default: reserved fields keep their current values.
+	PopulateReserved func(doc *T, current frontmatter.Frontmatter) (frontmatter.Frontmatter, error)
+
+	// Timestamps names frontmatter fields mddb stamps with the commit time.
+	// Both are reserved like [Config.ReservedFields], so values set by the
+	// document are ignored.
+	//
+	// Optional. Default: no timestamps.
+	Timestamps Timestamps
+
+	// Clock returns the current time wherever mddb stamps one, i.e. for
+	// [Config.Timestamps]. Inject a fixed or advancing clock for
+	// deterministic output in tests.
+	//
+	// File mtimes are not derived from it: [Tx.PutIfUnchanged] and
+	// [MDDB.ReindexIncremental] detect changes by mtime, which a fixed clock
+	// would hide.
+	//
+	// Optional. Default: [time.Now].
+	Clock func() time.Time
+
 	//
 	// INDEX LIFECYCLE HOOKS
 	// ---------------------
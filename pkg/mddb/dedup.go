@@ -0,0 +1,229 @@
+package mddb
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	iofs "io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/calvinalkan/fileproc"
+
+	"github.com/calvinalkan/agent-task/pkg/fs"
+	"github.com/calvinalkan/agent-task/pkg/mddb/frontmatter"
+)
+
+// frontmatterKeyBodySHA256 is the "body_sha256" frontmatter key referencing
+// a shared body under .mddb/bodies; see [Config.DedupBodies].
+// Do not modify; reuse to avoid per-call allocations in hot paths.
+var frontmatterKeyBodySHA256 = []byte("body_sha256")
+
+// bodiesDirName is the directory under .mddb holding deduplicated bodies.
+const bodiesDirName = "bodies"
+
+// hashBody returns the hex SHA-256 of a body, the name of its body file.
+func hashBody(body string) string {
+	sum := sha256.Sum256([]byte(body))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// bodyPath returns the absolute path of the body file for hash, sharded by
+// the first two hex digits so no directory grows too large.
+func (mddb *MDDB[T]) bodyPath(hash string) string {
+	return filepath.Join(mddb.dataDir, ".mddb", bodiesDirName, hash[:2], hash)
+}
+
+// isBodyHash reports whether s is a lowercase hex SHA-256. Checked before
+// building a path from a frontmatter value.
+func isBodyHash(s []byte) bool {
+	if len(s) != sha256.Size*2 {
+		return false
+	}
+
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// readBody loads and verifies the body referenced by a body_sha256 value.
+func (mddb *MDDB[T]) readBody(hash []byte) ([]byte, error) {
+	if !isBodyHash(hash) {
+		return nil, fmt.Errorf("frontmatter: invalid %s %q", frontmatterKeyBodySHA256, hash)
+	}
+
+	body, err := mddb.fs.ReadFile(mddb.bodyPath(string(hash)))
+	if err != nil {
+		return nil, fmt.Errorf("body: fs: %w", err)
+	}
+
+	if hashBody(string(body)) != string(hash) {
+		return nil, fmt.Errorf("body: content does not match %s %s", frontmatterKeyBodySHA256, hash)
+	}
+
+	return body, nil
+}
+
+// writeBody stores body under its hash unless it is already present. Body
+// files are content-addressed, so an existing one never needs rewriting.
+// Directories to sync are added to toSync, as in [MDDB.applyOpsToFS].
+func (mddb *MDDB[T]) writeBody(body string, rootDir string, existing, created, toSync map[string]struct{}) error {
+	path := mddb.bodyPath(hashBody(body))
+
+	_, err := mddb.fs.Stat(path)
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("fs: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+
+	err = ensureDir(mddb.fs, dir, rootDir, existing, created, toSync)
+	if err != nil {
+		return fmt.Errorf("creating dir: %w", err)
+	}
+
+	err = mddb.atomic.Write(path, strings.NewReader(body), fs.AtomicWriteOptions{
+		SyncDir: false,
+		Perm:    0o644,
+	})
+	if err != nil {
+		return fmt.Errorf("fs: %w", err)
+	}
+
+	toSync[dir] = struct{}{}
+
+	return nil
+}
+
+// PruneBodies removes body files under .mddb/bodies that no document
+// references any more, e.g. after deletes or updates with [Config.DedupBodies].
+//
+// Holds the exclusive lock while it parses the frontmatter of every document
+// file and lists the body files. Safe to run with DedupBodies off, e.g. after
+// disabling it and rewriting the documents.
+//
+// Returns the number of removed body files. A document whose frontmatter
+// fails to parse fails with [*IndexScanError] before anything is removed.
+// Returns [ErrClosed] if store is closed.
+func (mddb *MDDB[T]) PruneBodies(ctx context.Context) (int, error) {
+	if ctx == nil {
+		return 0, errors.New("context is nil")
+	}
+
+	if mddb == nil || mddb.closed.Load() {
+		return 0, ErrClosed
+	}
+
+	release, err := mddb.acquireWriteLockWithWalRecover(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("acquiring write lock: %w", err)
+	}
+
+	defer func() { _ = release() }()
+
+	return mddb.pruneBodiesLocked(ctx)
+}
+
+// pruneBodiesLocked implements [MDDB.PruneBodies]. Must be called with the
+// write lock held.
+func (mddb *MDDB[T]) pruneBodiesLocked(ctx context.Context) (int, error) {
+	referenced, err := mddb.referencedBodies(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+
+	err = mddb.fs.WalkDir(filepath.Join(mddb.dataDir, ".mddb", bodiesDirName), func(path string, d iofs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if errors.Is(walkErr, os.ErrNotExist) {
+				return nil
+			}
+
+			return walkErr
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		if _, ok := referenced[d.Name()]; ok {
+			return nil
+		}
+
+		removeErr := mddb.fs.Remove(path)
+		if removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
+			return removeErr
+		}
+
+		removed++
+
+		return nil
+	})
+	if err != nil {
+		return removed, fmt.Errorf("fs: %w", err)
+	}
+
+	return removed, nil
+}
+
+// referencedBodies returns the body_sha256 values of all document files.
+// Must be called with the write lock held.
+func (mddb *MDDB[T]) referencedBodies(ctx context.Context) (map[string]struct{}, error) {
+	var (
+		mu         sync.Mutex
+		referenced = make(map[string]struct{})
+	)
+
+	_, errs := fileproc.Process(ctx, mddb.dataDir, func(f *fileproc.File, _ *fileproc.FileWorker) (*struct{}, error) {
+		if isInternalPath(f.RelPath()) {
+			return nil, fileproc.ErrSkip
+		}
+
+		data, err := f.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("fs: %w", err)
+		}
+
+		fm, _, err := frontmatter.ParseBytes(data, mddb.cfg.ParseOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("parsing document: frontmatter: %w", err)
+		}
+
+		hash, ok := fm.GetBytes(frontmatterKeyBodySHA256)
+		if !ok {
+			return nil, fileproc.ErrSkip
+		}
+
+		mu.Lock()
+		referenced[string(hash)] = struct{}{}
+		mu.Unlock()
+
+		return nil, fileproc.ErrSkip
+	}, fileproc.WithRecursive(), fileproc.WithSuffix(".md"))
+
+	err := ctx.Err()
+	if err != nil {
+		return nil, fmt.Errorf("canceled: %w", context.Cause(ctx))
+	}
+
+	err = toIndexScanError(errs)
+	if err != nil {
+		return nil, err
+	}
+
+	return referenced, nil
+}
@@ -0,0 +1,272 @@
+package mddb_test
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/calvinalkan/agent-task/pkg/mddb"
+)
+
+func Test_DedupBodies_Stores_Shared_Body_Once_When_Docs_Identical(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	s := openTestStore(t, dir, withTestDedupBodies())
+
+	defer func() { _ = s.Close() }()
+
+	first := newTestDoc(t, "First")
+	first.DocBody = "Shared body\n"
+	second := newTestDoc(t, "Second")
+	second.DocBody = "Shared body\n"
+
+	createTestDoc(t.Context(), t, s, first)
+	createTestDoc(t.Context(), t, s, second)
+
+	if got := bodyFiles(t, dir); len(got) != 1 {
+		t.Fatalf("body files = %v, want 1", got)
+	}
+
+	content := readFileString(t, filepath.Join(dir, first.DocPath))
+	if strings.Contains(content, "Shared body") {
+		t.Fatalf("document file contains body:\n%s", content)
+	}
+
+	if !strings.Contains(content, "body_sha256: "+testBodyHash("Shared body\n")) {
+		t.Fatalf("document file missing body_sha256:\n%s", content)
+	}
+
+	got, err := s.Get(t.Context(), second.DocID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if got.DocBody != "Shared body\n" {
+		t.Fatalf("body = %q, want shared body", got.DocBody)
+	}
+
+	bodies, err := mddb.QueryScan(t.Context(), s, "SELECT body FROM "+testTableName, func(rows *sql.Rows) (string, error) {
+		var body string
+
+		err := rows.Scan(&body)
+
+		return body, err
+	})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+
+	for _, body := range bodies {
+		if body != "Shared body\n" {
+			t.Fatalf("indexed body = %q, want shared body", body)
+		}
+	}
+}
+
+func Test_DedupBodies_Returns_Same_Doc_As_Inline_When_Body_Lacks_Newline(t *testing.T) {
+	t.Parallel()
+
+	inline := openTestStore(t, t.TempDir())
+
+	defer func() { _ = inline.Close() }()
+
+	deduped := openTestStore(t, t.TempDir(), withTestDedupBodies())
+
+	defer func() { _ = deduped.Close() }()
+
+	doc := newTestDoc(t, "Doc")
+	doc.DocBody = "no trailing newline"
+
+	createTestDoc(t.Context(), t, inline, doc)
+	createTestDoc(t.Context(), t, deduped, doc)
+
+	want, err := inline.Get(t.Context(), doc.DocID)
+	if err != nil {
+		t.Fatalf("get inline: %v", err)
+	}
+
+	got, err := deduped.Get(t.Context(), doc.DocID)
+	if err != nil {
+		t.Fatalf("get deduped: %v", err)
+	}
+
+	if got.DocBody != want.DocBody {
+		t.Fatalf("body = %q, want %q", got.DocBody, want.DocBody)
+	}
+}
+
+func Test_DedupBodies_Replays_Body_From_WAL_When_Commit_Crashed(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s := openTestStore(t, dir, withTestDedupBodies())
+	_ = s.Close()
+
+	doc := newTestDoc(t, "WAL Doc")
+	doc.DocBody = "Body from wal\n"
+
+	rec := makeWalPutRecord(doc)
+	rec.Body = doc.DocBody
+	rec.Content = strings.Replace(renderDocContent(&TestDoc{
+		DocID:       doc.DocID,
+		DocTitle:    doc.DocTitle,
+		DocStatus:   doc.DocStatus,
+		DocPriority: doc.DocPriority,
+	}), "---\n", "---\nbody_sha256: "+testBodyHash(doc.DocBody)+"\n", 1)
+
+	writeWalFile(t, filepath.Join(dir, ".mddb", "wal"), []walRecord{rec})
+
+	s = openTestStore(t, dir, withTestDedupBodies())
+
+	defer func() { _ = s.Close() }()
+
+	got, err := s.Get(t.Context(), doc.DocID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if got.DocBody != doc.DocBody {
+		t.Fatalf("body = %q, want %q", got.DocBody, doc.DocBody)
+	}
+
+	if files := bodyFiles(t, dir); len(files) != 1 {
+		t.Fatalf("body files = %v, want 1", files)
+	}
+}
+
+func Test_DedupBodies_Keeps_Docs_Readable_When_Disabled(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s := openTestStore(t, dir, withTestDedupBodies())
+
+	doc := newTestDoc(t, "Doc")
+	doc.DocBody = "Deduped body\n"
+	createTestDoc(t.Context(), t, s, doc)
+
+	_ = s.Close()
+
+	s = openTestStore(t, dir)
+
+	defer func() { _ = s.Close() }()
+
+	got, err := s.Get(t.Context(), doc.DocID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if got.DocBody != "Deduped body\n" {
+		t.Fatalf("body = %q, want deduped body", got.DocBody)
+	}
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	_, err = tx.Update(got)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	err = tx.Commit(t.Context())
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	content := readFileString(t, filepath.Join(dir, doc.DocPath))
+	if !strings.Contains(content, "Deduped body") || strings.Contains(content, "body_sha256") {
+		t.Fatalf("document file not inline after update:\n%s", content)
+	}
+}
+
+func Test_PruneBodies_Removes_Unreferenced_Bodies_When_Docs_Deleted(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	s := openTestStore(t, dir, withTestDedupBodies())
+
+	defer func() { _ = s.Close() }()
+
+	kept := newTestDoc(t, "Kept")
+	kept.DocBody = "Kept body\n"
+	gone := newTestDoc(t, "Gone")
+	gone.DocBody = "Gone body\n"
+
+	createTestDoc(t.Context(), t, s, kept)
+	createTestDoc(t.Context(), t, s, gone)
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	err = tx.Delete(gone.DocID)
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	err = tx.Commit(t.Context())
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	removed, err := s.PruneBodies(t.Context())
+	if err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+
+	if removed != 1 {
+		t.Fatalf("removed = %d, want 1", removed)
+	}
+
+	files := bodyFiles(t, dir)
+	if len(files) != 1 || files[0] != testBodyHash("Kept body\n") {
+		t.Fatalf("body files = %v, want only kept body", files)
+	}
+
+	got, err := s.Get(t.Context(), kept.DocID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if got.DocBody != "Kept body\n" {
+		t.Fatalf("body = %q, want kept body", got.DocBody)
+	}
+}
+
+func bodyFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	var names []string
+
+	err := filepath.WalkDir(filepath.Join(dir, ".mddb", "bodies"), func(_ string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			names = append(names, d.Name())
+		}
+
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatalf("walk bodies: %v", err)
+	}
+
+	return names
+}
+
+func testBodyHash(body string) string {
+	sum := sha256.Sum256([]byte(body))
+
+	return hex.EncodeToString(sum[:])
+}
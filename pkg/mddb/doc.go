@@ -30,6 +30,9 @@
 //   - id: Document identifier from [Document.ID]
 //   - schema_version: Schema fingerprint at write time (diagnostics)
 //   - title: Document title from [Document.Title]
+//   - body_sha256: Body reference written with [Config.DedupBodies]
+//   - content_sha256: File checksum written with [Config.Checksums]
+//   - Any field listed in [Config.ReservedFields] or [Config.Timestamps]
 //
 // # SQLite Index
 //
@@ -114,4 +117,11 @@
 //	    rows, _ := sqlDB.Query("SELECT id FROM tickets WHERE status = ?", "open")
 //	    // ...
 //	})
+//
+//	// Query, one row at a time
+//	ids, _ := mddb.QueryScan(ctx, db, "SELECT id FROM tickets WHERE status = ?",
+//	    func(rows *sql.Rows) (string, error) {
+//	        var id string
+//	        return id, rows.Scan(&id)
+//	    }, "open")
 package mddb
@@ -93,6 +93,10 @@ const (
 	ValueScalar ValueKind = iota
 	ValueList
 	ValueObject
+
+	// ValueDelete marks a key for removal in a [Merge] overlay. It is not
+	// part of the YAML subset: [Frontmatter.MarshalYAML] rejects it.
+	ValueDelete
 )
 
 // Value represents a validated frontmatter value in the supported YAML subset.
@@ -1,6 +1,7 @@
 package frontmatter_test
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -838,6 +839,106 @@ func Test_FrontmatterParser_ReturnsEmpty_When_NoKeys(t *testing.T) {
 	}
 }
 
+func Test_Frontmatter_Merge_Combines_Keys_When_Options_Vary(t *testing.T) {
+	t.Parallel()
+
+	base := mustParseFrontmatter(t, "status: open\npriority: 2\ntags:\n  - a\n  - b\nassignee: alice")
+
+	var overlay frontmatter.Frontmatter
+
+	overlay.MustSet([]byte("status"), frontmatter.StringValue("closed"))
+	overlay.MustSet([]byte("tags"), frontmatter.StringListValue([]string{"b", "c"}))
+	overlay.MustSet([]byte("assignee"), frontmatter.DeleteValue())
+	overlay.MustSet([]byte("parent"), frontmatter.StringValue("x-1"))
+
+	cases := []struct {
+		name string
+		opts frontmatter.MergeOptions
+		want string
+	}{
+		{
+			name: "OverlayWins",
+			opts: frontmatter.MergeOptions{},
+			want: "assignee: alice\nparent: x-1\npriority: 2\nstatus: closed\ntags:\n  - b\n  - c\n",
+		},
+		{
+			name: "BaseWins",
+			opts: frontmatter.MergeOptions{Conflict: frontmatter.MergeBaseWins},
+			want: "assignee: alice\nparent: x-1\npriority: 2\nstatus: open\ntags:\n  - a\n  - b\n",
+		},
+		{
+			name: "UnionAndDeletes",
+			opts: frontmatter.MergeOptions{Lists: frontmatter.MergeListsUnion, Deletes: true},
+			want: "parent: x-1\npriority: 2\nstatus: closed\ntags:\n  - a\n  - b\n  - c\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			merged := frontmatter.Merge(base, overlay, tc.opts)
+
+			got, err := merged.MarshalYAML(frontmatter.WithYAMLDelimiters(false))
+			if err != nil {
+				t.Fatalf("marshal merged: %v", err)
+			}
+
+			if got != tc.want {
+				t.Fatalf("merged mismatch:\ngot:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+
+	tags, _ := base.GetList([]byte("tags"))
+	if strings.Join(tags, ",") != "a,b" || !base.Has([]byte("assignee")) {
+		t.Fatalf("base modified: tags=%v", tags)
+	}
+}
+
+func Test_Frontmatter_MarshalYAML_ReturnsError_When_DeleteMarkerPresent(t *testing.T) {
+	t.Parallel()
+
+	var fm frontmatter.Frontmatter
+
+	fm.MustSet([]byte("status"), frontmatter.DeleteValue())
+
+	_, err := fm.MarshalYAML()
+	if err == nil {
+		t.Fatal("expected error for delete marker")
+	}
+}
+
+func Test_FrontmatterParser_ReturnsError_When_StrictAndReservedKeySet(t *testing.T) {
+	t.Parallel()
+
+	reserved := []string{"id", "schema_version", "title"}
+	payload := []byte(wrapFrontmatter("status: open\ntitle: Mine", "body\n"))
+
+	_, _, err := frontmatter.ParseStrict(payload, reserved)
+	if !errors.Is(err, frontmatter.ErrReservedKey) {
+		t.Fatalf("expected ErrReservedKey, got %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "title") {
+		t.Fatalf("error should name the key: %v", err)
+	}
+
+	fm, tail, err := frontmatter.ParseStrict([]byte(wrapFrontmatter("status: open", "body\n")), reserved)
+	if err != nil {
+		t.Fatalf("parse strict: %v", err)
+	}
+
+	if status, _ := fm.GetString([]byte("status")); status != "open" || string(tail) != "body\n" {
+		t.Fatalf("status=%q tail=%q", status, tail)
+	}
+
+	_, _, err = frontmatter.ParseStrict([]byte(wrapFrontmatter("status: open\nstatus: closed", "")), nil)
+	if err == nil || !strings.Contains(err.Error(), "duplicate key") {
+		t.Fatalf("expected duplicate key error, got %v", err)
+	}
+}
+
 func wrapFrontmatter(fmContent string, tail string) string {
 	if fmContent == "" {
 		return strings.Join([]string{
@@ -855,6 +956,17 @@ func wrapFrontmatter(fmContent string, tail string) string {
 	}, "\n")
 }
 
+func mustParseFrontmatter(t *testing.T, fmContent string) frontmatter.Frontmatter {
+	t.Helper()
+
+	fm, _, err := frontmatter.ParseBytes([]byte(wrapFrontmatter(fmContent, "")))
+	if err != nil {
+		t.Fatalf("parse frontmatter: %v", err)
+	}
+
+	return fm
+}
+
 func Benchmark_FrontmatterParser_Parse(b *testing.B) {
 	payload := []byte(wrapFrontmatter(strings.Join([]string{
 		"id: 018f5f25-7e7d-7f0a-8c5c-123456789abc",
@@ -0,0 +1,110 @@
+package frontmatter
+
+import (
+	"bytes"
+	"slices"
+)
+
+// DeleteValue returns the delete marker for [Merge] overlays.
+func DeleteValue() *Value {
+	return &Value{Kind: ValueDelete}
+}
+
+// MergeConflict selects the winner when base and overlay both set a key.
+type MergeConflict uint8
+
+// MergeConflict values.
+const (
+	// MergeOverlayWins keeps the overlay value. This is the default.
+	MergeOverlayWins MergeConflict = iota
+
+	// MergeBaseWins keeps the base value; the overlay only adds missing keys.
+	MergeBaseWins
+)
+
+// MergeLists selects how two list values for the same key combine.
+type MergeLists uint8
+
+// MergeLists values.
+const (
+	// MergeListsReplace treats lists like any other value (see [MergeConflict]).
+	// This is the default.
+	MergeListsReplace MergeLists = iota
+
+	// MergeListsUnion keeps the base items in order, then appends overlay items
+	// not already present. Applies only when both values are lists.
+	MergeListsUnion
+)
+
+// MergeOptions configures [Merge].
+type MergeOptions struct {
+	Conflict MergeConflict
+	Lists    MergeLists
+
+	// Deletes makes overlay entries holding [DeleteValue] remove the key from
+	// the result, regardless of Conflict. If false, they are ignored.
+	Deletes bool
+}
+
+// Merge returns base with overlay applied, leaving both inputs unchanged.
+//
+// Keys keep base order; keys only in overlay follow in overlay order. Values
+// are copied shallowly, so borrowed data stays borrowed from the input buffers
+// (list slices built by [MergeListsUnion] are new, their items are not).
+// Delete markers never appear in the result.
+func Merge(base, overlay Frontmatter, opts MergeOptions) Frontmatter {
+	entries := make([]Entry, 0, len(base.entries)+len(overlay.entries))
+
+	for _, entry := range base.entries {
+		if entry.Value.Kind == ValueDelete {
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	for _, entry := range overlay.entries {
+		i := slices.IndexFunc(entries, func(e Entry) bool { return bytes.Equal(e.Key, entry.Key) })
+
+		if entry.Value.Kind == ValueDelete {
+			if opts.Deletes && i >= 0 {
+				entries = slices.Delete(entries, i, i+1)
+			}
+
+			continue
+		}
+
+		if i < 0 {
+			entries = append(entries, entry)
+
+			continue
+		}
+
+		current := &entries[i].Value
+
+		if opts.Lists == MergeListsUnion && current.Kind == ValueList && entry.Value.Kind == ValueList {
+			current.List = unionList(current.List, entry.Value.List)
+
+			continue
+		}
+
+		if opts.Conflict == MergeOverlayWins {
+			*current = entry.Value
+		}
+	}
+
+	return Frontmatter{entries: entries}
+}
+
+// unionList returns base followed by the items of overlay not in base.
+func unionList(base, overlay [][]byte) [][]byte {
+	out := slices.Clone(base)
+
+	for _, item := range overlay {
+		if !containsKeyBytes(out, item) {
+			out = append(out, item)
+		}
+	}
+
+	return out
+}
@@ -116,6 +116,33 @@ func ParseBytes(src []byte, opts ...ParseOption) (Frontmatter, []byte, error) {
 	return fm, tail, nil
 }
 
+// ErrReservedKey is returned by [ParseStrict] when the input sets a reserved key.
+var ErrReservedKey = errors.New("reserved key")
+
+// ParseStrict is like [ParseBytes] but rejects frontmatter that sets any of
+// the reserved keys. Use it for user-supplied input that must not set fields
+// the caller manages itself (for example mddb's id, schema_version and title);
+// the error wraps [ErrReservedKey] and names the first offending key.
+//
+// Duplicate keys need no option here: both ParseStrict and ParseBytes always
+// reject them.
+func ParseStrict(src []byte, reserved []string, opts ...ParseOption) (Frontmatter, []byte, error) {
+	fm, tail, err := ParseBytes(src, opts...)
+	if err != nil {
+		return Frontmatter{}, nil, err
+	}
+
+	for i := range fm.entries {
+		for _, key := range reserved {
+			if string(fm.entries[i].Key) == key {
+				return Frontmatter{}, nil, fmt.Errorf("%w: %s", ErrReservedKey, key)
+			}
+		}
+	}
+
+	return fm, tail, nil
+}
+
 type lineToken struct {
 	data []byte
 	num  int
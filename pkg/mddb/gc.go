@@ -0,0 +1,201 @@
+package mddb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	iofs "io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// GCOptions configures [MDDB.GC].
+type GCOptions struct {
+	// Vacuum also runs VACUUM when checkpointing the index; see
+	// [CheckpointOptions.Vacuum].
+	Vacuum bool
+}
+
+// GCResult summarizes [MDDB.GC].
+type GCResult struct {
+	RemovedDirs        int // empty directories under the data dir
+	RemovedBodies      int // body files no document references (see [Config.DedupBodies])
+	RemovedAttachments int // attachment directories of documents that no longer exist
+}
+
+// GC removes what a long-lived store leaves behind: empty directories under
+// the data dir (e.g. date buckets emptied by deletes; dot-directories such as
+// .git are left alone), body files no document
+// references, and attachments of documents whose file is gone. Then it
+// checkpoints the index like [MDDB.Checkpoint]; skipped with [IndexNone].
+//
+// Holds the exclusive lock while it runs. Only unreferenced data is removed
+// and directories are removed only while empty, so a crash midway leaves a
+// consistent store and GC can simply run again. Removals are not fsynced;
+// one lost to a crash is redone by the next GC.
+//
+// Returns [ErrClosed] if store is closed. A document whose frontmatter fails
+// to parse fails with [*IndexScanError] before anything is removed.
+func (mddb *MDDB[T]) GC(ctx context.Context, opts GCOptions) (GCResult, error) {
+	if ctx == nil {
+		return GCResult{}, errors.New("context is nil")
+	}
+
+	if mddb == nil || mddb.closed.Load() {
+		return GCResult{}, ErrClosed
+	}
+
+	release, err := mddb.acquireWriteLockWithWalRecover(ctx)
+	if err != nil {
+		return GCResult{}, fmt.Errorf("acquiring write lock: %w", err)
+	}
+
+	defer func() { _ = release() }()
+
+	var result GCResult
+
+	result.RemovedBodies, err = mddb.pruneBodiesLocked(ctx)
+	if err != nil {
+		return result, fmt.Errorf("pruning bodies: %w", err)
+	}
+
+	result.RemovedAttachments, err = mddb.pruneAttachmentsLocked(ctx)
+	if err != nil {
+		return result, fmt.Errorf("pruning attachments: %w", err)
+	}
+
+	result.RemovedDirs, err = mddb.removeEmptyDirsLocked(ctx)
+	if err != nil {
+		return result, fmt.Errorf("removing empty dirs: %w", err)
+	}
+
+	if !mddb.hasIndex() {
+		return result, nil
+	}
+
+	err = mddb.checkpointLocked(ctx, CheckpointOptions{Vacuum: opts.Vacuum})
+	if err != nil {
+		return result, fmt.Errorf("checkpointing index: %w", err)
+	}
+
+	return result, nil
+}
+
+// pruneAttachmentsLocked removes the attachment directories of IDs whose
+// document file does not exist. Must be called with the write lock held.
+func (mddb *MDDB[T]) pruneAttachmentsLocked(ctx context.Context) (int, error) {
+	if mddb.cfg.RelPathFromID == nil {
+		return 0, nil
+	}
+
+	root := filepath.Join(mddb.dataDir, ".mddb", attachmentsDirName)
+
+	entries, err := mddb.fs.ReadDir(root)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+
+		return 0, fmt.Errorf("fs: %w", err)
+	}
+
+	removed := 0
+
+	for _, entry := range entries {
+		err = ctx.Err()
+		if err != nil {
+			return removed, fmt.Errorf("canceled: %w", context.Cause(ctx))
+		}
+
+		id := entry.Name()
+		if !entry.IsDir() || validatePathComponent(id) != nil {
+			continue
+		}
+
+		// Keep attachments whose document can't be located; they may belong
+		// to IDs the current RelPathFromID does not produce.
+		path := mddb.cfg.RelPathFromID(id)
+		if mddb.validateRelPath(path) != nil {
+			continue
+		}
+
+		_, statErr := mddb.fs.Stat(filepath.Join(mddb.dataDir, path))
+		if statErr == nil {
+			continue
+		}
+
+		if !errors.Is(statErr, os.ErrNotExist) {
+			return removed, withContext(fmt.Errorf("fs: %w", statErr), id, path)
+		}
+
+		err = mddb.fs.RemoveAll(filepath.Join(root, id))
+		if err != nil {
+			return removed, withContext(fmt.Errorf("fs: %w", err), id, path)
+		}
+
+		removed++
+	}
+
+	return removed, nil
+}
+
+// removeEmptyDirsLocked removes empty directories under the data dir,
+// deepest first so parents emptied by their children go too. The data dir
+// itself and dot-directories with everything below them (.mddb, but also
+// trees mddb does not own, like .git) are kept. Must be called with the
+// write lock held.
+func (mddb *MDDB[T]) removeEmptyDirsLocked(ctx context.Context) (int, error) {
+	root := filepath.Clean(mddb.dataDir)
+
+	var dirs []string
+
+	err := mddb.fs.WalkDir(root, func(path string, d iofs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+
+		if !d.IsDir() || path == root {
+			return nil
+		}
+
+		if strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		dirs = append(dirs, path)
+
+		return ctx.Err()
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, fmt.Errorf("canceled: %w", context.Cause(ctx))
+		}
+
+		return 0, fmt.Errorf("fs: %w", err)
+	}
+
+	removed := 0
+
+	// WalkDir visits parents before children, so reverse order is deepest first.
+	for _, dir := range slices.Backward(dirs) {
+		entries, readErr := mddb.fs.ReadDir(dir)
+		if readErr != nil {
+			return removed, fmt.Errorf("fs: %w", readErr)
+		}
+
+		if len(entries) > 0 {
+			continue
+		}
+
+		removeErr := mddb.fs.Remove(dir)
+		if removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
+			return removed, fmt.Errorf("fs: %w", removeErr)
+		}
+
+		removed++
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,129 @@
+package mddb_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/calvinalkan/agent-task/pkg/mddb"
+)
+
+func Test_GC_Removes_Empty_Dirs_And_Orphans_When_Docs_Are_Gone(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	s := openTestStore(t, dir, withTestDedupBodies())
+
+	defer func() { _ = s.Close() }()
+
+	kept := newTestDoc(t, "Kept")
+	kept.DocBody = "Kept body\n"
+	deleted := newTestDoc(t, "Deleted")
+	deleted.DocBody = "Deleted body\n"
+	removed := newTestDoc(t, "Removed Externally")
+
+	createTestDoc(t.Context(), t, s, kept)
+	createTestDoc(t.Context(), t, s, deleted)
+	createTestDoc(t.Context(), t, s, removed)
+	putTestAttachment(t, s, removed.DocID, "log.txt", "orphan")
+	putTestAttachment(t, s, kept.DocID, "log.txt", "kept")
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	err = tx.Delete(deleted.DocID)
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	err = tx.Commit(t.Context())
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	err = os.Remove(filepath.Join(dir, removed.DocPath))
+	if err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+
+	err = os.MkdirAll(filepath.Join(dir, "2001", "01-01", "nested"), 0o750)
+	if err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	err = os.MkdirAll(filepath.Join(dir, ".git", "refs", "tags"), 0o750)
+	if err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	result, err := s.GC(t.Context(), mddb.GCOptions{})
+	if err != nil {
+		t.Fatalf("gc: %v", err)
+	}
+
+	if result.RemovedDirs < 3 || result.RemovedBodies != 1 || result.RemovedAttachments != 1 {
+		t.Fatalf("result = %+v, want >= 3 dirs, 1 body, 1 attachment", result)
+	}
+
+	_, err = os.Stat(filepath.Join(dir, "2001"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("empty dir tree: got %v, want not exist", err)
+	}
+
+	_, err = os.Stat(filepath.Join(dir, ".mddb"))
+	if err != nil {
+		t.Fatalf(".mddb: %v", err)
+	}
+
+	_, err = os.Stat(filepath.Join(dir, ".git", "refs", "tags"))
+	if err != nil {
+		t.Fatalf("dot-directory not owned by mddb: %v", err)
+	}
+
+	got, err := s.Get(t.Context(), kept.DocID)
+	if err != nil || got.DocBody != "Kept body\n" {
+		t.Fatalf("kept doc: got %+v, %v", got, err)
+	}
+
+	data, err := s.GetAttachment(t.Context(), kept.DocID, "log.txt")
+	if err != nil || string(data) != "kept" {
+		t.Fatalf("kept attachment: got %q, %v", data, err)
+	}
+
+	again, err := s.GC(t.Context(), mddb.GCOptions{Vacuum: true})
+	if err != nil {
+		t.Fatalf("second gc: %v", err)
+	}
+
+	if again != (mddb.GCResult{}) {
+		t.Fatalf("second gc result = %+v, want nothing removed", again)
+	}
+}
+
+func Test_GC_Skips_Checkpoint_When_Store_Has_No_Index(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	s := openTestStore(t, dir, withTestNoIndex())
+
+	defer func() { _ = s.Close() }()
+
+	createTestDoc(t.Context(), t, s, newTestDoc(t, "Doc"))
+
+	err := os.Mkdir(filepath.Join(dir, "empty"), 0o750)
+	if err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	result, err := s.GC(t.Context(), mddb.GCOptions{Vacuum: true})
+	if err != nil {
+		t.Fatalf("gc: %v", err)
+	}
+
+	if result.RemovedDirs != 1 {
+		t.Fatalf("removed dirs = %d, want 1", result.RemovedDirs)
+	}
+}
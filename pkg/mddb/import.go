@@ -0,0 +1,306 @@
+package mddb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/calvinalkan/fileproc"
+
+	"github.com/calvinalkan/agent-task/pkg/mddb/frontmatter"
+)
+
+const defaultImportBatchSize = 1000
+
+// ImportOptions configures [MDDB.ImportDir].
+type ImportOptions struct {
+	// BatchSize is the number of documents committed per WAL transaction.
+	// A crash leaves each batch either fully applied or not at all.
+	// Default: 1000.
+	BatchSize int
+
+	// SkipInvalid skips source files that fail validation (including
+	// documents that already exist, unless Overwrite is set) and reports them
+	// in [ImportResult.Issues]. If false, any invalid file aborts the import
+	// before anything is written.
+	SkipInvalid bool
+
+	// Overwrite replaces documents that already exist in the store.
+	// If false, an existing ID is an issue (see SkipInvalid).
+	Overwrite bool
+}
+
+// ImportResult summarizes [MDDB.ImportDir].
+type ImportResult struct {
+	Created int
+	Updated int
+
+	// Issues lists skipped files. Path is relative to the source directory.
+	Issues []*Error
+}
+
+// ImportDir copies the markdown documents under srcDir into the store.
+//
+// Runs in two phases. First every "*.md" file under srcDir is parsed and
+// validated like a store file; documents are placed at [Config.RelPathFromID]
+// regardless of their location in srcDir. Then the documents are written in
+// ID order through [Tx] in batches of [ImportOptions.BatchSize], so the
+// index is updated as each batch commits and no reindex is needed.
+//
+// Without [ImportOptions.SkipInvalid], validation issues abort the import
+// before any write and are returned as [*IndexScanError]. A failure while
+// writing keeps earlier batches committed; the returned result counts them.
+//
+// A srcDir that is missing or unreadable fails the import regardless of
+// SkipInvalid. Returns [ErrClosed] if store is closed.
+func (mddb *MDDB[T]) ImportDir(ctx context.Context, srcDir string, opts ImportOptions) (ImportResult, error) {
+	var result ImportResult
+
+	if ctx == nil {
+		return result, errors.New("context is nil")
+	}
+
+	if mddb == nil || mddb.closed.Load() {
+		return result, ErrClosed
+	}
+
+	if srcDir == "" {
+		return result, errors.New("source directory is empty")
+	}
+
+	batchSize := opts.BatchSize
+	if batchSize < 0 {
+		return result, errors.New("ImportOptions.BatchSize must be non-negative")
+	}
+
+	if batchSize == 0 {
+		batchSize = defaultImportBatchSize
+	}
+
+	// The walk reports an unreadable root like any unreadable file, which
+	// SkipInvalid would turn into a successful import of nothing.
+	err := checkImportDir(srcDir)
+	if err != nil {
+		return result, err
+	}
+
+	docs, issues, err := mddb.parseImportDir(ctx, srcDir)
+	if err != nil {
+		return result, err
+	}
+
+	if !opts.Overwrite {
+		docs, issues, err = mddb.dropExistingImports(docs, issues)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	if len(issues) > 0 && !opts.SkipInvalid {
+		return result, &IndexScanError{Issues: issues}
+	}
+
+	result.Issues = issues
+
+	for start := 0; start < len(docs); start += batchSize {
+		batch := docs[start:min(start+batchSize, len(docs))]
+
+		err = mddb.importBatch(ctx, batch, opts.Overwrite, &result)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// importDoc is a validated source document awaiting import.
+type importDoc[T Document] struct {
+	id      string
+	srcPath string // relative to the source directory
+	doc     *T
+}
+
+// checkImportDir returns an error unless srcDir is a readable directory.
+func checkImportDir(srcDir string) error {
+	dir, err := os.Open(filepath.Clean(srcDir))
+	if err != nil {
+		return fmt.Errorf("source directory: %w", err)
+	}
+
+	defer func() { _ = dir.Close() }()
+
+	info, err := dir.Stat()
+	if err != nil {
+		return fmt.Errorf("source directory: %w", err)
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("source directory: %s is not a directory", srcDir)
+	}
+
+	return nil
+}
+
+// parseImportDir parses all source files, returning documents sorted by ID
+// and one issue per invalid file or duplicate ID.
+func (mddb *MDDB[T]) parseImportDir(ctx context.Context, srcDir string) ([]importDoc[T], []*Error, error) {
+	var (
+		mu   sync.Mutex
+		docs []importDoc[T]
+	)
+
+	_, errs := fileproc.Process(ctx, filepath.Clean(srcDir), func(f *fileproc.File, _ *fileproc.FileWorker) (*struct{}, error) {
+		srcPath := string(f.RelPath())
+		if isInternalPath(f.RelPath()) {
+			return nil, fileproc.ErrSkip
+		}
+
+		data, err := f.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("fs: %w", err)
+		}
+
+		doc, err := mddb.parseImportFile(data)
+		if err != nil {
+			return nil, err
+		}
+
+		mu.Lock()
+		docs = append(docs, importDoc[T]{id: (*doc).ID(), srcPath: srcPath, doc: doc})
+		mu.Unlock()
+
+		return nil, fileproc.ErrSkip
+	}, fileproc.WithRecursive(), fileproc.WithSuffix(".md"))
+
+	err := ctx.Err()
+	if err != nil {
+		return nil, nil, fmt.Errorf("canceled: %w", context.Cause(ctx))
+	}
+
+	var issues []*Error
+
+	scanErr := &IndexScanError{}
+	if errors.As(toIndexScanError(errs), &scanErr) {
+		issues = scanErr.Issues
+	}
+
+	slices.SortFunc(docs, func(a, b importDoc[T]) int {
+		return strings.Compare(a.id+"\x00"+a.srcPath, b.id+"\x00"+b.srcPath)
+	})
+
+	// Keep the first file per ID; later ones are reported.
+	unique := docs[:0]
+
+	for i, d := range docs {
+		if i > 0 && docs[i-1].id == d.id {
+			issues = append(issues, &Error{
+				ID:   d.id,
+				Path: d.srcPath,
+				Err:  fmt.Errorf("duplicate id (also in %s)", docs[i-1].srcPath),
+			})
+
+			continue
+		}
+
+		unique = append(unique, d)
+	}
+
+	return unique, issues, nil
+}
+
+// parseImportFile parses a source file as if it were stored at the path
+// derived from its ID.
+func (mddb *MDDB[T]) parseImportFile(data []byte) (*T, error) {
+	fm, _, err := frontmatter.ParseBytes(data, mddb.cfg.ParseOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("frontmatter: %w", err)
+	}
+
+	id, ok := fm.GetString(frontmatterKeyID)
+	if !ok || id == "" {
+		return nil, fmt.Errorf("frontmatter: %w", errEmptyID)
+	}
+
+	relPath := mddb.cfg.RelPathFromID(id)
+	if relPath == "" {
+		return nil, withContext(errEmptyPath, id, "")
+	}
+
+	doc, err := mddb.parseDocument(relPath, data, 0, 0, id)
+	if err != nil {
+		return nil, withContext(err, id, "")
+	}
+
+	return doc, nil
+}
+
+// dropExistingImports moves documents whose target file already exists from
+// docs to issues.
+func (mddb *MDDB[T]) dropExistingImports(docs []importDoc[T], issues []*Error) ([]importDoc[T], []*Error, error) {
+	kept := docs[:0]
+
+	for _, d := range docs {
+		exists, err := mddb.fs.Exists(filepath.Join(mddb.dataDir, mddb.cfg.RelPathFromID(d.id)))
+		if err != nil {
+			return nil, nil, withContext(fmt.Errorf("fs: %w", err), d.id, d.srcPath)
+		}
+
+		if exists {
+			issues = append(issues, &Error{ID: d.id, Path: d.srcPath, Err: ErrAlreadyExists})
+
+			continue
+		}
+
+		kept = append(kept, d)
+	}
+
+	return kept, issues, nil
+}
+
+// importBatch writes one batch in a single transaction.
+func (mddb *MDDB[T]) importBatch(ctx context.Context, batch []importDoc[T], overwrite bool, result *ImportResult) error {
+	tx, err := mddb.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	var created, updated int
+
+	for _, d := range batch {
+		_, err = tx.Create(d.doc)
+		if err == nil {
+			created++
+
+			continue
+		}
+
+		if !errors.Is(err, ErrAlreadyExists) || !overwrite {
+			return withContext(err, d.id, d.srcPath)
+		}
+
+		_, err = tx.Update(d.doc)
+		if err != nil {
+			return withContext(err, d.id, d.srcPath)
+		}
+
+		updated++
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("committing batch: %w", err)
+	}
+
+	result.Created += created
+	result.Updated += updated
+
+	return nil
+}
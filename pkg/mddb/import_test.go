@@ -0,0 +1,192 @@
+package mddb_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/calvinalkan/agent-task/pkg/mddb"
+)
+
+func Test_ImportDir_Imports_Docs_When_Source_Is_Valid(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := t.TempDir()
+
+	s := openTestStore(t, dir)
+
+	defer func() { _ = s.Close() }()
+
+	docs := []*TestDoc{newTestDoc(t, "One"), newTestDoc(t, "Two"), newTestDoc(t, "Three")}
+	writeRawDocFile(t, src, "one.md", docs[0])
+	writeRawDocFile(t, src, "nested/deeper/two.md", docs[1])
+	writeRawDocFile(t, src, "three.md", docs[2])
+
+	result, err := s.ImportDir(t.Context(), src, mddb.ImportOptions{BatchSize: 2})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+
+	if result.Created != 3 || result.Updated != 0 || len(result.Issues) != 0 {
+		t.Fatalf("result = %+v, want 3 created", result)
+	}
+
+	for _, doc := range docs {
+		got, getErr := s.Get(t.Context(), doc.DocID)
+		if getErr != nil {
+			t.Fatalf("get %s: %v", doc.DocTitle, getErr)
+		}
+
+		if got.DocTitle != doc.DocTitle {
+			t.Fatalf("title = %q, want %q", got.DocTitle, doc.DocTitle)
+		}
+
+		// Placed by RelPathFromID, not by source layout.
+		_, statErr := os.Stat(filepath.Join(dir, doc.DocPath))
+		if statErr != nil {
+			t.Fatalf("stat %s: %v", doc.DocPath, statErr)
+		}
+	}
+}
+
+func Test_ImportDir_Writes_Nothing_When_A_File_Is_Invalid(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := t.TempDir()
+
+	s := openTestStore(t, dir)
+
+	defer func() { _ = s.Close() }()
+
+	valid := newTestDoc(t, "Valid")
+	writeRawDocFile(t, src, "valid.md", valid)
+	writeRawPath(t, src, "broken.md", "no frontmatter here\n")
+
+	_, err := s.ImportDir(t.Context(), src, mddb.ImportOptions{})
+
+	scanErr := &mddb.IndexScanError{}
+	if !errors.As(err, &scanErr) {
+		t.Fatalf("import: got %v, want IndexScanError", err)
+	}
+
+	if len(scanErr.Issues) != 1 || scanErr.Issues[0].Path != "broken.md" {
+		t.Fatalf("issues = %v, want one for broken.md", scanErr.Issues)
+	}
+
+	_, err = s.Get(t.Context(), valid.DocID)
+	if !errors.Is(err, mddb.ErrNotFound) {
+		t.Fatalf("get valid: got %v, want ErrNotFound", err)
+	}
+}
+
+func Test_ImportDir_Reports_Issues_When_SkipInvalid(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := t.TempDir()
+
+	s := openTestStore(t, dir)
+
+	defer func() { _ = s.Close() }()
+
+	existing := createTestDoc(t.Context(), t, s, newTestDoc(t, "Existing"))
+	fresh := newTestDoc(t, "Fresh")
+
+	writeRawDocFile(t, src, "existing.md", existing)
+	writeRawDocFile(t, src, "fresh.md", fresh)
+	writeRawDocFile(t, src, "fresh-copy.md", fresh)
+	writeRawPath(t, src, "broken.md", "no frontmatter here\n")
+
+	result, err := s.ImportDir(t.Context(), src, mddb.ImportOptions{SkipInvalid: true})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+
+	if result.Created != 1 {
+		t.Fatalf("created = %d, want 1", result.Created)
+	}
+
+	issues := make(map[string]error, len(result.Issues))
+	for _, issue := range result.Issues {
+		issues[issue.Path] = issue.Err
+	}
+
+	if len(issues) != 3 {
+		t.Fatalf("issues = %v, want broken, existing and duplicate", result.Issues)
+	}
+
+	if _, ok := issues["broken.md"]; !ok {
+		t.Fatalf("missing issue for broken.md: %v", result.Issues)
+	}
+
+	if !errors.Is(issues["existing.md"], mddb.ErrAlreadyExists) {
+		t.Fatalf("existing.md issue = %v, want ErrAlreadyExists", issues["existing.md"])
+	}
+
+	if _, ok := issues["fresh.md"]; !ok {
+		t.Fatalf("missing duplicate issue for fresh.md: %v", result.Issues)
+	}
+
+	_, err = s.Get(t.Context(), fresh.DocID)
+	if err != nil {
+		t.Fatalf("get fresh: %v", err)
+	}
+}
+
+func Test_ImportDir_Returns_Error_When_Source_Dir_Is_Missing_Even_With_SkipInvalid(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(t.TempDir(), "missing")
+
+	s := openTestStore(t, dir)
+
+	defer func() { _ = s.Close() }()
+
+	result, err := s.ImportDir(t.Context(), src, mddb.ImportOptions{SkipInvalid: true})
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("import: got %v, want os.ErrNotExist", err)
+	}
+
+	if result.Created != 0 || len(result.Issues) != 0 {
+		t.Fatalf("result = %+v, want empty", result)
+	}
+}
+
+func Test_ImportDir_Updates_Existing_Docs_When_Overwrite(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := t.TempDir()
+
+	s := openTestStore(t, dir)
+
+	defer func() { _ = s.Close() }()
+
+	existing := createTestDoc(t.Context(), t, s, newTestDoc(t, "Before"))
+
+	changed := *existing
+	changed.DocTitle = "After"
+	writeRawDocFile(t, src, "changed.md", &changed)
+
+	result, err := s.ImportDir(t.Context(), src, mddb.ImportOptions{Overwrite: true})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+
+	if result.Created != 0 || result.Updated != 1 {
+		t.Fatalf("result = %+v, want 1 updated", result)
+	}
+
+	got, err := s.Get(t.Context(), existing.DocID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if got.DocTitle != "After" {
+		t.Fatalf("title = %q, want After", got.DocTitle)
+	}
+}
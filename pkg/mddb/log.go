@@ -0,0 +1,40 @@
+package mddb
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// discardLogger is the default [Config.Logger].
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// logLockWait logs an attempt to take the file lock in mode ("read" or
+// "write") that started at start.
+func (mddb *MDDB[T]) logLockWait(ctx context.Context, mode string, start time.Time, err error) {
+	if err != nil {
+		mddb.cfg.Logger.WarnContext(ctx, "mddb: lock failed", "mode", mode, "wait", time.Since(start), "err", err)
+
+		return
+	}
+
+	mddb.cfg.Logger.DebugContext(ctx, "mddb: lock acquired", "mode", mode, "wait", time.Since(start))
+}
+
+// logReindex logs the outcome of a reindex that started at start.
+func (mddb *MDDB[T]) logReindex(ctx context.Context, incremental bool, start time.Time, result IncrementalIndexResult, err error) {
+	if err != nil {
+		mddb.cfg.Logger.ErrorContext(ctx, "mddb: reindex failed", "incremental", incremental, "duration", time.Since(start), "err", err)
+
+		return
+	}
+
+	mddb.cfg.Logger.InfoContext(ctx, "mddb: reindex finished",
+		"incremental", incremental,
+		"duration", time.Since(start),
+		"total", result.Total,
+		"inserted", result.Inserted,
+		"updated", result.Updated,
+		"deleted", result.Deleted,
+		"skipped", result.Skipped)
+}
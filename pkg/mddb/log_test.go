@@ -0,0 +1,48 @@
+package mddb_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/calvinalkan/agent-task/pkg/mddb"
+)
+
+func Test_Logger_Records_Internal_Operations_When_Configured(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	cfg := testConfig(t.TempDir())
+	cfg.Logger = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	s, err := mddb.Open(t.Context(), cfg)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	defer func() { _ = s.Close() }()
+
+	doc := newTestDoc(t, "Logged")
+	createTestDoc(t.Context(), t, s, doc)
+
+	_, err = s.Reindex(t.Context())
+	if err != nil {
+		t.Fatalf("reindex: %v", err)
+	}
+
+	out := buf.String()
+
+	for _, want := range []string{
+		`msg="mddb: lock acquired" mode=write`,
+		`msg="mddb: wal synced" ops=1`,
+		`msg="mddb: file written" doc_id=` + doc.DocID + ` doc_path=` + doc.DocPath,
+		`msg="mddb: index updated" puts=1 deletes=0`,
+		`msg="mddb: reindex finished" incremental=false`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("log missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -3,15 +3,18 @@ package mddb
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
-	_ "github.com/mattn/go-sqlite3" // sqlite3 driver
+	"github.com/mattn/go-sqlite3"
 
 	"github.com/calvinalkan/agent-task/pkg/fs"
 	"github.com/calvinalkan/agent-task/pkg/mddb/frontmatter"
@@ -20,6 +23,10 @@ import (
 // ErrClosed indicates an operation was attempted on a closed MDDB.
 var ErrClosed = errors.New("mddb closed")
 
+// ErrNeedsReindex indicates a write was attempted while the index was built
+// with a schema other than [Config.SQLSchema]; see [Config.DeferReindex].
+var ErrNeedsReindex = errors.New("index schema outdated: reindex required")
+
 // MDDB provides document storage with SQLite indexing and WAL-based crash recovery.
 //
 // Stores [Document] implementations as markdown files with YAML frontmatter.
@@ -38,6 +45,8 @@ var ErrClosed = errors.New("mddb closed")
 type MDDB[T Document] struct {
 	cfg         Config[T]
 	dataDir     string
+	indexPath   string
+	sqliteOpts  sqliteOptions
 	schema      *SQLSchema
 	sql         *sql.DB
 	fs          fs.FS
@@ -48,6 +57,9 @@ type MDDB[T Document] struct {
 	lockTimeout time.Duration
 	closed      atomic.Bool
 
+	// prefixIdx serves [MDDB.GetByPrefix]; see [prefixIndex].
+	prefixIdx prefixIndex
+
 	// mu guards in-process concurrent access to the MDDB.
 	//
 	// File locking (flock) coordinates across processes but not within a process -
@@ -86,6 +98,17 @@ func Open[T Document](ctx context.Context, cfg Config[T]) (*MDDB[T], error) {
 		return nil, errors.New("Config.DocumentFrom is required")
 	}
 
+	switch cfg.IndexMode {
+	case IndexSQLite:
+	case IndexNone:
+		err := validateNoIndexConfig(&cfg)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown Config.IndexMode %d", cfg.IndexMode)
+	}
+
 	// Default path layout: flat (id.md)
 	if cfg.RelPathFromID == nil {
 		cfg.RelPathFromID = func(id string) string { return id + ".md" }
@@ -96,6 +119,30 @@ func Open[T Document](ctx context.Context, cfg Config[T]) (*MDDB[T], error) {
 		cfg.ShortIDFromID = func(id string) string { return id }
 	}
 
+	if cfg.Logger == nil {
+		cfg.Logger = discardLogger
+	}
+
+	if cfg.NewID != nil && cfg.SetID == nil {
+		return nil, errors.New("Config.SetID is required when Config.NewID is set")
+	}
+
+	if err := validateReservedFields(cfg.ReservedFields); err != nil {
+		return nil, fmt.Errorf("Config.ReservedFields: %w", err)
+	}
+
+	if cfg.PopulateReserved != nil && len(cfg.ReservedFields) == 0 {
+		return nil, errors.New("Config.PopulateReserved requires Config.ReservedFields")
+	}
+
+	if err := validateTimestamps(cfg.Timestamps); err != nil {
+		return nil, fmt.Errorf("Config.Timestamps: %w", err)
+	}
+
+	if cfg.Clock == nil {
+		cfg.Clock = time.Now
+	}
+
 	// Default schema if not provided
 	schema := cfg.SQLSchema
 	if schema == nil {
@@ -136,6 +183,16 @@ func Open[T Document](ctx context.Context, cfg Config[T]) (*MDDB[T], error) {
 		return nil, fmt.Errorf("creating internal mddb dir: fs: %w", err)
 	}
 
+	indexPath := filepath.Join(mddbDir, "index.sqlite")
+	if cfg.IndexPath != "" {
+		indexPath = filepath.Clean(cfg.IndexPath)
+
+		err = fsReal.MkdirAll(filepath.Dir(indexPath), 0o750)
+		if err != nil {
+			return nil, fmt.Errorf("creating index dir: fs: %w", err)
+		}
+	}
+
 	walPath := filepath.Join(mddbDir, "wal")
 
 	walFile, err := fsReal.OpenFile(walPath, os.O_RDWR|os.O_CREATE, 0o600)
@@ -143,19 +200,38 @@ func Open[T Document](ctx context.Context, cfg Config[T]) (*MDDB[T], error) {
 		return nil, fmt.Errorf("opening wal: fs: %w", err)
 	}
 
-	sqlite, err := openSqlite(ctx, filepath.Join(mddbDir, "index.sqlite"))
-	if err != nil {
-		closeErr := walFile.Close()
-		if closeErr != nil {
-			closeErr = fmt.Errorf("fs: close wal: %w", closeErr)
+	var (
+		sqliteOpts sqliteOptions
+		sqlite     *sql.DB
+	)
+
+	if cfg.IndexMode != IndexNone {
+		sqliteOpts, err = newSqliteOptions(cfg.SQLitePragmas, cfg.MaxOpenConns)
+		if err != nil {
+			closeErr := walFile.Close()
+			if closeErr != nil {
+				closeErr = fmt.Errorf("fs: close wal: %w", closeErr)
+			}
+
+			return nil, errors.Join(err, closeErr)
 		}
 
-		return nil, errors.Join(fmt.Errorf("open: %w", err), closeErr)
+		sqlite, err = openSqlite(ctx, indexPath, sqliteOpts)
+		if err != nil {
+			closeErr := walFile.Close()
+			if closeErr != nil {
+				closeErr = fmt.Errorf("fs: close wal: %w", closeErr)
+			}
+
+			return nil, errors.Join(fmt.Errorf("open: %w", err), closeErr)
+		}
 	}
 
 	mddb := &MDDB[T]{
 		cfg:         cfg,
 		dataDir:     dataDir,
+		indexPath:   indexPath,
+		sqliteOpts:  sqliteOpts,
 		schema:      schema,
 		sql:         sqlite,
 		fs:          fsReal,
@@ -166,21 +242,24 @@ func Open[T Document](ctx context.Context, cfg Config[T]) (*MDDB[T], error) {
 		lockTimeout: lockTimeout,
 	}
 
-	storedVersion, err := queryUserVersion(ctx, sqlite)
+	walSize, err := mddb.walSize()
 	if err != nil {
 		closeErr := mddb.Close()
 
-		return nil, errors.Join(fmt.Errorf("querying schema version: %w", err), closeErr)
+		return nil, errors.Join(fmt.Errorf("checking wal size: %w", err), closeErr)
 	}
 
-	expectedVersion := schema.fingerprint()
-	versionMismatch := int64(storedVersion) != expectedVersion
+	versionMismatch := false
 
-	walSize, err := mddb.walSize()
-	if err != nil {
-		closeErr := mddb.Close()
+	if cfg.IndexMode != IndexNone {
+		storedVersion, versionErr := queryUserVersion(ctx, sqlite)
+		if versionErr != nil {
+			closeErr := mddb.Close()
 
-		return nil, errors.Join(fmt.Errorf("checking wal size: %w", err), closeErr)
+			return nil, errors.Join(fmt.Errorf("querying schema version: %w", versionErr), closeErr)
+		}
+
+		versionMismatch = int64(storedVersion) != schema.fingerprint()
 	}
 
 	if !versionMismatch && walSize == 0 {
@@ -188,6 +267,11 @@ func Open[T Document](ctx context.Context, cfg Config[T]) (*MDDB[T], error) {
 		return mddb, nil
 	}
 
+	if versionMismatch && cfg.DeferReindex && walSize == 0 {
+		// Caller decides when to rebuild; see [MDDB.NeedsReindex].
+		return mddb, nil
+	}
+
 	if !versionMismatch && walSize > 0 {
 		release, lockErr := mddb.acquireWriteLockWithWalRecover(ctx)
 		if lockErr != nil {
@@ -231,6 +315,11 @@ func (mddb *MDDB[T]) Close() error {
 
 	var errs []error
 
+	dropErr := mddb.dropPrefixIndex()
+	if dropErr != nil {
+		errs = append(errs, dropErr)
+	}
+
 	if mddb.sql != nil {
 		err := mddb.sql.Close()
 		if err != nil {
@@ -252,6 +341,143 @@ func (mddb *MDDB[T]) Close() error {
 	return errors.Join(errs...)
 }
 
+// SchemaFingerprint returns the hash of the configured [Config.SQLSchema].
+//
+// The index records the fingerprint it was built with; a different value
+// means the index must be rebuilt. See [MDDB.NeedsReindex].
+func (mddb *MDDB[T]) SchemaFingerprint() uint64 {
+	return uint64(mddb.schema.fingerprint())
+}
+
+// NeedsReindex reports whether the index was built with a schema other than
+// the configured one, i.e. whether [MDDB.Reindex] is required before queries
+// can rely on the index.
+//
+// [Open] rebuilds automatically on mismatch, so this only returns true when
+// [Config.DeferReindex] is set or another process rebuilt the index with a
+// different schema. While it returns true, [MDDB.Begin] and
+// [MDDB.ReindexIncremental] fail with [ErrNeedsReindex]. Always false with
+// [IndexNone].
+//
+// Returns [ErrClosed] if store is closed.
+func (mddb *MDDB[T]) NeedsReindex(ctx context.Context) (bool, error) {
+	if ctx == nil {
+		return false, errors.New("context is nil")
+	}
+
+	if mddb == nil || mddb.closed.Load() {
+		return false, ErrClosed
+	}
+
+	release, err := mddb.acquireReadLock(ctx)
+	if err != nil {
+		return false, fmt.Errorf("acquiring read lock: %w", err)
+	}
+
+	defer func() { _ = release() }()
+
+	return mddb.needsReindexLocked(ctx)
+}
+
+// needsReindexLocked implements [MDDB.NeedsReindex]. Must be called with the
+// read or write lock held.
+func (mddb *MDDB[T]) needsReindexLocked(ctx context.Context) (bool, error) {
+	if !mddb.hasIndex() {
+		return false, nil
+	}
+
+	storedVersion, err := queryUserVersion(ctx, mddb.sql)
+	if err != nil {
+		return false, fmt.Errorf("querying schema version: %w", err)
+	}
+
+	return int64(storedVersion) != mddb.schema.fingerprint(), nil
+}
+
+// checkSchemaCurrentLocked returns [ErrNeedsReindex] if the index was built
+// with another schema. Index writes for the configured schema would fail
+// only after the WAL is durable, leaving a WAL that cannot be replayed.
+// Must be called with the write lock held.
+func (mddb *MDDB[T]) checkSchemaCurrentLocked(ctx context.Context) error {
+	needsReindex, err := mddb.needsReindexLocked(ctx)
+	if err != nil {
+		return err
+	}
+
+	if needsReindex {
+		return ErrNeedsReindex
+	}
+
+	return nil
+}
+
+// CheckpointOptions configures [MDDB.Checkpoint].
+type CheckpointOptions struct {
+	// Vacuum also rebuilds the index file with VACUUM, returning free pages
+	// to the filesystem. Takes time proportional to the index size.
+	Vacuum bool
+}
+
+// Checkpoint compacts the SQLite index: it copies SQLite's write-ahead log
+// into the database and truncates it (PRAGMA wal_checkpoint(TRUNCATE)), then
+// optionally runs VACUUM. Only the derived index is touched, never the
+// documents or mddb's own WAL, so it is always safe; it just keeps the index
+// small for the next start. Services can call it on shutdown or on a
+// schedule.
+//
+// Holds the exclusive lock, blocking reads and writes while it runs.
+//
+// Returns [ErrClosed] if store is closed and [ErrNoIndex] with [IndexNone].
+// Returns an error if the checkpoint could not complete because another
+// connection kept reading the index.
+func (mddb *MDDB[T]) Checkpoint(ctx context.Context, opts CheckpointOptions) error {
+	if ctx == nil {
+		return errors.New("context is nil")
+	}
+
+	if mddb == nil || mddb.closed.Load() {
+		return ErrClosed
+	}
+
+	if !mddb.hasIndex() {
+		return ErrNoIndex
+	}
+
+	release, err := mddb.acquireWriteLockWithWalRecover(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring write lock: %w", err)
+	}
+
+	defer func() { _ = release() }()
+
+	return mddb.checkpointLocked(ctx, opts)
+}
+
+// checkpointLocked implements [MDDB.Checkpoint]. Must be called with the
+// write lock held.
+func (mddb *MDDB[T]) checkpointLocked(ctx context.Context, opts CheckpointOptions) error {
+	if opts.Vacuum {
+		_, err := mddb.sql.ExecContext(ctx, "VACUUM")
+		if err != nil {
+			return fmt.Errorf("sqlite: vacuum: %w", err)
+		}
+	}
+
+	// VACUUM goes through the SQLite WAL too, so checkpoint after it.
+	var busy, logFrames, checkpointed int
+
+	err := mddb.sql.QueryRowContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)").Scan(&busy, &logFrames, &checkpointed)
+	if err != nil {
+		return fmt.Errorf("sqlite: wal_checkpoint: %w", err)
+	}
+
+	if busy != 0 {
+		return errors.New("sqlite: wal_checkpoint: blocked by another connection")
+	}
+
+	return nil
+}
+
 const (
 	defaultWalLockTimeout = 10 * time.Second
 	defaultTableName      = "documents"
@@ -290,7 +516,7 @@ func (mddb *MDDB[T]) walSize() (int64, error) {
 func (mddb *MDDB[T]) acquireReadLock(ctx context.Context) (func() error, error) {
 	mddb.mu.RLock()
 
-	if mddb.closed.Load() || mddb.sql == nil || mddb.wal == nil {
+	if mddb.closed.Load() || (mddb.hasIndex() && mddb.sql == nil) || mddb.wal == nil {
 		mddb.mu.RUnlock()
 
 		return nil, ErrClosed
@@ -299,7 +525,10 @@ func (mddb *MDDB[T]) acquireReadLock(ctx context.Context) (func() error, error)
 	lockCtx, cancel := context.WithTimeout(ctx, mddb.lockTimeout)
 	defer cancel()
 
+	start := time.Now()
 	flock, err := mddb.locker.RLockWithTimeout(lockCtx, mddb.lockPath)
+	mddb.logLockWait(ctx, "read", start, err)
+
 	if err != nil {
 		mddb.mu.RUnlock()
 
@@ -346,7 +575,10 @@ func (mddb *MDDB[T]) acquireReadLock(ctx context.Context) (func() error, error)
 		// WAL not empty - upgrade to write lock, replay, then re-acquire read lock.
 		_ = flock.Close()
 
+		start = time.Now()
 		writeLock, lockErr := mddb.locker.LockWithTimeout(lockCtx, mddb.lockPath)
+		mddb.logLockWait(ctx, "write", start, lockErr)
+
 		if lockErr != nil {
 			mddb.mu.RUnlock()
 
@@ -382,7 +614,7 @@ func (mddb *MDDB[T]) acquireReadLock(ctx context.Context) (func() error, error)
 func (mddb *MDDB[T]) acquireWriteLockWithWalRecover(ctx context.Context) (func() error, error) {
 	mddb.mu.Lock()
 
-	if mddb.closed.Load() || mddb.sql == nil || mddb.wal == nil {
+	if mddb.closed.Load() || (mddb.hasIndex() && mddb.sql == nil) || mddb.wal == nil {
 		mddb.mu.Unlock()
 
 		return nil, ErrClosed
@@ -391,7 +623,10 @@ func (mddb *MDDB[T]) acquireWriteLockWithWalRecover(ctx context.Context) (func()
 	lockCtx, cancel := context.WithTimeout(ctx, mddb.lockTimeout)
 	defer cancel()
 
+	start := time.Now()
 	flock, err := mddb.locker.LockWithTimeout(lockCtx, mddb.lockPath)
+	mddb.logLockWait(ctx, "write", start, err)
+
 	if err != nil {
 		mddb.mu.Unlock()
 
@@ -432,46 +667,140 @@ func (mddb *MDDB[T]) acquireWriteLockWithWalRecover(ctx context.Context) (func()
 	}, nil
 }
 
-// openSqlite opens the derived index database and applies the configured pragmas.
-func openSqlite(ctx context.Context, path string) (*sql.DB, error) {
-	if path == "" {
-		return nil, errors.New("path is empty")
+// sqliteOptions holds the user connection tuning from [Config.SQLitePragmas]
+// and [Config.MaxOpenConns].
+type sqliteOptions struct {
+	pragmas      string // "PRAGMA k = v;" statements, applied after mddb's own
+	maxOpenConns int
+}
+
+// reservedPragmas break mddb's invariants if overridden.
+var reservedPragmas = map[string]string{
+	"user_version":    "stores the schema fingerprint",
+	"schema_version":  "is managed by SQLite",
+	"locking_mode":    "EXCLUSIVE blocks other processes from reading the index",
+	"query_only":      "commits must write the index",
+	"writable_schema": "can corrupt the index",
+}
+
+// newSqliteOptions validates pragma names and values. Values are restricted
+// to identifier-like tokens and numbers since they are spliced into SQL.
+func newSqliteOptions(pragmas map[string]string, maxOpenConns int) (sqliteOptions, error) {
+	if maxOpenConns < 0 {
+		return sqliteOptions{}, errors.New("Config.MaxOpenConns must be non-negative")
 	}
 
-	db, err := sql.Open("sqlite3", path)
-	if err != nil {
-		return nil, fmt.Errorf("sqlite: %w", err)
+	if maxOpenConns == 0 {
+		maxOpenConns = 1
 	}
 
-	// Ensure per-connection PRAGMAs apply consistently.
-	db.SetMaxOpenConns(1)
-	db.SetMaxIdleConns(1)
+	names := make([]string, 0, len(pragmas))
+	for name := range pragmas {
+		names = append(names, name)
+	}
 
-	err = db.PingContext(ctx)
-	if err != nil {
-		closeErr := db.Close()
-		if closeErr != nil {
-			closeErr = fmt.Errorf("sqlite: close: %w", closeErr)
+	slices.Sort(names)
+
+	var b strings.Builder
+
+	for _, name := range names {
+		value := pragmas[name]
+
+		if !isPragmaToken(name, false) {
+			return sqliteOptions{}, fmt.Errorf("Config.SQLitePragmas: invalid pragma name %q", name)
 		}
 
-		return nil, errors.Join(fmt.Errorf("sqlite: ping: %w", err), closeErr)
+		if reason, ok := reservedPragmas[strings.ToLower(name)]; ok {
+			return sqliteOptions{}, fmt.Errorf("Config.SQLitePragmas: %s cannot be set: it %s", name, reason)
+		}
+
+		if !isPragmaToken(value, true) {
+			return sqliteOptions{}, fmt.Errorf("Config.SQLitePragmas: invalid value %q for %s", value, name)
+		}
+
+		fmt.Fprintf(&b, "PRAGMA %s = %s;\n", name, value)
+	}
+
+	return sqliteOptions{pragmas: b.String(), maxOpenConns: maxOpenConns}, nil
+}
+
+// isPragmaToken reports whether s is a non-empty run of letters, digits and
+// underscores. Values may also start with '-' (e.g. cache_size = -20000).
+func isPragmaToken(s string, value bool) bool {
+	if value {
+		s = strings.TrimPrefix(s, "-")
+	}
+
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if r != '_' && (r < '0' || r > '9') && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sqliteConnector opens index connections through a driver whose
+// ConnectHook applies the pragmas, so every pooled connection gets them.
+type sqliteConnector struct {
+	driver *sqlite3.SQLiteDriver
+	dsn    string
+}
+
+func (c sqliteConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c sqliteConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// openSqlite opens the derived index database and applies mddb's pragmas
+// followed by the user's [sqliteOptions] on every connection.
+func openSqlite(ctx context.Context, path string, opts sqliteOptions) (*sql.DB, error) {
+	if path == "" {
+		return nil, errors.New("path is empty")
 	}
 
-	_, err = db.ExecContext(ctx, fmt.Sprintf(`
+	pragmas := fmt.Sprintf(`
 		PRAGMA busy_timeout = %d;
 		PRAGMA journal_mode = WAL;
 		PRAGMA synchronous = FULL;
 		PRAGMA mmap_size = 268435456;
 		PRAGMA cache_size = -20000;
 		PRAGMA temp_store = MEMORY;
-	`, sqliteBusyTimeoutMs))
+	`, sqliteBusyTimeoutMs) + opts.pragmas
+
+	db := sql.OpenDB(sqliteConnector{
+		driver: &sqlite3.SQLiteDriver{
+			ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+				_, err := conn.Exec(pragmas, nil)
+				if err != nil {
+					return fmt.Errorf("apply pragmas: %w", err)
+				}
+
+				return nil
+			},
+		},
+		dsn: path,
+	})
+
+	maxConns := max(opts.maxOpenConns, 1)
+	db.SetMaxOpenConns(maxConns)
+	db.SetMaxIdleConns(maxConns)
+
+	err := db.PingContext(ctx)
 	if err != nil {
 		closeErr := db.Close()
 		if closeErr != nil {
 			closeErr = fmt.Errorf("sqlite: close: %w", closeErr)
 		}
 
-		return nil, errors.Join(fmt.Errorf("sqlite: apply pragmas: %w", err), closeErr)
+		return nil, errors.Join(fmt.Errorf("sqlite: ping: %w", err), closeErr)
 	}
 
 	return db, nil
@@ -116,6 +116,321 @@ func Test_Open_Rebuilds_Index_When_Schema_Version_Mismatches(t *testing.T) {
 	}
 }
 
+func Test_NeedsReindex_Reports_Mismatch_When_DeferReindex(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	doc := newTestDoc(t, "Test Doc")
+	writeTestDocFile(t, dir, doc)
+
+	db := openIndex(t, dir)
+
+	_, err := db.Exec("PRAGMA user_version = 999")
+	if err != nil {
+		_ = db.Close()
+
+		t.Fatalf("set user_version: %v", err)
+	}
+
+	_ = db.Close()
+
+	s := openTestStore(t, dir, withTestDeferReindex())
+
+	defer func() { _ = s.Close() }()
+
+	needs, err := s.NeedsReindex(t.Context())
+	if err != nil {
+		t.Fatalf("needs reindex: %v", err)
+	}
+
+	if !needs {
+		t.Fatal("NeedsReindex = false, want true before reindex")
+	}
+
+	_, err = s.Reindex(t.Context())
+	if err != nil {
+		t.Fatalf("reindex: %v", err)
+	}
+
+	needs, err = s.NeedsReindex(t.Context())
+	if err != nil {
+		t.Fatalf("needs reindex: %v", err)
+	}
+
+	if needs {
+		t.Fatal("NeedsReindex = true, want false after reindex")
+	}
+
+	db = openIndex(t, dir)
+
+	defer func() { _ = db.Close() }()
+
+	version, err := userVersion(t.Context(), db)
+	if err != nil {
+		t.Fatalf("user_version: %v", err)
+	}
+
+	if uint64(version) != s.SchemaFingerprint() {
+		t.Fatalf("user_version = %d, want SchemaFingerprint %d", version, s.SchemaFingerprint())
+	}
+}
+
+func Test_Begin_Returns_ErrNeedsReindex_When_Reindex_Deferred(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	writeTestDocFile(t, dir, newTestDoc(t, "Test Doc"))
+
+	db := openIndex(t, dir)
+
+	_, err := db.Exec("PRAGMA user_version = 999")
+	if err != nil {
+		_ = db.Close()
+
+		t.Fatalf("set user_version: %v", err)
+	}
+
+	_ = db.Close()
+
+	s := openTestStore(t, dir, withTestDeferReindex())
+
+	defer func() { _ = s.Close() }()
+
+	_, err = s.Begin(t.Context())
+	if !errors.Is(err, mddb.ErrNeedsReindex) {
+		t.Fatalf("begin: got %v, want ErrNeedsReindex", err)
+	}
+
+	_, err = s.ReindexIncremental(t.Context())
+	if !errors.Is(err, mddb.ErrNeedsReindex) {
+		t.Fatalf("reindex incremental: got %v, want ErrNeedsReindex", err)
+	}
+
+	_, err = s.Reindex(t.Context())
+	if err != nil {
+		t.Fatalf("reindex: %v", err)
+	}
+
+	createTestDoc(t.Context(), t, s, newTestDoc(t, "After Reindex"))
+}
+
+func Test_Open_Uses_IndexPath_When_Configured(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	indexPath := filepath.Join(t.TempDir(), "cache", "docs.sqlite")
+
+	s := openTestStore(t, dir, withTestIndexPath(indexPath))
+
+	doc := createTestDoc(t.Context(), t, s, newTestDoc(t, "Indexed Elsewhere"))
+
+	_, err := os.Stat(indexPath)
+	if err != nil {
+		t.Fatalf("stat index: %v", err)
+	}
+
+	_, err = os.Stat(filepath.Join(dir, ".mddb", "index.sqlite"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("default index should not exist, err = %v", err)
+	}
+
+	_, err = os.Stat(filepath.Join(dir, ".mddb", "wal"))
+	if err != nil {
+		t.Fatalf("stat wal: %v", err)
+	}
+
+	count, err := s.Reindex(t.Context())
+	if err != nil {
+		t.Fatalf("reindex: %v", err)
+	}
+
+	if count != 1 {
+		t.Fatalf("reindex count = %d, want 1", count)
+	}
+
+	_ = s.Close()
+
+	// Drop the index entirely; reopening rebuilds it at the same place.
+	err = os.Remove(indexPath)
+	if err != nil {
+		t.Fatalf("remove index: %v", err)
+	}
+
+	s = openTestStore(t, dir, withTestIndexPath(indexPath))
+
+	defer func() { _ = s.Close() }()
+
+	got, err := s.Get(t.Context(), doc.DocID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if got.ID() != doc.DocID {
+		t.Fatalf("id = %s, want %s", got.ID(), doc.DocID)
+	}
+}
+
+func Test_Open_Applies_SQLitePragmas_When_Configured(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig(t.TempDir())
+	cfg.SQLitePragmas = map[string]string{"cache_size": "-4000"}
+	cfg.MaxOpenConns = 4
+
+	s, err := mddb.Open(t.Context(), cfg)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	defer func() { _ = s.Close() }()
+
+	type pragmaState struct {
+		cacheSize    int
+		maxOpenConns int
+	}
+
+	got, err := mddb.Query(t.Context(), s, func(db *sql.DB) (pragmaState, error) {
+		var state pragmaState
+
+		scanErr := db.QueryRow("PRAGMA cache_size").Scan(&state.cacheSize)
+		state.maxOpenConns = db.Stats().MaxOpenConnections
+
+		return state, scanErr
+	})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+
+	if got.cacheSize != -4000 {
+		t.Fatalf("cache_size = %d, want -4000", got.cacheSize)
+	}
+
+	if got.maxOpenConns != 4 {
+		t.Fatalf("max open conns = %d, want 4", got.maxOpenConns)
+	}
+}
+
+func Test_Open_Returns_Error_When_SQLitePragma_Is_Reserved_Or_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		pragmas map[string]string
+		want    string
+	}{
+		{"reserved", map[string]string{"user_version": "1"}, "user_version cannot be set"},
+		{"locking mode", map[string]string{"locking_mode": "EXCLUSIVE"}, "locking_mode cannot be set"},
+		{"bad name", map[string]string{"cache_size; DROP TABLE docs": "1"}, "invalid pragma name"},
+		{"bad value", map[string]string{"cache_size": "1; DROP TABLE docs"}, "invalid value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := testConfig(t.TempDir())
+			cfg.SQLitePragmas = tt.pragmas
+
+			s, err := mddb.Open(t.Context(), cfg)
+			if err == nil {
+				_ = s.Close()
+
+				t.Fatal("open: expected error")
+			}
+
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("open: got %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Checkpoint_Truncates_SQLite_WAL_And_Shrinks_Index_When_Vacuum(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	s := openTestStore(t, dir)
+
+	defer func() { _ = s.Close() }()
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	var docs []*TestDoc
+
+	for range 200 {
+		doc := newTestDoc(t, "Doc")
+		doc.DocBody = strings.Repeat("body ", 200)
+
+		_, err = tx.Create(doc)
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+
+		docs = append(docs, doc)
+	}
+
+	err = tx.Commit(t.Context())
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	err = s.Checkpoint(t.Context(), mddb.CheckpointOptions{})
+	if err != nil {
+		t.Fatalf("checkpoint: %v", err)
+	}
+
+	indexPath := filepath.Join(dir, ".mddb", "index.sqlite")
+	full := fileSize(t, indexPath)
+
+	if size := fileSize(t, indexPath+"-wal"); size != 0 {
+		t.Fatalf("sqlite wal size = %d after checkpoint, want 0", size)
+	}
+
+	tx, err = s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	for _, doc := range docs[1:] {
+		err = tx.Delete(doc.DocID)
+		if err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+	}
+
+	err = tx.Commit(t.Context())
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	err = s.Checkpoint(t.Context(), mddb.CheckpointOptions{Vacuum: true})
+	if err != nil {
+		t.Fatalf("checkpoint with vacuum: %v", err)
+	}
+
+	if size := fileSize(t, indexPath); size >= full {
+		t.Fatalf("index size = %d after vacuum, want < %d", size, full)
+	}
+
+	if size := fileSize(t, indexPath+"-wal"); size != 0 {
+		t.Fatalf("sqlite wal size = %d after vacuum, want 0", size)
+	}
+
+	got, err := s.Get(t.Context(), docs[0].DocID)
+	if err != nil {
+		t.Fatalf("get after vacuum: %v", err)
+	}
+
+	if got.DocTitle != "Doc" {
+		t.Fatalf("title = %q, want Doc", got.DocTitle)
+	}
+}
+
 func Test_Close_Returns_Nil_When_Called_Multiple_Times(t *testing.T) {
 	t.Parallel()
 
@@ -231,6 +546,53 @@ func Test_Get_Returns_Error_When_ID_Not_Found(t *testing.T) {
 	}
 }
 
+func Test_GetRaw_Returns_Parsed_File_When_Doc_Exists(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s := openTestStore(t, dir)
+
+	defer func() { _ = s.Close() }()
+
+	doc := newTestDoc(t, "Raw Doc")
+	doc.DocStatus = "closed"
+	doc.DocBody = "Some body"
+	createTestDoc(t.Context(), t, s, doc)
+
+	raw, err := s.GetRaw(t.Context(), doc.DocID)
+	if err != nil {
+		t.Fatalf("get raw: %v", err)
+	}
+
+	if string(raw.ID) != doc.DocID || string(raw.Title) != "Raw Doc" || string(raw.RelPath) != doc.DocPath {
+		t.Fatalf("raw = id %q title %q path %q, want %q %q %q", raw.ID, raw.Title, raw.RelPath, doc.DocID, "Raw Doc", doc.DocPath)
+	}
+
+	if !strings.Contains(string(raw.Body), "Some body") {
+		t.Fatalf("body = %q, want it to contain %q", raw.Body, "Some body")
+	}
+
+	status, ok := raw.Frontmatter.GetString([]byte("status"))
+	if !ok || status != "closed" {
+		t.Fatalf("status = %q (ok=%v), want closed", status, ok)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, doc.DocPath))
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+
+	if raw.MtimeNS != info.ModTime().UnixNano() || raw.SizeBytes != info.Size() {
+		t.Fatalf("mtime/size = %d/%d, want %d/%d", raw.MtimeNS, raw.SizeBytes, info.ModTime().UnixNano(), info.Size())
+	}
+
+	_, err = s.GetRaw(t.Context(), "nonexistent-id")
+	if !errors.Is(err, mddb.ErrNotFound) {
+		t.Fatalf("get raw missing: got %v, want ErrNotFound", err)
+	}
+}
+
 func Test_Get_Recovers_WAL_When_WAL_Appears_After_Open(t *testing.T) {
 	t.Parallel()
 
@@ -289,3 +651,14 @@ func tableExists(t *testing.T, db *sql.DB, name string) bool {
 
 	return count > 0
 }
+
+func fileSize(t *testing.T, path string) int64 {
+	t.Helper()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat %s: %v", path, err)
+	}
+
+	return info.Size()
+}
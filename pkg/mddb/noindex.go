@@ -0,0 +1,124 @@
+package mddb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/calvinalkan/fileproc"
+)
+
+// ErrNoIndex indicates an index operation on a store opened with [IndexNone].
+var ErrNoIndex = errors.New("store has no index (Config.IndexMode is IndexNone)")
+
+// getByPrefixLimit is the maximum number of rows [MDDB.GetByPrefix] returns.
+const getByPrefixLimit = 50
+
+// hasIndex reports whether the store maintains a SQLite index.
+func (mddb *MDDB[T]) hasIndex() bool {
+	return mddb.cfg.IndexMode != IndexNone
+}
+
+// validateNoIndexConfig rejects index settings that [IndexNone] would
+// silently ignore.
+func validateNoIndexConfig[T Document](cfg *Config[T]) error {
+	set := []struct {
+		name string
+		ok   bool
+	}{
+		{"SQLSchema", cfg.SQLSchema != nil},
+		{"SQLColumnValues", cfg.SQLColumnValues != nil},
+		{"IndexPath", cfg.IndexPath != ""},
+		{"SQLitePragmas", len(cfg.SQLitePragmas) > 0},
+		{"AfterCreate", cfg.AfterCreate != nil},
+		{"AfterUpdate", cfg.AfterUpdate != nil},
+		{"AfterDelete", cfg.AfterDelete != nil},
+		{"AfterRecreateSchema", cfg.AfterRecreateSchema != nil},
+		{"AfterIndexBatch", cfg.AfterIndexBatch != nil},
+	}
+
+	for _, s := range set {
+		if s.ok {
+			return fmt.Errorf("Config.%s requires an index (Config.IndexMode is IndexNone)", s.name)
+		}
+	}
+
+	return nil
+}
+
+// getByPrefixFiles implements [MDDB.GetByPrefix] for [IndexNone] by parsing
+// every document file. With exact, only rows whose ID or short_id equals
+// prefix (ignoring ASCII case) match. Must be called with the read lock held.
+func (mddb *MDDB[T]) getByPrefixFiles(ctx context.Context, prefix string, exact bool) ([]GetPrefixRow, error) {
+	var (
+		mu      sync.Mutex
+		results []GetPrefixRow
+	)
+
+	_, errs := fileproc.Process(ctx, mddb.dataDir, func(f *fileproc.File, _ *fileproc.FileWorker) (*struct{}, error) {
+		relPath := f.RelPath()
+		if isInternalPath(relPath) {
+			return nil, fileproc.ErrSkip
+		}
+
+		stat, err := f.Stat()
+		if err != nil {
+			return nil, fmt.Errorf("fs: %w", err)
+		}
+
+		data, err := f.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("fs: %w", err)
+		}
+
+		parsed, err := mddb.parseIndexable(relPath, data, stat.ModTime, stat.Size, "")
+		if err != nil {
+			return nil, fmt.Errorf("parsing document: %w", err)
+		}
+
+		matches := hasPrefixFold
+		if exact {
+			matches = equalFold
+		}
+
+		if !matches(string(parsed.ID), prefix) && !matches(string(parsed.ShortID), prefix) {
+			return nil, fileproc.ErrSkip
+		}
+
+		row := GetPrefixRow{
+			ID:        string(parsed.ID),
+			ShortID:   string(parsed.ShortID),
+			Path:      string(relPath),
+			MtimeNS:   stat.ModTime,
+			SizeBytes: stat.Size,
+			Title:     string(parsed.Title),
+		}
+
+		mu.Lock()
+		results = append(results, row)
+		mu.Unlock()
+
+		return nil, fileproc.ErrSkip
+	}, fileproc.WithRecursive(), fileproc.WithSuffix(".md"))
+
+	err := ctx.Err()
+	if err != nil {
+		return nil, fmt.Errorf("canceled: %w", context.Cause(ctx))
+	}
+
+	err = toIndexScanError(errs)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(results, func(a, b GetPrefixRow) int { return strings.Compare(a.ID, b.ID) })
+
+	if len(results) > getByPrefixLimit {
+		results = results[:getByPrefixLimit]
+	}
+
+	return results, nil
+}
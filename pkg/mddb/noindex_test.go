@@ -0,0 +1,146 @@
+package mddb_test
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/calvinalkan/agent-task/pkg/mddb"
+)
+
+func Test_IndexNone_Stores_And_Reads_Docs_Without_SQLite_When_Opened(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	s := openTestStore(t, dir, withTestNoIndex())
+
+	defer func() { _ = s.Close() }()
+
+	first := createTestDoc(t.Context(), t, s, newTestDoc(t, "First"))
+	second := createTestDoc(t.Context(), t, s, newTestDoc(t, "Second"))
+
+	got, err := s.Get(t.Context(), first.DocID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if got.DocTitle != "First" {
+		t.Fatalf("title = %q, want First", got.DocTitle)
+	}
+
+	rows, err := s.GetByPrefix(t.Context(), second.DocShort[:8])
+	if err != nil {
+		t.Fatalf("get by prefix: %v", err)
+	}
+
+	if len(rows) != 1 || rows[0].ID != second.DocID || rows[0].Path != second.DocPath {
+		t.Fatalf("rows = %+v, want only %s", rows, second.DocID)
+	}
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	_, err = tx.Create(first)
+	if !errors.Is(err, mddb.ErrAlreadyExists) {
+		t.Fatalf("create existing: got %v, want ErrAlreadyExists", err)
+	}
+
+	err = tx.Delete(first.DocID)
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	err = tx.Commit(t.Context())
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	_, err = s.Get(t.Context(), first.DocID)
+	if !errors.Is(err, mddb.ErrNotFound) {
+		t.Fatalf("get deleted: got %v, want ErrNotFound", err)
+	}
+
+	_, err = os.Stat(filepath.Join(dir, ".mddb", "index.sqlite"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("index file exists: err=%v", err)
+	}
+}
+
+func Test_IndexNone_Returns_ErrNoIndex_When_Querying(t *testing.T) {
+	t.Parallel()
+
+	s := openTestStore(t, t.TempDir(), withTestNoIndex())
+
+	defer func() { _ = s.Close() }()
+
+	_, err := mddb.Query(t.Context(), s, func(*sql.DB) (int, error) { return 0, nil })
+	if !errors.Is(err, mddb.ErrNoIndex) {
+		t.Fatalf("query: got %v, want ErrNoIndex", err)
+	}
+
+	_, err = s.Reindex(t.Context())
+	if !errors.Is(err, mddb.ErrNoIndex) {
+		t.Fatalf("reindex: got %v, want ErrNoIndex", err)
+	}
+
+	err = s.Checkpoint(t.Context(), mddb.CheckpointOptions{})
+	if !errors.Is(err, mddb.ErrNoIndex) {
+		t.Fatalf("checkpoint: got %v, want ErrNoIndex", err)
+	}
+
+	needs, err := s.NeedsReindex(t.Context())
+	if err != nil || needs {
+		t.Fatalf("needs reindex = %v, %v; want false, nil", needs, err)
+	}
+}
+
+func Test_IndexNone_Replays_WAL_When_Opened(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s := openTestStore(t, dir, withTestNoIndex())
+	_ = s.Close()
+
+	doc := newTestDoc(t, "WAL Doc")
+	walPath := filepath.Join(dir, ".mddb", "wal")
+	writeWalFile(t, walPath, []walRecord{makeWalPutRecord(doc)})
+
+	s = openTestStore(t, dir, withTestNoIndex())
+
+	defer func() { _ = s.Close() }()
+
+	got, err := s.Get(t.Context(), doc.DocID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if got.DocTitle != "WAL Doc" {
+		t.Fatalf("title = %q, want WAL Doc", got.DocTitle)
+	}
+
+	info, err := os.Stat(walPath)
+	if err != nil {
+		t.Fatalf("stat wal: %v", err)
+	}
+
+	if info.Size() != 0 {
+		t.Fatalf("wal size = %d, want 0", info.Size())
+	}
+}
+
+func Test_Open_Rejects_Index_Settings_When_IndexNone(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig(t.TempDir())
+	cfg.IndexMode = mddb.IndexNone
+
+	_, err := mddb.Open(t.Context(), cfg)
+	if err == nil {
+		t.Fatal("open: want error for SQLSchema with IndexNone")
+	}
+}
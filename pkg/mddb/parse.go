@@ -16,6 +16,9 @@ import (
 //
 // Validates:
 //   - Frontmatter structure and required fields (id, title)
+//   - File matches its checksum (content_sha256), if it has one
+//   - ID accepted by [Config.ValidateID]
+//   - Referenced body (body_sha256) exists and matches its hash
 //   - Derived path matches actual file path (prevents orphaned files)
 //   - ShortID derivation succeeds
 func (mddb *MDDB[T]) parseIndexable(fsRelPath []byte, content []byte, mtimeNS int64, sizeBytes int64, expectedID string) (IndexableDocument, error) {
@@ -24,17 +27,36 @@ func (mddb *MDDB[T]) parseIndexable(fsRelPath []byte, content []byte, mtimeNS in
 		return IndexableDocument{}, fmt.Errorf("frontmatter: %w", err)
 	}
 
+	// Verify checksums regardless of Config.Checksums, so turning it off
+	// keeps checking the files written while it was on.
+	if sum, ok := fm.GetBytes(frontmatterKeyContentSHA256); ok {
+		err = verifyContentSHA256(content, sum)
+		if err != nil {
+			return IndexableDocument{}, err
+		}
+	}
+
+	// Resolve deduplicated bodies regardless of Config.DedupBodies, so
+	// turning it off keeps existing documents readable.
+	if hash, ok := fm.GetBytes(frontmatterKeyBodySHA256); ok {
+		tail, err = mddb.readBody(hash)
+		if err != nil {
+			return IndexableDocument{}, err
+		}
+	}
+
 	idBytes, ok := fm.GetBytes(frontmatterKeyID)
 	if !ok {
 		return IndexableDocument{}, errors.New("frontmatter: missing id field")
 	}
 
-	if len(idBytes) == 0 {
-		return IndexableDocument{}, fmt.Errorf("frontmatter: %w", errEmptyID)
-	}
-
 	id := string(idBytes)
 
+	err = mddb.validateID(id)
+	if err != nil {
+		return IndexableDocument{}, fmt.Errorf("frontmatter: %w", err)
+	}
+
 	if expectedID != "" && id != expectedID {
 		return IndexableDocument{}, fmt.Errorf("frontmatter: id mismatch: expected %q, got %q", expectedID, id)
 	}
@@ -0,0 +1,323 @@
+package mddb
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// prefixIndex is an in-memory copy of the id, short_id and listing columns,
+// kept sorted so [MDDB.GetByPrefix] is a binary search instead of a LIKE
+// scan over the whole table.
+//
+// It is built lazily from SQLite on the first lookup and updated in place by
+// commits from this store. Commits from other processes are detected with
+// SQLite's PRAGMA data_version, which changes whenever another connection
+// commits; a changed version triggers a rebuild on the next lookup.
+//
+// data_version is per connection, so the index keeps its own single
+// connection (versionDB) that never writes.
+type prefixIndex struct {
+	mu sync.Mutex
+
+	versionDB *sql.DB
+	version   int64
+	built     bool
+
+	rows    map[string]*GetPrefixRow
+	byID    []prefixEntry // sorted by (key, row.ID), key = foldASCII(row.ID)
+	byShort []prefixEntry // sorted by (key, row.ID), key = foldASCII(row.ShortID)
+}
+
+// prefixEntry is one sort key of a row in [prefixIndex].
+type prefixEntry struct {
+	key string
+	row *GetPrefixRow
+}
+
+func comparePrefixEntry(a, b prefixEntry) int {
+	return cmp.Or(strings.Compare(a.key, b.key), strings.Compare(a.row.ID, b.row.ID))
+}
+
+// getByPrefixIndexed implements [MDDB.GetByPrefix] on the in-memory index,
+// (re)building it first if needed. See [prefixIndex.match] for exact.
+// Must be called with the read lock held.
+func (mddb *MDDB[T]) getByPrefixIndexed(ctx context.Context, prefix string, exact bool) ([]GetPrefixRow, error) {
+	idx := &mddb.prefixIdx
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	err := mddb.ensurePrefixIndexLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return idx.match(prefix, exact), nil
+}
+
+// ensurePrefixIndexLocked rebuilds the index if it was never built or
+// another connection committed since. Must be called with idx.mu held.
+func (mddb *MDDB[T]) ensurePrefixIndexLocked(ctx context.Context) error {
+	idx := &mddb.prefixIdx
+
+	if idx.versionDB == nil {
+		db, err := openSqlite(ctx, mddb.indexPath, sqliteOptions{maxOpenConns: 1})
+		if err != nil {
+			return fmt.Errorf("opening prefix index connection: %w", err)
+		}
+
+		idx.versionDB = db
+		idx.built = false
+	}
+
+	version, err := queryDataVersion(ctx, idx.versionDB)
+	if err != nil {
+		return err
+	}
+
+	if idx.built && version == idx.version {
+		return nil
+	}
+
+	rows, err := mddb.loadPrefixRows(ctx)
+	if err != nil {
+		return err
+	}
+
+	idx.build(rows)
+	idx.version = version
+	idx.built = true
+
+	return nil
+}
+
+// loadPrefixRows reads the [GetPrefixRow] columns of every indexed document.
+func (mddb *MDDB[T]) loadPrefixRows(ctx context.Context) ([]GetPrefixRow, error) {
+	rows, err := mddb.sql.QueryContext(ctx, "SELECT id, short_id, path, mtime_ns, size_bytes, title FROM "+mddb.schema.tableName)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: %w", err)
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	var results []GetPrefixRow
+
+	for rows.Next() {
+		var row GetPrefixRow
+
+		scanErr := rows.Scan(&row.ID, &row.ShortID, &row.Path, &row.MtimeNS, &row.SizeBytes, &row.Title)
+		if scanErr != nil {
+			return nil, fmt.Errorf("sqlite: %w", scanErr)
+		}
+
+		results = append(results, row)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: %w", err)
+	}
+
+	return results, nil
+}
+
+// updatePrefixIndex applies committed index rows and deletes to a built
+// index and records the post-commit data_version, so the next lookup does
+// not rebuild. Must be called with the write lock held, right after the
+// SQLite commit. If the version can't be read, the index is rebuilt later.
+func (mddb *MDDB[T]) updatePrefixIndex(ctx context.Context, puts []IndexRow, deletes []string) {
+	idx := &mddb.prefixIdx
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if !idx.built {
+		return
+	}
+
+	for _, id := range deletes {
+		idx.remove(id)
+	}
+
+	for i := range puts {
+		idx.put(GetPrefixRow{
+			ID:        puts[i].ID,
+			ShortID:   puts[i].ShortID,
+			Path:      puts[i].RelPath,
+			MtimeNS:   puts[i].MtimeNS,
+			SizeBytes: puts[i].SizeBytes,
+			Title:     puts[i].Title,
+		})
+	}
+
+	version, err := queryDataVersion(ctx, idx.versionDB)
+	if err != nil {
+		idx.built = false
+
+		return
+	}
+
+	idx.version = version
+}
+
+// dropPrefixIndex discards the index and its connection. Used when the
+// index file is rebuilt or replaced, and on [MDDB.Close].
+func (mddb *MDDB[T]) dropPrefixIndex() error {
+	idx := &mddb.prefixIdx
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.built = false
+	idx.rows = nil
+	idx.byID = nil
+	idx.byShort = nil
+
+	if idx.versionDB == nil {
+		return nil
+	}
+
+	err := idx.versionDB.Close()
+	idx.versionDB = nil
+
+	if err != nil {
+		return fmt.Errorf("sqlite: close prefix index connection: %w", err)
+	}
+
+	return nil
+}
+
+func (idx *prefixIndex) build(rows []GetPrefixRow) {
+	idx.rows = make(map[string]*GetPrefixRow, len(rows))
+	idx.byID = make([]prefixEntry, 0, len(rows))
+	idx.byShort = make([]prefixEntry, 0, len(rows))
+
+	for i := range rows {
+		row := &rows[i]
+		idx.rows[row.ID] = row
+		idx.byID = append(idx.byID, prefixEntry{key: foldASCII(row.ID), row: row})
+		idx.byShort = append(idx.byShort, prefixEntry{key: foldASCII(row.ShortID), row: row})
+	}
+
+	slices.SortFunc(idx.byID, comparePrefixEntry)
+	slices.SortFunc(idx.byShort, comparePrefixEntry)
+}
+
+func (idx *prefixIndex) put(row GetPrefixRow) {
+	idx.remove(row.ID)
+
+	stored := &row
+	idx.rows[row.ID] = stored
+	idx.byID = insertPrefixEntry(idx.byID, prefixEntry{key: foldASCII(row.ID), row: stored})
+	idx.byShort = insertPrefixEntry(idx.byShort, prefixEntry{key: foldASCII(row.ShortID), row: stored})
+}
+
+func (idx *prefixIndex) remove(id string) {
+	row, ok := idx.rows[id]
+	if !ok {
+		return
+	}
+
+	delete(idx.rows, id)
+	idx.byID = removePrefixEntry(idx.byID, prefixEntry{key: foldASCII(row.ID), row: row})
+	idx.byShort = removePrefixEntry(idx.byShort, prefixEntry{key: foldASCII(row.ShortID), row: row})
+}
+
+// match returns up to getByPrefixLimit rows whose ID or ShortID starts with
+// prefix, ignoring ASCII case like SQLite's LIKE, ordered by ID. With exact,
+// the ID or ShortID must equal prefix instead.
+func (idx *prefixIndex) match(prefix string, exact bool) []GetPrefixRow {
+	key := foldASCII(prefix)
+
+	var (
+		seen    = make(map[string]struct{})
+		results []GetPrefixRow
+	)
+
+	for _, entries := range [][]prefixEntry{idx.byID, idx.byShort} {
+		start, _ := slices.BinarySearchFunc(entries, key, func(e prefixEntry, k string) int {
+			return strings.Compare(e.key, k)
+		})
+
+		for _, e := range entries[start:] {
+			if !strings.HasPrefix(e.key, key) || (exact && e.key != key) {
+				break
+			}
+
+			if _, dup := seen[e.row.ID]; dup {
+				continue
+			}
+
+			seen[e.row.ID] = struct{}{}
+			results = append(results, *e.row)
+		}
+	}
+
+	slices.SortFunc(results, func(a, b GetPrefixRow) int { return strings.Compare(a.ID, b.ID) })
+
+	if len(results) > getByPrefixLimit {
+		results = results[:getByPrefixLimit]
+	}
+
+	return results
+}
+
+func insertPrefixEntry(entries []prefixEntry, e prefixEntry) []prefixEntry {
+	i, _ := slices.BinarySearchFunc(entries, e, comparePrefixEntry)
+
+	return slices.Insert(entries, i, e)
+}
+
+func removePrefixEntry(entries []prefixEntry, e prefixEntry) []prefixEntry {
+	i, found := slices.BinarySearchFunc(entries, e, comparePrefixEntry)
+	if !found {
+		return entries
+	}
+
+	return slices.Delete(entries, i, i+1)
+}
+
+// hasPrefixFold reports whether s starts with prefix, ignoring ASCII case.
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && foldASCII(s[:len(prefix)]) == foldASCII(prefix)
+}
+
+// equalFold reports whether s equals t, ignoring ASCII case.
+func equalFold(s, t string) bool {
+	return len(s) == len(t) && foldASCII(s) == foldASCII(t)
+}
+
+// foldASCII lowercases ASCII letters only, matching SQLite's default LIKE.
+func foldASCII(s string) string {
+	for i := range len(s) {
+		if 'A' <= s[i] && s[i] <= 'Z' {
+			b := []byte(s)
+			for j := i; j < len(b); j++ {
+				if 'A' <= b[j] && b[j] <= 'Z' {
+					b[j] += 'a' - 'A'
+				}
+			}
+
+			return string(b)
+		}
+	}
+
+	return s
+}
+
+// queryDataVersion reads SQLite's PRAGMA data_version for db's connection.
+func queryDataVersion(ctx context.Context, db *sql.DB) (int64, error) {
+	var version int64
+
+	err := db.QueryRowContext(ctx, "PRAGMA data_version").Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("sqlite: data_version: %w", err)
+	}
+
+	return version, nil
+}
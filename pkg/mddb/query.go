@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"iter"
 	"os"
 	"path/filepath"
 	"strings"
@@ -13,6 +14,31 @@ import (
 // ErrNotFound indicates the requested document does not exist.
 var ErrNotFound = errors.New("not found")
 
+// ErrAmbiguousPrefix indicates a prefix matched more than one document.
+// [MDDB.ResolvePrefix] returns it as [*AmbiguousPrefixError], which lists the
+// candidates.
+var ErrAmbiguousPrefix = errors.New("ambiguous prefix")
+
+// AmbiguousPrefixError is returned by [MDDB.ResolvePrefix] when a prefix
+// matches several documents. It matches [ErrAmbiguousPrefix] with [errors.Is].
+type AmbiguousPrefixError struct {
+	// Prefix is the prefix that was looked up.
+	Prefix string
+
+	// IDs are the matching document IDs ordered by ID, at most 50.
+	IDs []string
+}
+
+// Error formats as "ambiguous prefix "<prefix>": matches <id>, <id>, ...".
+func (e *AmbiguousPrefixError) Error() string {
+	return fmt.Sprintf("%s %q: matches %s", ErrAmbiguousPrefix, e.Prefix, strings.Join(e.IDs, ", "))
+}
+
+// Unwrap returns [ErrAmbiguousPrefix].
+func (e *AmbiguousPrefixError) Unwrap() error {
+	return ErrAmbiguousPrefix
+}
+
 // GetPrefixRow contains the base fields returned by [MDDB.GetByPrefix].
 // These correspond to just the required SQLite columns that all documents must have.
 // Use [MDDB.Get] to retrieve the full document with body and custom fields.
@@ -41,8 +67,8 @@ type GetPrefixRow struct {
 // Acquires shared lock, replays pending WAL if needed, then calls fn with
 // the SQLite *sql.DB. Multiple Query calls run concurrently.
 //
-// Returns [ErrClosed] if store is closed. Also returns lock timeout,
-// WAL replay failures, or errors from fn.
+// Returns [ErrClosed] if store is closed and [ErrNoIndex] with [IndexNone].
+// Also returns lock timeout, WAL replay failures, or errors from fn.
 func Query[T Document, R any](ctx context.Context, s *MDDB[T], fn func(db *sql.DB) (R, error)) (R, error) {
 	var zero R
 
@@ -54,6 +80,10 @@ func Query[T Document, R any](ctx context.Context, s *MDDB[T], fn func(db *sql.D
 		return zero, ErrClosed
 	}
 
+	if !s.hasIndex() {
+		return zero, ErrNoIndex
+	}
+
 	release, err := s.acquireReadLock(ctx)
 	if err != nil {
 		return zero, fmt.Errorf("acquiring read lock: %w", err)
@@ -64,13 +94,187 @@ func Query[T Document, R any](ctx context.Context, s *MDDB[T], fn func(db *sql.D
 	return fn(s.sql)
 }
 
+// QueryScan runs a SQL query with a read lock held and maps each row with scan.
+//
+// Like [Query], but owns the QueryContext/Close/Next/Err loop so callers only
+// write the per-row Scan. Stops at the first error from scan and returns it
+// unchanged. An empty result is a nil slice.
+//
+// Returns [ErrClosed] if store is closed and [ErrNoIndex] with [IndexNone].
+// Also returns lock timeout, WAL replay failures, and SQLite errors from the
+// query or row iteration.
+func QueryScan[T Document, R any](ctx context.Context, s *MDDB[T], query string, scan func(*sql.Rows) (R, error), args ...any) ([]R, error) {
+	if scan == nil {
+		return nil, errors.New("scan is nil")
+	}
+
+	return Query(ctx, s, func(db *sql.DB) ([]R, error) {
+		rows, err := db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("sqlite: %w", err)
+		}
+
+		defer func() { _ = rows.Close() }()
+
+		var results []R
+
+		for rows.Next() {
+			row, scanErr := scan(rows)
+			if scanErr != nil {
+				return nil, scanErr
+			}
+
+			results = append(results, row)
+		}
+
+		err = rows.Err()
+		if err != nil {
+			return nil, fmt.Errorf("sqlite: %w", err)
+		}
+
+		return results, nil
+	})
+}
+
+// QuerySeq runs a SQL query like [QueryScan] but yields the mapped rows
+// lazily, for range-over-func:
+//
+//	for title, err := range mddb.QuerySeq(ctx, store, "SELECT title FROM docs", scanTitle) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(title)
+//	}
+//
+// The read lock is held and the rows stay open until iteration ends. The open
+// rows hold the index's only connection, so the loop body must not call any
+// method of the store, reads included: it would block until ctx is done, or
+// forever without a deadline. Collect what the body needs and act on it after
+// the loop, or use [QueryScan]. Breaking out of the loop closes the rows and
+// releases the lock. Errors, including from scan
+// and from a canceled ctx (checked before each row), are yielded once as the
+// last element.
+//
+// Yields [ErrClosed] if store is closed and [ErrNoIndex] with [IndexNone].
+// Also yields lock timeout, WAL replay failures, and SQLite errors.
+func QuerySeq[T Document, R any](ctx context.Context, s *MDDB[T], query string, scan func(*sql.Rows) (R, error), args ...any) iter.Seq2[R, error] {
+	return func(yield func(R, error) bool) {
+		var zero R
+
+		if scan == nil {
+			yield(zero, errors.New("scan is nil"))
+
+			return
+		}
+
+		err := querySeq(ctx, s, query, scan, args, yield)
+		if err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// querySeq drives [QuerySeq]. Returns nil once yield asks to stop.
+func querySeq[T Document, R any](ctx context.Context, s *MDDB[T], query string, scan func(*sql.Rows) (R, error), args []any, yield func(R, error) bool) error {
+	_, err := Query(ctx, s, func(db *sql.DB) (struct{}, error) {
+		rows, err := db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return struct{}{}, fmt.Errorf("sqlite: %w", err)
+		}
+
+		defer func() { _ = rows.Close() }()
+
+		for rows.Next() {
+			ctxErr := ctx.Err()
+			if ctxErr != nil {
+				return struct{}{}, fmt.Errorf("canceled: %w", context.Cause(ctx))
+			}
+
+			row, scanErr := scan(rows)
+			if scanErr != nil {
+				return struct{}{}, scanErr
+			}
+
+			if !yield(row, nil) {
+				return struct{}{}, nil
+			}
+		}
+
+		err = rows.Err()
+		if err != nil {
+			return struct{}{}, fmt.Errorf("sqlite: %w", err)
+		}
+
+		return struct{}{}, nil
+	})
+
+	return err
+}
+
+// EachID calls fn with the ID, short_id and title of every indexed document,
+// ordered by ID, without reading any document files.
+//
+// The rows are read into memory first and fn runs after the read lock is
+// released, so fn may call any store method, including writes. Documents
+// committed meanwhile are not reflected. Stops at the first error from fn and
+// returns it unchanged.
+//
+// Returns [ErrClosed] if store is closed and [ErrNoIndex] with [IndexNone].
+// Also returns lock timeout, WAL replay failures, and SQLite errors.
+func (mddb *MDDB[T]) EachID(ctx context.Context, fn func(id, shortID, title string) error) error {
+	if fn == nil {
+		return errors.New("fn is nil")
+	}
+
+	type idRow struct{ id, shortID, title string }
+
+	rows, err := QueryScan(ctx, mddb, "SELECT id, short_id, title FROM "+mddb.schema.tableName+" ORDER BY id",
+		func(rows *sql.Rows) (idRow, error) {
+			var row idRow
+
+			err := rows.Scan(&row.id, &row.shortID, &row.title)
+			if err != nil {
+				return idRow{}, fmt.Errorf("sqlite: %w", err)
+			}
+
+			return row, nil
+		})
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		err = fn(row.id, row.shortID, row.title)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // GetByPrefix finds documents by short_id or ID prefix.
 //
 // Returns up to 50 [GetPrefixRow] matches ordered by ID. Use [MDDB.Get] for full
 // documents. Empty slice means no match; multiple results means ambiguous prefix.
+// Use [MDDB.ResolvePrefix] to get that as [ErrNotFound] / [ErrAmbiguousPrefix].
+// Matching ignores ASCII case.
+//
+// Lookups use an in-memory sorted copy of the IDs, built from SQLite on first
+// use and kept current by commits, so they cost O(log n + matches). A commit
+// from another process causes one rebuild on the next lookup.
+//
+// With [IndexNone], every document file is parsed; a file that fails to parse
+// fails the lookup with [*IndexScanError].
 //
 // Returns [ErrClosed] if store is closed.
 func (mddb *MDDB[T]) GetByPrefix(ctx context.Context, prefix string) ([]GetPrefixRow, error) {
+	return mddb.getByPrefix(ctx, prefix, false)
+}
+
+// getByPrefix implements [MDDB.GetByPrefix]. With exact, only documents whose
+// ID or short_id equals prefix, ignoring ASCII case, match.
+func (mddb *MDDB[T]) getByPrefix(ctx context.Context, prefix string, exact bool) ([]GetPrefixRow, error) {
 	if ctx == nil {
 		return nil, errors.New("context is nil")
 	}
@@ -90,56 +294,55 @@ func (mddb *MDDB[T]) GetByPrefix(ctx context.Context, prefix string) ([]GetPrefi
 
 	defer func() { _ = release() }()
 
-	query := "SELECT id, short_id, path, mtime_ns, size_bytes, title FROM " + mddb.schema.tableName +
-		" WHERE short_id LIKE ? ESCAPE '\\' OR id LIKE ? ESCAPE '\\' ORDER BY id LIMIT 50"
+	if !mddb.hasIndex() {
+		return mddb.getByPrefixFiles(ctx, prefix, exact)
+	}
 
-	pattern := escapeLike(prefix) + "%"
+	return mddb.getByPrefixIndexed(ctx, prefix, exact)
+}
 
-	rows, err := mddb.sql.QueryContext(ctx, query, pattern, pattern)
+// ResolvePrefix returns the single document whose ID or short_id starts with
+// prefix, as matched by [MDDB.GetByPrefix]. An exact ID or short_id match,
+// ignoring ASCII case like the prefix match, wins over longer matches, so a
+// full ID always resolves however many documents share its prefix.
+//
+// Returns [ErrNotFound] if nothing matches and [*AmbiguousPrefixError]
+// ([ErrAmbiguousPrefix]) if several documents match.
+// Returns [ErrClosed] if store is closed.
+func (mddb *MDDB[T]) ResolvePrefix(ctx context.Context, prefix string) (GetPrefixRow, error) {
+	// Look for exact matches separately: the prefix lookup stops at
+	// getByPrefixLimit rows and could miss them.
+	rows, err := mddb.getByPrefix(ctx, prefix, true)
 	if err != nil {
-		return nil, fmt.Errorf("sqlite: %w", err)
+		return GetPrefixRow{}, err
 	}
 
-	defer func() { _ = rows.Close() }()
-
-	var results []GetPrefixRow
-
-	for rows.Next() {
-		var (
-			idStr     string
-			shortID   string
-			path      string
-			mtimeNS   int64
-			sizeBytes int64
-			title     string
-		)
-
-		scanErr := rows.Scan(&idStr, &shortID, &path, &mtimeNS, &sizeBytes, &title)
-		if scanErr != nil {
-			return nil, fmt.Errorf("sqlite: %w", scanErr)
+	if len(rows) == 0 {
+		rows, err = mddb.getByPrefix(ctx, prefix, false)
+		if err != nil {
+			return GetPrefixRow{}, err
 		}
+	}
 
-		results = append(results, GetPrefixRow{
-			ID:        idStr,
-			ShortID:   shortID,
-			Path:      path,
-			MtimeNS:   mtimeNS,
-			SizeBytes: sizeBytes,
-			Title:     title,
-		})
+	switch len(rows) {
+	case 0:
+		return GetPrefixRow{}, fmt.Errorf("%w: no document matching %q", ErrNotFound, prefix)
+	case 1:
+		return rows[0], nil
 	}
 
-	err = rows.Err()
-	if err != nil {
-		return nil, fmt.Errorf("sqlite: %w", err)
+	ids := make([]string, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
 	}
 
-	return results, nil
+	return GetPrefixRow{}, &AmbiguousPrefixError{Prefix: prefix, IDs: ids}
 }
 
 // Get retrieves a document by full ID.
 //
-// Looks up path in SQLite, reads file, builds via [Config.DocumentFrom].
+// Looks up path in SQLite (or derives it via [Config.RelPathFromID] with
+// [IndexNone]), reads file, builds via [Config.DocumentFrom].
 // For prefix lookup, use [MDDB.GetByPrefix] first.
 //
 // Returns [ErrNotFound] if document doesn't exist or file is missing.
@@ -164,69 +367,142 @@ func (mddb *MDDB[T]) Get(ctx context.Context, id string) (*T, error) {
 
 	defer func() { _ = release() }()
 
-	// We need to find the path. Query SQLite for it.
+	path, err := mddb.lookupPath(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := mddb.readDocumentFile(id, path)
+	if err != nil {
+		return nil, withContext(fmt.Errorf("reading document: %w", err), id, path)
+	}
+
+	return doc, nil
+}
+
+// GetRaw retrieves a document by full ID as parsed from its file, before the
+// [Config.DocumentFrom] conversion.
+//
+// Useful for diff, verify, and migration tools that need the raw frontmatter
+// and body. Unlike the callback arguments elsewhere, the returned fields are
+// backed by a buffer owned by the caller and safe to retain.
+//
+// Returns [ErrNotFound] if document doesn't exist or file is missing.
+// Returns [ErrClosed] if mddb is closed.
+func (mddb *MDDB[T]) GetRaw(ctx context.Context, id string) (IndexableDocument, error) {
+	if ctx == nil {
+		return IndexableDocument{}, errors.New("context is nil")
+	}
+
+	if mddb == nil || mddb.closed.Load() {
+		return IndexableDocument{}, ErrClosed
+	}
+
+	if id == "" {
+		return IndexableDocument{}, errEmptyID
+	}
+
+	release, err := mddb.acquireReadLock(ctx)
+	if err != nil {
+		return IndexableDocument{}, fmt.Errorf("acquiring read lock: %w", err)
+	}
+
+	defer func() { _ = release() }()
+
+	path, err := mddb.lookupPath(ctx, id)
+	if err != nil {
+		return IndexableDocument{}, err
+	}
+
+	data, mtimeNS, sizeBytes, err := mddb.readFile(path)
+	if err != nil {
+		return IndexableDocument{}, withContext(fmt.Errorf("reading document: %w", err), id, path)
+	}
+
+	raw, err := mddb.parseIndexable([]byte(path), data, mtimeNS, sizeBytes, id)
+	if err != nil {
+		return IndexableDocument{}, withContext(fmt.Errorf("reading document: %w", err), id, path)
+	}
+
+	return raw, nil
+}
+
+// lookupPath returns the validated relative path of id from the index.
+// Without an index the path is derived from the ID; a missing file is
+// reported as [ErrNotFound] when it is read.
+func (mddb *MDDB[T]) lookupPath(ctx context.Context, id string) (string, error) {
+	if !mddb.hasIndex() {
+		path := mddb.cfg.RelPathFromID(id)
+		if path == "" {
+			return "", withContext(errEmptyPath, id, "")
+		}
+
+		err := mddb.validateRelPath(path)
+		if err != nil {
+			return "", withContext(fmt.Errorf("validating path: %w", err), id, path)
+		}
+
+		return path, nil
+	}
+
 	var path string
 
 	query := "SELECT path FROM " + mddb.schema.tableName + " WHERE id = ?"
 	row := mddb.sql.QueryRowContext(ctx, query, id)
 
-	err = row.Scan(&path)
+	err := row.Scan(&path)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, withContext(ErrNotFound, id, "")
+			return "", withContext(ErrNotFound, id, "")
 		}
 
-		return nil, withContext(fmt.Errorf("sqlite: %w", err), id, "")
+		return "", withContext(fmt.Errorf("sqlite: %w", err), id, "")
 	}
 
 	err = mddb.validateRelPath(path)
 	if err != nil {
-		return nil, withContext(fmt.Errorf("validating path: %w", err), id, path)
+		return "", withContext(fmt.Errorf("validating path: %w", err), id, path)
 	}
 
-	doc, err := mddb.readDocumentFile(id, path)
+	return path, nil
+}
+
+// readDocumentFile reads and parses a document from its path.
+func (mddb *MDDB[T]) readDocumentFile(expectedID string, relPath string) (*T, error) {
+	data, mtimeNS, sizeBytes, err := mddb.readFile(relPath)
 	if err != nil {
-		return nil, withContext(fmt.Errorf("reading document: %w", err), id, path)
+		return nil, err
+	}
+
+	doc, err := mddb.parseDocument(relPath, data, mtimeNS, sizeBytes, expectedID)
+	if err != nil {
+		return nil, err
 	}
 
 	return doc, nil
 }
 
-// readDocumentFile reads and parses a document from its path.
-func (mddb *MDDB[T]) readDocumentFile(expectedID string, relPath string) (*T, error) {
+// readFile reads a regular document file, returning its content, mtime, and size.
+func (mddb *MDDB[T]) readFile(relPath string) ([]byte, int64, int64, error) {
 	absPath := filepath.Join(mddb.dataDir, relPath)
 
 	info, err := mddb.fs.Stat(absPath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, ErrNotFound
+			return nil, 0, 0, ErrNotFound
 		}
 
-		return nil, fmt.Errorf("fs: %w", err)
+		return nil, 0, 0, fmt.Errorf("fs: %w", err)
 	}
 
 	if !info.Mode().IsRegular() {
-		return nil, ErrNotFound
+		return nil, 0, 0, ErrNotFound
 	}
 
 	data, err := mddb.fs.ReadFile(absPath)
 	if err != nil {
-		return nil, fmt.Errorf("fs: %w", err)
-	}
-
-	mtimeNS := info.ModTime().UnixNano()
-	sizeBytes := info.Size()
-
-	doc, err := mddb.parseDocument(relPath, data, mtimeNS, sizeBytes, expectedID)
-	if err != nil {
-		return nil, err
+		return nil, 0, 0, fmt.Errorf("fs: %w", err)
 	}
 
-	return doc, nil
-}
-
-var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
-
-func escapeLike(value string) string {
-	return likeEscaper.Replace(value)
+	return data, info.ModTime().UnixNano(), info.Size(), nil
 }
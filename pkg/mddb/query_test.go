@@ -1,8 +1,16 @@
 package mddb_test
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/calvinalkan/agent-task/pkg/mddb"
 )
@@ -162,6 +170,255 @@ func Test_GetByPrefix_Returns_Error_When_Prefix_Empty(t *testing.T) {
 	}
 }
 
+func Test_ResolvePrefix_Returns_Typed_Errors_When_Not_Unique(t *testing.T) {
+	t.Parallel()
+
+	s := openTestStore(t, t.TempDir())
+
+	defer func() { _ = s.Close() }()
+
+	doc1 := createTestDoc(t.Context(), t, s, newTestDoc(t, "Doc One"))
+	doc2 := createTestDoc(t.Context(), t, s, newTestDoc(t, "Doc Two"))
+
+	row, err := s.ResolvePrefix(t.Context(), doc1.DocShort)
+	if err != nil {
+		t.Fatalf("resolve unique: %v", err)
+	}
+
+	if row.ID != doc1.DocID {
+		t.Fatalf("id = %s, want %s", row.ID, doc1.DocID)
+	}
+
+	_, err = s.ResolvePrefix(t.Context(), "ZZZZZZ")
+	if !errors.Is(err, mddb.ErrNotFound) {
+		t.Fatalf("resolve missing: got %v, want ErrNotFound", err)
+	}
+
+	// UUIDv7 IDs created back to back share the timestamp prefix.
+	_, err = s.ResolvePrefix(t.Context(), doc1.DocID[:8])
+	if !errors.Is(err, mddb.ErrAmbiguousPrefix) {
+		t.Fatalf("resolve ambiguous: got %v, want ErrAmbiguousPrefix", err)
+	}
+
+	var ambErr *mddb.AmbiguousPrefixError
+	if !errors.As(err, &ambErr) {
+		t.Fatalf("resolve ambiguous: got %T, want *AmbiguousPrefixError", err)
+	}
+
+	if len(ambErr.IDs) != 2 || !strings.Contains(err.Error(), doc1.DocID) || !strings.Contains(err.Error(), doc2.DocID) {
+		t.Fatalf("candidates = %v (%v), want both docs", ambErr.IDs, err)
+	}
+}
+
+func Test_ResolvePrefix_Prefers_Exact_Match_When_Case_Differs_Or_Beyond_Limit(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig(t.TempDir())
+	cfg.RelPathFromID = func(id string) string { return id + ".md" }
+	cfg.ShortIDFromID = func(id string) string {
+		if id == "zzz" {
+			return "abc"
+		}
+
+		return id
+	}
+
+	s, err := mddb.Open(t.Context(), cfg)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	defer func() { _ = s.Close() }()
+
+	ids := []string{"abcd", "zzz"}
+	for i := range 60 {
+		ids = append(ids, fmt.Sprintf("abc%02d", i))
+	}
+
+	for _, id := range ids {
+		doc := newTestDoc(t, "Doc "+id)
+		doc.DocID, doc.DocShort, doc.DocPath = id, cfg.ShortIDFromID(id), id+".md"
+		createTestDoc(t.Context(), t, s, doc)
+	}
+
+	// 61 documents start with "abc"; only zzz's short_id equals it.
+	row, err := s.ResolvePrefix(t.Context(), "ABC")
+	if err != nil || row.ID != "zzz" {
+		t.Fatalf("resolve ABC: got %+v, %v; want zzz", row, err)
+	}
+
+	row, err = s.ResolvePrefix(t.Context(), "ABCD")
+	if err != nil || row.ID != "abcd" {
+		t.Fatalf("resolve ABCD: got %+v, %v; want abcd", row, err)
+	}
+}
+
+func Test_GetByPrefix_Ignores_ASCII_Case_When_Matching(t *testing.T) {
+	t.Parallel()
+
+	s := openTestStore(t, t.TempDir())
+
+	defer func() { _ = s.Close() }()
+
+	doc := createTestDoc(t.Context(), t, s, newTestDoc(t, "Test Doc"))
+
+	results, err := s.GetByPrefix(t.Context(), strings.ToLower(doc.DocShort))
+	if err != nil {
+		t.Fatalf("get by prefix: %v", err)
+	}
+
+	if len(results) != 1 || results[0].ID != doc.DocID {
+		t.Fatalf("results = %+v, want only %s", results, doc.DocID)
+	}
+}
+
+func Test_GetByPrefix_Reflects_Commits_When_Index_Already_Built(t *testing.T) {
+	t.Parallel()
+
+	s := openTestStore(t, t.TempDir())
+
+	defer func() { _ = s.Close() }()
+
+	doc := createTestDoc(t.Context(), t, s, newTestDoc(t, "Before"))
+
+	// First lookup builds the in-memory index.
+	_, err := s.GetByPrefix(t.Context(), doc.DocShort)
+	if err != nil {
+		t.Fatalf("get by prefix: %v", err)
+	}
+
+	doc.DocTitle = "After"
+	added := newTestDoc(t, "Added")
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	_, err = tx.Update(doc)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	_, err = tx.Create(added)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	err = tx.Commit(t.Context())
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	results, err := s.GetByPrefix(t.Context(), doc.DocShort)
+	if err != nil {
+		t.Fatalf("get by prefix: %v", err)
+	}
+
+	if len(results) != 1 || results[0].Title != "After" {
+		t.Fatalf("results = %+v, want title After", results)
+	}
+
+	results, err = s.GetByPrefix(t.Context(), added.DocShort)
+	if err != nil {
+		t.Fatalf("get by prefix: %v", err)
+	}
+
+	if len(results) != 1 || results[0].ID != added.DocID {
+		t.Fatalf("results = %+v, want only %s", results, added.DocID)
+	}
+
+	tx, err = s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	err = tx.Delete(doc.DocID)
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	err = tx.Commit(t.Context())
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	results, err = s.GetByPrefix(t.Context(), doc.DocShort)
+	if err != nil {
+		t.Fatalf("get by prefix: %v", err)
+	}
+
+	if len(results) != 0 {
+		t.Fatalf("results = %+v, want none after delete", results)
+	}
+}
+
+func Test_GetByPrefix_Sees_Docs_When_Committed_By_Another_Store(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s := openTestStore(t, dir)
+
+	defer func() { _ = s.Close() }()
+
+	other := openTestStore(t, dir)
+
+	defer func() { _ = other.Close() }()
+
+	first := createTestDoc(t.Context(), t, s, newTestDoc(t, "First"))
+
+	_, err := s.GetByPrefix(t.Context(), first.DocShort)
+	if err != nil {
+		t.Fatalf("get by prefix: %v", err)
+	}
+
+	second := createTestDoc(t.Context(), t, other, newTestDoc(t, "Second"))
+
+	results, err := s.GetByPrefix(t.Context(), second.DocShort)
+	if err != nil {
+		t.Fatalf("get by prefix: %v", err)
+	}
+
+	if len(results) != 1 || results[0].ID != second.DocID {
+		t.Fatalf("results = %+v, want only %s", results, second.DocID)
+	}
+}
+
+func Test_GetByPrefix_Sees_Docs_When_Reindexed(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s := openTestStore(t, dir)
+
+	defer func() { _ = s.Close() }()
+
+	first := createTestDoc(t.Context(), t, s, newTestDoc(t, "First"))
+
+	_, err := s.GetByPrefix(t.Context(), first.DocShort)
+	if err != nil {
+		t.Fatalf("get by prefix: %v", err)
+	}
+
+	added := newTestDoc(t, "Added")
+	writeTestDocFile(t, dir, added)
+
+	_, err = s.Reindex(t.Context())
+	if err != nil {
+		t.Fatalf("reindex: %v", err)
+	}
+
+	results, err := s.GetByPrefix(t.Context(), added.DocShort)
+	if err != nil {
+		t.Fatalf("get by prefix: %v", err)
+	}
+
+	if len(results) != 1 || results[0].ID != added.DocID {
+		t.Fatalf("results = %+v, want only %s", results, added.DocID)
+	}
+}
+
 func Test_Query_Returns_All_Docs_When_No_Filter(t *testing.T) {
 	t.Parallel()
 
@@ -368,3 +625,257 @@ func Test_Query_Returns_Typed_Results_When_Custom_Struct_Used(t *testing.T) {
 		t.Fatalf("priority = %d, want 5", results[0].Priority)
 	}
 }
+
+func Test_QueryScan_Returns_Scanned_Rows_When_Query_Matches(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s := openTestStore(t, dir)
+
+	defer func() { _ = s.Close() }()
+
+	for _, title := range []string{"Scan B", "Scan A", "Scan C"} {
+		doc := newTestDoc(t, title)
+		doc.DocPriority = 2
+		createTestDoc(t.Context(), t, s, doc)
+	}
+
+	titles, err := mddb.QueryScan(t.Context(), s,
+		"SELECT title FROM "+testTableName+" WHERE priority = ? ORDER BY title",
+		func(rows *sql.Rows) (string, error) {
+			var title string
+
+			return title, rows.Scan(&title)
+		}, 2)
+	if err != nil {
+		t.Fatalf("query scan: %v", err)
+	}
+
+	if strings.Join(titles, ",") != "Scan A,Scan B,Scan C" {
+		t.Fatalf("titles = %v, want [Scan A Scan B Scan C]", titles)
+	}
+
+	none, err := mddb.QueryScan(t.Context(), s,
+		"SELECT title FROM "+testTableName+" WHERE priority = ?",
+		func(rows *sql.Rows) (string, error) {
+			var title string
+
+			return title, rows.Scan(&title)
+		}, 99)
+	if err != nil || none != nil {
+		t.Fatalf("no match: got %v, %v, want nil, nil", none, err)
+	}
+}
+
+func Test_QueryScan_Returns_Scan_Error_When_Scan_Fails(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s := openTestStore(t, dir)
+
+	defer func() { _ = s.Close() }()
+
+	createTestDoc(t.Context(), t, s, newTestDoc(t, "Scan Error"))
+
+	errBoom := errors.New("boom")
+
+	_, err := mddb.QueryScan(t.Context(), s, "SELECT id FROM "+testTableName,
+		func(*sql.Rows) (string, error) { return "", errBoom })
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("got %v, want scan error", err)
+	}
+}
+
+func Test_QuerySeq_Yields_Rows_Lazily_When_Ranged(t *testing.T) {
+	t.Parallel()
+
+	s := openTestStore(t, t.TempDir())
+
+	defer func() { _ = s.Close() }()
+
+	for _, title := range []string{"Seq B", "Seq A", "Seq C"} {
+		createTestDoc(t.Context(), t, s, newTestDoc(t, title))
+	}
+
+	scanTitle := func(rows *sql.Rows) (string, error) {
+		var title string
+
+		return title, rows.Scan(&title)
+	}
+
+	var titles []string
+
+	for title, err := range mddb.QuerySeq(t.Context(), s, "SELECT title FROM "+testTableName+" ORDER BY title", scanTitle) {
+		if err != nil {
+			t.Fatalf("query seq: %v", err)
+		}
+
+		titles = append(titles, title)
+	}
+
+	if strings.Join(titles, ",") != "Seq A,Seq B,Seq C" {
+		t.Fatalf("titles = %v, want [Seq A Seq B Seq C]", titles)
+	}
+
+	// Breaking early must close the rows and release the read lock.
+	for _, err := range mddb.QuerySeq(t.Context(), s, "SELECT title FROM "+testTableName, scanTitle) {
+		if err != nil {
+			t.Fatalf("query seq: %v", err)
+		}
+
+		break
+	}
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin after break: %v", err)
+	}
+
+	_ = tx.Rollback()
+}
+
+func Test_QuerySeq_Yields_Error_Once_When_Context_Canceled(t *testing.T) {
+	t.Parallel()
+
+	s := openTestStore(t, t.TempDir())
+
+	defer func() { _ = s.Close() }()
+
+	createTestDoc(t.Context(), t, s, newTestDoc(t, "Seq One"))
+	createTestDoc(t.Context(), t, s, newTestDoc(t, "Seq Two"))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var (
+		rows int
+		errs []error
+	)
+
+	for _, err := range mddb.QuerySeq(ctx, s, "SELECT id FROM "+testTableName, func(rows *sql.Rows) (string, error) {
+		var id string
+
+		return id, rows.Scan(&id)
+	}) {
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		rows++
+
+		cancel()
+	}
+
+	if rows != 1 || len(errs) != 1 || !errors.Is(errs[0], context.Canceled) {
+		t.Fatalf("got %d rows, errs %v, want 1 row then context.Canceled", rows, errs)
+	}
+}
+
+func Test_EachID_Lists_Index_Rows_When_Files_Are_Missing(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s := openTestStore(t, dir)
+
+	defer func() { _ = s.Close() }()
+
+	doc1 := createTestDoc(t.Context(), t, s, newTestDoc(t, "Each One"))
+	doc2 := createTestDoc(t.Context(), t, s, newTestDoc(t, "Each Two"))
+
+	// EachID must not read documents, so a removed file is still listed.
+	err := os.Remove(filepath.Join(dir, doc1.DocPath))
+	if err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+
+	var got []string
+
+	err = s.EachID(t.Context(), func(id, shortID, title string) error {
+		got = append(got, id+" "+shortID+" "+title)
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("each id: %v", err)
+	}
+
+	want := []string{
+		doc1.DocID + " " + doc1.DocShort + " Each One",
+		doc2.DocID + " " + doc2.DocShort + " Each Two",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("rows = %q, want %q", got, want)
+	}
+}
+
+func Test_EachID_Stops_With_Callback_Error_When_Callback_Fails(t *testing.T) {
+	t.Parallel()
+
+	s := openTestStore(t, t.TempDir())
+
+	defer func() { _ = s.Close() }()
+
+	createTestDoc(t.Context(), t, s, newTestDoc(t, "Each One"))
+	createTestDoc(t.Context(), t, s, newTestDoc(t, "Each Two"))
+
+	errStop := errors.New("stop")
+	calls := 0
+
+	err := s.EachID(t.Context(), func(string, string, string) error {
+		calls++
+
+		return errStop
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("got %v, want callback error", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1", calls)
+	}
+}
+
+func Test_EachID_Allows_Store_Calls_When_Callback_Reads_And_Writes(t *testing.T) {
+	t.Parallel()
+
+	s := openTestStore(t, t.TempDir())
+
+	defer func() { _ = s.Close() }()
+
+	createTestDoc(t.Context(), t, s, newTestDoc(t, "Each One"))
+	createTestDoc(t.Context(), t, s, newTestDoc(t, "Each Two"))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	err := s.EachID(ctx, func(id, _, _ string) error {
+		doc, err := s.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		tx, err := s.Begin(ctx)
+		if err != nil {
+			return err
+		}
+
+		doc.DocStatus = "closed"
+
+		_, err = tx.Update(doc)
+		if err != nil {
+			_ = tx.Rollback()
+
+			return err
+		}
+
+		return tx.Commit(ctx)
+	})
+	if err != nil {
+		t.Fatalf("each id: %v", err)
+	}
+}
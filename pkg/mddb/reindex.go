@@ -7,8 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
 	"sync/atomic"
+	"time"
 
 	"github.com/calvinalkan/fileproc"
 
@@ -120,6 +120,8 @@ func (e *IndexScanError) Error() string {
 //   - Deletes missing files
 //
 // Returns counts for each category plus the resulting total row count.
+// Returns [ErrNoIndex] with [IndexNone] and [ErrNeedsReindex] if the index
+// was built with another schema (use [MDDB.Reindex]).
 func (mddb *MDDB[T]) ReindexIncremental(ctx context.Context) (IncrementalIndexResult, error) {
 	var zero IncrementalIndexResult
 
@@ -131,6 +133,10 @@ func (mddb *MDDB[T]) ReindexIncremental(ctx context.Context) (IncrementalIndexRe
 		return zero, ErrClosed
 	}
 
+	if !mddb.hasIndex() {
+		return zero, ErrNoIndex
+	}
+
 	if err := ctx.Err(); err != nil {
 		return zero, fmt.Errorf("canceled: %w", context.Cause(ctx))
 	}
@@ -142,12 +148,22 @@ func (mddb *MDDB[T]) ReindexIncremental(ctx context.Context) (IncrementalIndexRe
 
 	defer func() { _ = release() }()
 
+	// An incremental pass only writes changed rows; rows from the old schema
+	// would stay as they are.
+	err = mddb.checkSchemaCurrentLocked(ctx)
+	if err != nil {
+		return zero, err
+	}
+
 	metaIndex, err := mddb.loadIndexMeta(ctx)
 	if err != nil {
 		return zero, fmt.Errorf("load index metadata: %w", err)
 	}
 
+	start := time.Now()
 	result, err := mddb.runReindex(ctx, mddb.sql, metaIndex)
+	mddb.logReindex(ctx, true, start, result, err)
+
 	if err != nil {
 		return zero, err
 	}
@@ -161,8 +177,13 @@ func (mddb *MDDB[T]) ReindexIncremental(ctx context.Context) (IncrementalIndexRe
 // of indexed documents. Holds exclusive lock for entire duration, blocking
 // all reads ([MDDB.Get], [MDDB.GetByPrefix], [Query]) and writes ([MDDB.Begin]).
 //
-// Returns [ErrClosed] if store is closed. Returns [*IndexScanError] if files
-// fail validation; use [errors.As] to inspect Issues for details.
+// Files are read and parsed in parallel by fileproc workers; a single writer
+// goroutine batches the rows into SQLite inside one transaction, built in a
+// temp database that replaces the index only on success.
+//
+// Returns [ErrClosed] if store is closed and [ErrNoIndex] with [IndexNone].
+// Returns [*IndexScanError] if files fail validation; use [errors.As] to
+// inspect Issues for details.
 func (mddb *MDDB[T]) Reindex(ctx context.Context) (int, error) {
 	if ctx == nil {
 		return 0, errors.New("context is nil")
@@ -172,6 +193,10 @@ func (mddb *MDDB[T]) Reindex(ctx context.Context) (int, error) {
 		return 0, ErrClosed
 	}
 
+	if !mddb.hasIndex() {
+		return 0, ErrNoIndex
+	}
+
 	if err := ctx.Err(); err != nil {
 		return 0, fmt.Errorf("canceled: %w", context.Cause(ctx))
 	}
@@ -183,8 +208,11 @@ func (mddb *MDDB[T]) Reindex(ctx context.Context) (int, error) {
 
 	defer func() { _ = release() }()
 
-	mddbDir := filepath.Dir(mddb.lockPath)
-	indexPath := filepath.Join(mddbDir, "index.sqlite")
+	// The index file is replaced below; the prefix index connection would
+	// keep reading the old one.
+	_ = mddb.dropPrefixIndex()
+
+	indexPath := mddb.indexPath
 	tmpPath := indexPath + ".tmp"
 
 	// Clean up any stale temp DB from a previous crash before rebuilding.
@@ -208,7 +236,9 @@ func (mddb *MDDB[T]) Reindex(ctx context.Context) (int, error) {
 
 	defer func() { _ = mddb.fs.Remove(tmpPath) }()
 
+	start := time.Now()
 	result, rebuildErr := mddb.runReindex(ctx, tmpDB, nil)
+	mddb.logReindex(ctx, false, start, result, rebuildErr)
 
 	closeErr := tmpDB.Close()
 	if rebuildErr != nil {
@@ -228,7 +258,7 @@ func (mddb *MDDB[T]) Reindex(ctx context.Context) (int, error) {
 	// Atomically replace old index with the rebuilt temp DB.
 	if renameErr := mddb.fs.Rename(tmpPath, indexPath); renameErr != nil {
 		// Best-effort reopen old DB so the store stays usable.
-		reopen, reopenErr := openSqlite(ctx, indexPath)
+		reopen, reopenErr := openSqlite(ctx, indexPath, mddb.sqliteOpts)
 		if reopenErr == nil {
 			mddb.sql = reopen
 		}
@@ -237,7 +267,7 @@ func (mddb *MDDB[T]) Reindex(ctx context.Context) (int, error) {
 	}
 
 	// Reopen the swapped DB with safe runtime pragmas.
-	newDB, err := openSqlite(ctx, indexPath)
+	newDB, err := openSqlite(ctx, indexPath, mddb.sqliteOpts)
 	if err != nil {
 		return 0, fmt.Errorf("open index: %w", err)
 	}
@@ -0,0 +1,182 @@
+package mddb
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/calvinalkan/agent-task/pkg/mddb/frontmatter"
+)
+
+// builtinReservedKeys are the frontmatter keys mddb always manages.
+var builtinReservedKeys = [][]byte{
+	frontmatterKeyID,
+	frontmatterKeySchemaVersion,
+	frontmatterKeyTitle,
+	frontmatterKeyBodySHA256,
+	frontmatterKeyContentSHA256,
+}
+
+// validateReservedFields checks [Config.ReservedFields].
+func validateReservedFields(names []string) error {
+	seen := make(map[string]bool, len(names))
+
+	for _, name := range names {
+		var fm frontmatter.Frontmatter
+
+		err := fm.Set([]byte(name), frontmatter.DeleteValue())
+		if err != nil {
+			return fmt.Errorf("%q: %w", name, err)
+		}
+
+		for _, key := range builtinReservedKeys {
+			if name == string(key) {
+				return fmt.Errorf("%q is reserved by mddb", name)
+			}
+		}
+
+		if seen[name] {
+			return fmt.Errorf("%q listed twice", name)
+		}
+
+		seen[name] = true
+	}
+
+	return nil
+}
+
+// validateTimestamps checks the field names of [Config.Timestamps]. They may
+// also be listed in [Config.ReservedFields].
+func validateTimestamps(ts Timestamps) error {
+	var names []string
+
+	if ts.Created != "" {
+		names = append(names, ts.Created)
+	}
+
+	if ts.Updated != "" {
+		names = append(names, ts.Updated)
+	}
+
+	return validateReservedFields(names)
+}
+
+// reservedFieldValues returns a [frontmatter.Merge] overlay for the managed
+// fields of doc, being written to relPath: [Config.ReservedFields] and a
+// Created timestamp keep the values in the current file (deleted if unset)
+// unless [Config.PopulateReserved] sets them, and an Updated timestamp is set
+// to now. A Created timestamp is set to now too if the file has none yet.
+//
+// Returns an empty overlay if no fields are managed. Values borrow from the
+// file content read here.
+func (mddb *MDDB[T]) reservedFieldValues(relPath string, doc *T, now time.Time) (frontmatter.Frontmatter, error) {
+	var overlay frontmatter.Frontmatter
+
+	ts := mddb.cfg.Timestamps
+	if len(mddb.cfg.ReservedFields) == 0 && ts.Created == "" && ts.Updated == "" {
+		return overlay, nil
+	}
+
+	var current frontmatter.Frontmatter
+
+	content, err := mddb.fs.ReadFile(filepath.Join(mddb.dataDir, relPath))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return overlay, fmt.Errorf("fs: %w", err)
+	}
+
+	exists := err == nil
+
+	if exists {
+		current, _, err = frontmatter.ParseBytes(content, mddb.cfg.ParseOptions...)
+		if err != nil {
+			return overlay, fmt.Errorf("frontmatter: %w", err)
+		}
+	}
+
+	// A file written before Timestamps was configured has no Created value
+	// yet; it gets one below like a new document.
+	keepCreated := ts.Created != "" && current.Has([]byte(ts.Created))
+
+	kept := mddb.cfg.ReservedFields
+	if keepCreated {
+		kept = append(slices.Clip(kept), ts.Created)
+	}
+
+	for _, name := range kept {
+		key := []byte(name)
+
+		value, ok := current.Get(key)
+		if !ok {
+			value = *frontmatter.DeleteValue()
+		}
+
+		err = overlay.Set(key, &value)
+		if err != nil {
+			return overlay, fmt.Errorf("frontmatter: %w", err)
+		}
+	}
+
+	if mddb.cfg.PopulateReserved != nil {
+		err = mddb.populateReserved(&overlay, doc, &current)
+		if err != nil {
+			return overlay, err
+		}
+	}
+
+	stamp := frontmatter.StringValue(now.UTC().Format(time.RFC3339))
+
+	if ts.Created != "" && !keepCreated {
+		err = overlay.Set([]byte(ts.Created), stamp)
+		if err != nil {
+			return overlay, fmt.Errorf("frontmatter: %w", err)
+		}
+	}
+
+	if ts.Updated != "" {
+		err = overlay.Set([]byte(ts.Updated), stamp)
+		if err != nil {
+			return overlay, fmt.Errorf("frontmatter: %w", err)
+		}
+	}
+
+	return overlay, nil
+}
+
+// populateReserved sets the values [Config.PopulateReserved] returns for doc
+// in overlay. current is the frontmatter of the document's current file.
+func (mddb *MDDB[T]) populateReserved(overlay *frontmatter.Frontmatter, doc *T, current *frontmatter.Frontmatter) error {
+	var reserved frontmatter.Frontmatter
+
+	for _, name := range mddb.cfg.ReservedFields {
+		value, ok := current.Get([]byte(name))
+		if !ok {
+			continue
+		}
+
+		err := reserved.Set([]byte(name), &value)
+		if err != nil {
+			return fmt.Errorf("frontmatter: %w", err)
+		}
+	}
+
+	populated, err := mddb.cfg.PopulateReserved(doc, reserved)
+	if err != nil {
+		return fmt.Errorf("PopulateReserved: %w", err)
+	}
+
+	for _, entry := range populated.EntriesView() {
+		if !slices.Contains(mddb.cfg.ReservedFields, string(entry.Key)) {
+			return fmt.Errorf("PopulateReserved: %q is not in Config.ReservedFields", entry.Key)
+		}
+
+		err = overlay.Set(entry.Key, &entry.Value)
+		if err != nil {
+			return fmt.Errorf("PopulateReserved: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,295 @@
+package mddb_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/calvinalkan/agent-task/pkg/mddb"
+	"github.com/calvinalkan/agent-task/pkg/mddb/frontmatter"
+)
+
+func Test_ReservedFields_Keep_File_Values_When_Document_Sets_Them(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	// Written before "status" is reserved, so the file carries a value.
+	s := openTestStore(t, dir)
+	existing := newTestDoc(t, "Existing")
+	createTestDoc(t.Context(), t, s, existing)
+
+	err := s.Close()
+	if err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	cfg := testConfig(dir)
+	cfg.ReservedFields = []string{"status"}
+
+	s, err = mddb.Open(t.Context(), cfg)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	defer func() { _ = s.Close() }()
+
+	fresh := newTestDoc(t, "Fresh")
+	createTestDoc(t.Context(), t, s, fresh)
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	existing.DocStatus = "closed"
+	existing.DocTitle = "Existing Renamed"
+
+	_, err = tx.Update(existing)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	err = tx.Commit(t.Context())
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	got, err := s.Get(t.Context(), existing.DocID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if got.DocStatus != "open" || got.DocTitle != "Existing Renamed" {
+		t.Fatalf("got status %q title %q, want open, Existing Renamed", got.DocStatus, got.DocTitle)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, fresh.DocPath))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	if strings.Contains(string(content), "status:") {
+		t.Fatalf("new document has reserved field:\n%s", content)
+	}
+}
+
+func Test_PopulateReserved_Sets_Values_When_Documents_Are_Written(t *testing.T) {
+	t.Parallel()
+
+	errRejected := errors.New("rejected")
+
+	cfg := testConfig(t.TempDir())
+	cfg.ReservedFields = []string{"revision"}
+	cfg.PopulateReserved = func(doc *TestDoc, current frontmatter.Frontmatter) (frontmatter.Frontmatter, error) {
+		var fm frontmatter.Frontmatter
+
+		if doc.DocTitle == "Rejected" {
+			return fm, errRejected
+		}
+
+		revision, _ := current.GetInt([]byte("revision"))
+		fm.MustSet([]byte("revision"), frontmatter.IntValue(revision+1))
+
+		return fm, nil
+	}
+
+	s, err := mddb.Open(t.Context(), cfg)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	defer func() { _ = s.Close() }()
+
+	doc := newTestDoc(t, "Counted")
+	createTestDoc(t.Context(), t, s, doc)
+
+	if got := readTestFrontmatter(t, cfg.BaseDir, doc.DocPath)["revision"]; got != "1" {
+		t.Fatalf("revision after create = %q, want 1", got)
+	}
+
+	for _, title := range []string{"Counted Again", "Rejected"} {
+		tx, err := s.Begin(t.Context())
+		if err != nil {
+			t.Fatalf("begin: %v", err)
+		}
+
+		doc.DocTitle = title
+
+		_, err = tx.Update(doc)
+		if err != nil {
+			t.Fatalf("update: %v", err)
+		}
+
+		err = tx.Commit(t.Context())
+		if title == "Rejected" {
+			if !errors.Is(err, errRejected) {
+				t.Fatalf("commit rejected: got %v, want errRejected", err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("commit: %v", err)
+		}
+	}
+
+	if got := readTestFrontmatter(t, cfg.BaseDir, doc.DocPath)["revision"]; got != "2" {
+		t.Fatalf("revision after update = %q, want 2", got)
+	}
+}
+
+func Test_Timestamps_Stamp_Created_Once_And_Updated_On_Every_Write(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 28, 9, 15, 0, 0, time.FixedZone("CET", 3600))
+
+	cfg := testConfig(t.TempDir())
+	cfg.Timestamps = mddb.Timestamps{Created: "created", Updated: "updated"}
+	cfg.Clock = func() time.Time { return now }
+
+	s, err := mddb.Open(t.Context(), cfg)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	defer func() { _ = s.Close() }()
+
+	doc := newTestDoc(t, "Stamped")
+	createTestDoc(t.Context(), t, s, doc)
+
+	got := readTestFrontmatter(t, cfg.BaseDir, doc.DocPath)
+	if got["created"] != "2026-01-28T08:15:00Z" || got["updated"] != "2026-01-28T08:15:00Z" {
+		t.Fatalf("after create: %v", got)
+	}
+
+	now = now.Add(time.Hour)
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	doc.DocStatus = "closed"
+
+	_, err = tx.Update(doc)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	err = tx.Commit(t.Context())
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	got = readTestFrontmatter(t, cfg.BaseDir, doc.DocPath)
+	if got["created"] != "2026-01-28T08:15:00Z" || got["updated"] != "2026-01-28T09:15:00Z" {
+		t.Fatalf("after update: %v", got)
+	}
+}
+
+func Test_Timestamps_Stamp_Created_When_File_Predates_Timestamps(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s := openTestStore(t, dir)
+	doc := newTestDoc(t, "Old")
+	createTestDoc(t.Context(), t, s, doc)
+
+	err := s.Close()
+	if err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	cfg := testConfig(dir)
+	cfg.Timestamps = mddb.Timestamps{Created: "created"}
+	cfg.Clock = func() time.Time { return time.Date(2026, 1, 28, 9, 15, 0, 0, time.UTC) }
+
+	s, err = mddb.Open(t.Context(), cfg)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	defer func() { _ = s.Close() }()
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	_, err = tx.Update(doc)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	err = tx.Commit(t.Context())
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	if got := readTestFrontmatter(t, dir, doc.DocPath)["created"]; got != "2026-01-28T09:15:00Z" {
+		t.Fatalf("created = %q, want stamped on first write with Timestamps", got)
+	}
+}
+
+func Test_Open_Returns_Error_When_ReservedFields_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, fields := range [][]string{{"title"}, {"body_sha256"}, {"created", "created"}, {"bad key"}, {""}} {
+		cfg := testConfig(t.TempDir())
+		cfg.ReservedFields = fields
+
+		s, err := mddb.Open(t.Context(), cfg)
+		if err == nil {
+			_ = s.Close()
+
+			t.Fatalf("open with %q: want error", fields)
+		}
+
+		if !strings.Contains(err.Error(), "Config.ReservedFields") {
+			t.Fatalf("open with %q: err = %v", fields, err)
+		}
+	}
+
+	cfg := testConfig(t.TempDir())
+	cfg.Timestamps = mddb.Timestamps{Created: "stamp", Updated: "stamp"}
+
+	s, err := mddb.Open(t.Context(), cfg)
+	if err == nil {
+		_ = s.Close()
+
+		t.Fatal("open with equal timestamp fields: want error")
+	}
+}
+
+// readTestFrontmatter returns the top-level "key: value" lines of the
+// frontmatter of the file at relPath under dir.
+func readTestFrontmatter(t *testing.T, dir, relPath string) map[string]string {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join(dir, relPath))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	fields := map[string]string{}
+
+	for line := range strings.Lines(string(content)) {
+		line = strings.TrimSpace(line)
+		if line == "---" && len(fields) > 0 {
+			break
+		}
+
+		key, value, ok := strings.Cut(line, ": ")
+		if ok {
+			fields[key] = value
+		}
+	}
+
+	return fields
+}
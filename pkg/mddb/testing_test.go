@@ -138,7 +138,11 @@ const testTableName = "docs"
 type TestStore = mddb.MDDB[TestDoc]
 
 type testOpts struct {
-	lockTimeout time.Duration
+	lockTimeout  time.Duration
+	indexPath    string
+	deferReindex bool
+	noIndex      bool
+	dedupBodies  bool
 }
 
 type testOpt func(*testOpts)
@@ -147,16 +151,37 @@ func withTestLockTimeout() testOpt {
 	return func(o *testOpts) { o.lockTimeout = 10 * time.Millisecond }
 }
 
+func withTestIndexPath(path string) testOpt {
+	return func(o *testOpts) { o.indexPath = path }
+}
+
+func withTestDeferReindex() testOpt {
+	return func(o *testOpts) { o.deferReindex = true }
+}
+
+// withTestDedupBodies opens the store with [mddb.Config.DedupBodies].
+func withTestDedupBodies() testOpt {
+	return func(o *testOpts) { o.dedupBodies = true }
+}
+
+// withTestNoIndex opens the store with [mddb.IndexNone] and no SQL settings.
+func withTestNoIndex() testOpt {
+	return func(o *testOpts) { o.noIndex = true }
+}
+
 func testConfig(dir string, opts ...testOpt) mddb.Config[TestDoc] {
 	o := testOpts{}
 	for _, opt := range opts {
 		opt(&o)
 	}
 
-	return mddb.Config[TestDoc]{
+	cfg := mddb.Config[TestDoc]{
 		BaseDir:      dir,
 		DocumentFrom: documentFromTestDoc,
 		LockTimeout:  o.lockTimeout,
+		IndexPath:    o.indexPath,
+		DeferReindex: o.deferReindex,
+		DedupBodies:  o.dedupBodies,
 		SQLSchema: mddb.NewBaseSQLSchema(testTableName).
 			Text("status", true).
 			Int("priority", true).
@@ -184,6 +209,14 @@ func testConfig(dir string, opts ...testOpt) mddb.Config[TestDoc] {
 			return []any{status, priority, string(doc.Body)}
 		},
 	}
+
+	if o.noIndex {
+		cfg.IndexMode = mddb.IndexNone
+		cfg.SQLSchema = nil
+		cfg.SQLColumnValues = nil
+	}
+
+	return cfg
 }
 
 // openTestStore opens a store with TestDoc config.
@@ -297,6 +330,9 @@ type walRecord struct {
 	ID      string `json:"id"`
 	Path    string `json:"path"`
 	Content string `json:"content,omitempty"`
+	Body    string `json:"body,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Data    []byte `json:"data,omitempty"`
 }
 
 const (
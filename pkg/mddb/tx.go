@@ -8,6 +8,8 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 )
 
 // ErrCommitIncomplete indicates WAL was durable but file write or index update failed.
@@ -23,6 +25,47 @@ var ErrCommitIncomplete = errors.New("wal durable; apply incomplete")
 // already exists in the index or filesystem.
 var ErrAlreadyExists = errors.New("already exists")
 
+// ErrConflict indicates [Tx.Commit] found a document file changed since the
+// mtime passed to [Tx.PutIfUnchanged]. Nothing was written.
+var ErrConflict = errors.New("conflict: document changed")
+
+// ErrInvalidSavepoint indicates [Tx.RollbackTo] was called with a savepoint
+// that was never created by this transaction or was released by an earlier
+// rollback.
+var ErrInvalidSavepoint = errors.New("invalid savepoint")
+
+// SavepointID identifies a point in a transaction's buffered operations.
+// Returned by [Tx.Savepoint] and consumed by [Tx.RollbackTo].
+type SavepointID int
+
+// ChangeAction is the file-level effect of a buffered operation.
+type ChangeAction string
+
+// Change actions reported by [Tx.Plan].
+const (
+	ChangeCreate    ChangeAction = "create"    // file does not exist yet
+	ChangeOverwrite ChangeAction = "overwrite" // existing file gets new content
+	ChangeDelete    ChangeAction = "delete"    // existing file is removed
+)
+
+// PlannedChange is one file write or removal that [Tx.Commit] would perform.
+type PlannedChange struct {
+	ID     string
+	Path   string // relative to data directory
+	Action ChangeAction
+
+	// Content is the markdown (or attachment data) that would be written.
+	// Empty for [ChangeDelete].
+	Content string
+}
+
+// PutResult describes a document buffered by [Tx.Put].
+type PutResult struct {
+	ID      string
+	Path    string // relative to data directory, as resolved by [Config.RelPathFromID]
+	Created bool   // false if an existing document is replaced
+}
+
 // Tx buffers write operations until [Tx.Commit] persists them atomically.
 //
 // Create via [MDDB.Begin]. Holds exclusive WAL lock until Commit or Rollback.
@@ -36,6 +79,25 @@ type Tx[T Document] struct {
 	release func() error
 	ops     map[string]walOp[T] // keyed by ID, last op wins
 	closed  bool
+
+	// undo records the op each buffer write replaced, oldest first.
+	// Savepoints are positions in this log.
+	undo       []txUndo[T]
+	savepoints []txSavepoint
+	nextSP     SavepointID
+}
+
+// txUndo is the state of one ID before a buffer write.
+type txUndo[T Document] struct {
+	id   string
+	prev walOp[T]
+	had  bool // false if the ID had no buffered op
+}
+
+// txSavepoint marks the undo log length at [Tx.Savepoint] time.
+type txSavepoint struct {
+	id   SavepointID
+	mark int
 }
 
 // Begin starts a write transaction with exclusive WAL lock.
@@ -43,8 +105,9 @@ type Tx[T Document] struct {
 // Replays pending WAL before returning. Caller must call [Tx.Commit] or
 // [Tx.Rollback] to release lock.
 //
-// Returns [ErrClosed] if store is closed. Also returns lock timeout or
-// WAL replay failures.
+// Returns [ErrClosed] if store is closed and [ErrNeedsReindex] if the index
+// was built with another schema (see [Config.DeferReindex]). Also returns
+// lock timeout or WAL replay failures.
 func (mddb *MDDB[T]) Begin(ctx context.Context) (*Tx[T], error) {
 	if ctx == nil {
 		return nil, errors.New("context is nil")
@@ -59,6 +122,11 @@ func (mddb *MDDB[T]) Begin(ctx context.Context) (*Tx[T], error) {
 		return nil, fmt.Errorf("acquiring write lock: %w", err)
 	}
 
+	err = mddb.checkSchemaCurrentLocked(ctx)
+	if err != nil {
+		return nil, errors.Join(err, release())
+	}
+
 	return &Tx[T]{
 		mddb:    mddb,
 		ctx:     ctx,
@@ -72,9 +140,10 @@ func (mddb *MDDB[T]) Begin(ctx context.Context) (*Tx[T], error) {
 //
 // Returns [ErrAlreadyExists] if the document exists in the index or filesystem.
 // Validates document fields (id, title, path, short_id must be non-empty).
+// A document without an ID gets one from [Config.NewID], if set.
 // No disk I/O until commit.
 func (tx *Tx[T]) Create(doc *T) (*T, error) {
-	id, path, err := tx.validateForWrite(doc)
+	id, path, err := tx.validateForWrite(doc, true)
 	if err != nil {
 		return nil, withContext(fmt.Errorf("validating: %w", err), id, path)
 	}
@@ -110,38 +179,66 @@ func (tx *Tx[T]) Create(doc *T) (*T, error) {
 // Validates document fields (id, title, path, short_id must be non-empty).
 // No disk I/O until commit.
 func (tx *Tx[T]) Update(doc *T) (*T, error) {
-	id, path, err := tx.validateForWrite(doc)
-	if err != nil {
-		return nil, withContext(fmt.Errorf("validating: %w", err), id, path)
-	}
+	return tx.update(doc, 0)
+}
 
-	// Check index first (fast path)
-	exists, err := tx.existsInIndex(id)
+// Put buffers a document for writing on [Tx.Commit], creating it or replacing
+// the existing one.
+//
+// Returns where the document will be written and whether it is new, so
+// callers need not recompute the path. A document already buffered by
+// [Tx.Create] in this transaction stays a create.
+// Validates document fields (id, title, path, short_id must be non-empty).
+// A document without an ID gets one from [Config.NewID], if set, and is
+// always a create.
+// No disk I/O until commit.
+func (tx *Tx[T]) Put(doc *T) (PutResult, error) {
+	id, path, err := tx.validateForWrite(doc, true)
 	if err != nil {
-		return nil, withContext(fmt.Errorf("checking index: %w", err), id, path)
+		return PutResult{}, withContext(fmt.Errorf("validating: %w", err), id, path)
 	}
 
-	if !exists {
-		return nil, withContext(ErrNotFound, id, path)
-	}
+	kind := walKindUpdate
 
-	// Check filesystem (source of truth)
-	exists, err = tx.fileExists(path)
-	if err != nil {
-		return nil, withContext(fmt.Errorf("checking file: %w", err), id, path)
+	if existing, ok := tx.ops[id]; ok && existing.Kind == walKindCreate {
+		kind = walKindCreate
+	} else {
+		exists, existsErr := tx.fileExists(path)
+		if existsErr != nil {
+			return PutResult{}, withContext(fmt.Errorf("checking file: %w", existsErr), id, path)
+		}
+
+		if !exists {
+			kind = walKindCreate
+		}
 	}
 
-	if !exists {
-		return nil, withContext(ErrNotFound, id, path)
+	tx.bufferPut(id, path, doc, kind)
+
+	return PutResult{ID: id, Path: path, Created: kind == walKindCreate}, nil
+}
+
+// PutIfUnchanged buffers an update like [Tx.Update], but [Tx.Commit] fails
+// with [ErrConflict] unless the file's mtime still equals expectedMtimeNS.
+//
+// Compare-and-set for read-modify-write: pass the mtime observed when the
+// document was read (e.g. [IndexableDocument.MtimeNS]). The check runs under
+// the transaction's exclusive lock before the WAL is written, so a conflict
+// leaves the store untouched. Later ops on the same ID in this transaction
+// keep the check.
+func (tx *Tx[T]) PutIfUnchanged(doc *T, expectedMtimeNS int64) error {
+	if expectedMtimeNS == 0 {
+		return errors.New("expected mtime is zero")
 	}
 
-	tx.bufferPut(id, path, doc, walKindUpdate)
+	_, err := tx.update(doc, expectedMtimeNS)
 
-	return doc, nil
+	return err
 }
 
 // validateForWrite performs common validation for Create and Update.
-func (tx *Tx[T]) validateForWrite(doc *T) (string, string, error) {
+// With mintID, a document without an ID is assigned one from [Config.NewID].
+func (tx *Tx[T]) validateForWrite(doc *T, mintID bool) (string, string, error) {
 	if tx == nil {
 		return "", "", errors.New("tx is nil")
 	}
@@ -160,6 +257,20 @@ func (tx *Tx[T]) validateForWrite(doc *T) (string, string, error) {
 		return "", "", errors.New("type assertion to Document failed")
 	}
 
+	if mintID && d.ID() == "" && tx.mddb.cfg.NewID != nil {
+		id, err := tx.mddb.cfg.NewID()
+		if err != nil {
+			return "", "", fmt.Errorf("NewID: %w", err)
+		}
+
+		tx.mddb.cfg.SetID(doc, id)
+
+		d, ok = any(*doc).(Document)
+		if !ok || d.ID() != id {
+			return id, "", errors.New("SetID: document id not set")
+		}
+	}
+
 	id, path, err := tx.mddb.validateDocument(d)
 	if err != nil {
 		return id, "", err
@@ -173,7 +284,12 @@ func (tx *Tx[T]) validateForWrite(doc *T) (string, string, error) {
 }
 
 // existsInIndex checks if a document ID exists in the SQLite index.
+// Without an index, it reports whether the document file exists.
 func (tx *Tx[T]) existsInIndex(id string) (bool, error) {
+	if !tx.mddb.hasIndex() {
+		return tx.fileExists(tx.mddb.cfg.RelPathFromID(id))
+	}
+
 	var exists bool
 
 	query := fmt.Sprintf("SELECT 1 FROM %s WHERE id = ? LIMIT 1", tx.mddb.schema.tableName)
@@ -211,18 +327,71 @@ func (tx *Tx[T]) fileExists(path string) (bool, error) {
 	return false, fmt.Errorf("fs: %w", err)
 }
 
+// update buffers an existing document, optionally guarded by an mtime check.
+func (tx *Tx[T]) update(doc *T, expectMtimeNS int64) (*T, error) {
+	id, path, err := tx.validateForWrite(doc, false)
+	if err != nil {
+		return nil, withContext(fmt.Errorf("validating: %w", err), id, path)
+	}
+
+	// Check index first (fast path)
+	exists, err := tx.existsInIndex(id)
+	if err != nil {
+		return nil, withContext(fmt.Errorf("checking index: %w", err), id, path)
+	}
+
+	if !exists {
+		return nil, withContext(ErrNotFound, id, path)
+	}
+
+	// Check filesystem (source of truth)
+	exists, err = tx.fileExists(path)
+	if err != nil {
+		return nil, withContext(fmt.Errorf("checking file: %w", err), id, path)
+	}
+
+	if !exists {
+		return nil, withContext(ErrNotFound, id, path)
+	}
+
+	tx.setOp(walOp[T]{
+		Op:            walOpPut,
+		Kind:          walKindUpdate,
+		ID:            id,
+		Path:          path,
+		Doc:           doc,
+		ExpectMtimeNS: expectMtimeNS,
+	})
+
+	return doc, nil
+}
+
 // bufferPut adds a put operation to the transaction buffer.
 func (tx *Tx[T]) bufferPut(id, path string, doc *T, kind walKind) {
-	tx.ops[id] = walOp[T]{
+	tx.setOp(walOp[T]{
 		Op:   walOpPut,
 		Kind: kind,
 		ID:   id,
 		Path: path,
 		Doc:  doc,
+	})
+}
+
+// setOp buffers op, recording the replaced op so [Tx.RollbackTo] can restore it.
+// An mtime check from an earlier op on the same ID is carried over.
+func (tx *Tx[T]) setOp(op walOp[T]) {
+	key := op.key()
+
+	prev, had := tx.ops[key]
+	if had && op.ExpectMtimeNS == 0 {
+		op.ExpectMtimeNS = prev.ExpectMtimeNS
 	}
+
+	tx.undo = append(tx.undo, txUndo[T]{id: key, prev: prev, had: had})
+	tx.ops[key] = op
 }
 
-// Delete buffers a document for removal on [Tx.Commit].
+// Delete buffers a document and its attachments for removal on [Tx.Commit].
 //
 // Returns [ErrNotFound] if the document file does not exist.
 func (tx *Tx[T]) Delete(id string) error {
@@ -275,12 +444,15 @@ func (tx *Tx[T]) Delete(id string) error {
 		}
 	}
 
-	tx.ops[id] = walOp[T]{
+	tx.setOp(walOp[T]{
 		Op:   walOpDelete,
 		Kind: walKindDelete,
 		ID:   id,
 		Path: path,
-	}
+	})
+
+	// Deleting the document removes its attachments on disk.
+	tx.dropBufferedAttachments(id)
 
 	return nil
 }
@@ -318,8 +490,13 @@ func (tx *Tx[T]) Commit(ctx context.Context) error {
 		ops = append(ops, txOp)
 	}
 
+	err := tx.checkExpectedMtimes(ops)
+	if err != nil {
+		return err
+	}
+
 	// Snapshot markdown content before WAL write so recovery replays exact bytes.
-	err := tx.materializeOps(ops)
+	err = tx.materializeOps(ops)
 	if err != nil {
 		return fmt.Errorf("materializing ops: %w", err)
 	}
@@ -348,7 +525,124 @@ func (tx *Tx[T]) Commit(ctx context.Context) error {
 	return nil
 }
 
+// Plan reports the file changes [Tx.Commit] would make, sorted by path.
+//
+// Resolves buffered ops against the files currently on disk: a put becomes
+// [ChangeCreate] or [ChangeOverwrite], a delete becomes [ChangeDelete]. Ops
+// with no file-level effect are omitted (a put whose content matches the
+// existing file, or a delete of a file that does not exist, e.g. create
+// followed by delete). Writes nothing and does not touch the WAL; the
+// transaction stays open.
+//
+// [Config.Normalize] runs on a shallow copy of each document, so Plan leaves
+// the buffered documents as they are; only a Normalize that modifies shared
+// slices or maps in place reaches them.
+func (tx *Tx[T]) Plan(ctx context.Context) ([]PlannedChange, error) {
+	if tx == nil {
+		return nil, errors.New("tx is nil")
+	}
+
+	if tx.closed {
+		return nil, errors.New("transaction closed")
+	}
+
+	ops := make([]walOp[T], 0, len(tx.ops))
+
+	for _, txOp := range tx.ops {
+		if txOp.Doc != nil {
+			doc := *txOp.Doc
+			txOp.Doc = &doc
+		}
+
+		ops = append(ops, txOp)
+	}
+
+	err := tx.materializeOps(ops)
+	if err != nil {
+		return nil, fmt.Errorf("materializing ops: %w", err)
+	}
+
+	changes := make([]PlannedChange, 0, len(ops))
+
+	for _, op := range ops {
+		err = ctx.Err()
+		if err != nil {
+			return nil, fmt.Errorf("canceled: %w", context.Cause(ctx))
+		}
+
+		absPath := filepath.Join(tx.mddb.dataDir, op.Path)
+		change := PlannedChange{ID: op.ID, Path: op.Path}
+
+		if op.Op == walOpAttach {
+			absPath = tx.mddb.attachmentPath(op.ID, op.Name)
+			change.Path, _ = filepath.Rel(tx.mddb.dataDir, absPath)
+		}
+
+		existing, readErr := tx.mddb.fs.ReadFile(absPath)
+		if readErr != nil && !errors.Is(readErr, os.ErrNotExist) {
+			return nil, withContext(fmt.Errorf("fs: %w", readErr), op.ID, op.Path)
+		}
+
+		exists := readErr == nil
+
+		switch {
+		case op.Op == walOpAttach && !exists:
+			change.Action = ChangeCreate
+			change.Content = string(op.Data)
+		case op.Op == walOpAttach && string(existing) != string(op.Data):
+			change.Action = ChangeOverwrite
+			change.Content = string(op.Data)
+		case op.Op == walOpDelete && exists:
+			change.Action = ChangeDelete
+		case op.Op == walOpPut && !exists:
+			change.Action = ChangeCreate
+			change.Content = op.Content
+		case op.Op == walOpPut && string(existing) != op.Content:
+			change.Action = ChangeOverwrite
+			change.Content = op.Content
+		default:
+			continue
+		}
+
+		changes = append(changes, change)
+	}
+
+	slices.SortFunc(changes, func(a, b PlannedChange) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return changes, nil
+}
+
+// checkExpectedMtimes returns [ErrConflict] if a file guarded by
+// [Tx.PutIfUnchanged] was modified or removed.
+func (tx *Tx[T]) checkExpectedMtimes(ops []walOp[T]) error {
+	for _, op := range ops {
+		if op.ExpectMtimeNS == 0 {
+			continue
+		}
+
+		info, err := tx.mddb.fs.Stat(filepath.Join(tx.mddb.dataDir, op.Path))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return withContext(fmt.Errorf("%w: file removed", ErrConflict), op.ID, op.Path)
+			}
+
+			return withContext(fmt.Errorf("fs: %w", err), op.ID, op.Path)
+		}
+
+		mtimeNS := info.ModTime().UnixNano()
+		if mtimeNS != op.ExpectMtimeNS {
+			return withContext(fmt.Errorf("%w: mtime %d, expected %d", ErrConflict, mtimeNS, op.ExpectMtimeNS), op.ID, op.Path)
+		}
+	}
+
+	return nil
+}
+
 func (tx *Tx[T]) materializeOps(ops []walOp[T]) error {
+	now := tx.mddb.cfg.Clock()
+
 	for i := range ops {
 		op := &ops[i]
 		if op.Op != walOpPut {
@@ -363,12 +657,49 @@ func (tx *Tx[T]) materializeOps(ops []walOp[T]) error {
 			return fmt.Errorf("missing document (doc_id=%s)", op.ID)
 		}
 
-		content, err := tx.mddb.marshalDocument(*op.Doc)
+		if tx.mddb.cfg.Normalize != nil {
+			err := tx.normalize(op)
+			if err != nil {
+				return fmt.Errorf("%w (doc_id=%s)", err, op.ID)
+			}
+		}
+
+		reserved, err := tx.mddb.reservedFieldValues(op.Path, op.Doc, now)
+		if err != nil {
+			return fmt.Errorf("reserved fields: %w (doc_id=%s)", err, op.ID)
+		}
+
+		content, body, err := tx.mddb.marshalDocument(*op.Doc, reserved)
 		if err != nil {
 			return fmt.Errorf("marshaling document: %w (doc_id=%s)", err, op.ID)
 		}
 
 		op.Content = string(content)
+		op.Body = body
+	}
+
+	return nil
+}
+
+// normalize runs [Config.Normalize] on op's document and revalidates it.
+func (tx *Tx[T]) normalize(op *walOp[T]) error {
+	err := tx.mddb.cfg.Normalize(op.Doc)
+	if err != nil {
+		return fmt.Errorf("Normalize: %w", err)
+	}
+
+	d, ok := any(*op.Doc).(Document)
+	if !ok {
+		return errors.New("type assertion to Document failed")
+	}
+
+	id, _, err := tx.mddb.validateDocument(d)
+	if err != nil {
+		return fmt.Errorf("validating normalized document: %w", err)
+	}
+
+	if id != op.ID {
+		return fmt.Errorf("Normalize changed id to %q", id)
 	}
 
 	return nil
@@ -400,6 +731,7 @@ func (tx *Tx[T]) materializeOps(ops []walOp[T]) error {
 //     file writes may persist, causing inconsistency.
 //   - Use [Tx.Create], [Tx.Update], and [Tx.Delete] for document operations.
 //   - Direct reads are safe.
+//   - Returns nil with [IndexNone].
 func (tx *Tx[T]) DB() *sql.DB {
 	return tx.mddb.sql
 }
@@ -417,6 +749,8 @@ func (tx *Tx[T]) Rollback() error {
 
 	tx.closed = true
 	tx.ops = nil
+	tx.undo = nil
+	tx.savepoints = nil
 
 	if tx.release != nil {
 		_ = tx.release()
@@ -426,6 +760,73 @@ func (tx *Tx[T]) Rollback() error {
 	return nil
 }
 
+// Savepoint marks the current set of buffered operations.
+//
+// A later [Tx.RollbackTo] with the returned ID discards every Create, Update,
+// and Delete buffered after this call, restoring the previous op for each
+// affected ID. Savepoints are purely in-memory; nothing is written until
+// [Tx.Commit]. Savepoints nest: rolling back to one releases all savepoints
+// created after it.
+//
+// Returns -1 on a nil or closed transaction.
+func (tx *Tx[T]) Savepoint() SavepointID {
+	if tx == nil || tx.closed {
+		return -1
+	}
+
+	id := tx.nextSP
+	tx.nextSP++
+	tx.savepoints = append(tx.savepoints, txSavepoint{id: id, mark: len(tx.undo)})
+
+	return id
+}
+
+// RollbackTo discards operations buffered since savepoint id was created.
+//
+// The savepoint itself stays valid and may be rolled back to again; all
+// savepoints created after it are released. The transaction remains open.
+//
+// Returns [ErrInvalidSavepoint] if id is unknown or was released.
+func (tx *Tx[T]) RollbackTo(id SavepointID) error {
+	if tx == nil {
+		return errors.New("tx is nil")
+	}
+
+	if tx.closed {
+		return errors.New("transaction closed")
+	}
+
+	idx := -1
+
+	for i, sp := range tx.savepoints {
+		if sp.id == id {
+			idx = i
+
+			break
+		}
+	}
+
+	if idx < 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidSavepoint, id)
+	}
+
+	mark := tx.savepoints[idx].mark
+
+	for i := len(tx.undo) - 1; i >= mark; i-- {
+		u := tx.undo[i]
+		if u.had {
+			tx.ops[u.id] = u.prev
+		} else {
+			delete(tx.ops, u.id)
+		}
+	}
+
+	tx.undo = tx.undo[:mark]
+	tx.savepoints = tx.savepoints[:idx+1]
+
+	return nil
+}
+
 func (tx *Tx[T]) writeWAL(ops []walOp[T]) error {
 	content, err := encodeWalContent(ops)
 	if err != nil {
@@ -467,5 +868,7 @@ func (tx *Tx[T]) writeWAL(ops []walOp[T]) error {
 		return fmt.Errorf("fs: sync: %w", err)
 	}
 
+	tx.mddb.cfg.Logger.DebugContext(tx.ctx, "mddb: wal synced", "ops", len(ops), "bytes", len(content))
+
 	return nil
 }
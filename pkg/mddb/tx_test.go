@@ -8,6 +8,9 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/calvinalkan/agent-task/pkg/mddb"
 )
@@ -1064,3 +1067,718 @@ func Test_Tx_Update_Returns_ErrNotFound_When_Doc_Not_Exists(t *testing.T) {
 		t.Fatalf("update: got %v, want ErrNotFound", err)
 	}
 }
+
+func Test_Tx_Discards_Ops_After_Savepoint_When_RollbackTo(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s := openTestStore(t, dir)
+
+	defer func() { _ = s.Close() }()
+
+	existing := createTestDoc(t.Context(), t, s, newTestDoc(t, "Original Title"))
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	kept, err := tx.Create(newTestDoc(t, "Kept"))
+	if err != nil {
+		t.Fatalf("create kept: %v", err)
+	}
+
+	sp := tx.Savepoint()
+
+	discarded, err := tx.Create(newTestDoc(t, "Discarded"))
+	if err != nil {
+		t.Fatalf("create discarded: %v", err)
+	}
+
+	updated := *existing
+	updated.DocTitle = "Changed Title"
+
+	_, err = tx.Update(&updated)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	err = tx.Delete(kept.ID())
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	err = tx.RollbackTo(sp)
+	if err != nil {
+		t.Fatalf("rollback to: %v", err)
+	}
+
+	err = tx.Commit(t.Context())
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	_, err = s.Get(t.Context(), kept.ID())
+	if err != nil {
+		t.Fatalf("get kept: %v", err)
+	}
+
+	_, err = s.Get(t.Context(), discarded.ID())
+	if !errors.Is(err, mddb.ErrNotFound) {
+		t.Fatalf("get discarded: got %v, want ErrNotFound", err)
+	}
+
+	got, err := s.Get(t.Context(), existing.ID())
+	if err != nil {
+		t.Fatalf("get existing: %v", err)
+	}
+
+	if got.DocTitle != "Original Title" {
+		t.Fatalf("title = %q, want %q", got.DocTitle, "Original Title")
+	}
+}
+
+func Test_Tx_Restores_Outer_State_When_RollbackTo_Nested_Savepoints(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s := openTestStore(t, dir)
+
+	defer func() { _ = s.Close() }()
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	doc := newTestDoc(t, "First")
+
+	outer := tx.Savepoint()
+
+	_, err = tx.Create(doc)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	inner := tx.Savepoint()
+
+	second := *doc
+	second.DocTitle = "Second"
+
+	_, err = tx.Create(&second)
+	if err != nil {
+		t.Fatalf("create again: %v", err)
+	}
+
+	err = tx.RollbackTo(inner)
+	if err != nil {
+		t.Fatalf("rollback to inner: %v", err)
+	}
+
+	// Rolling back to a savepoint keeps it usable.
+	err = tx.RollbackTo(inner)
+	if err != nil {
+		t.Fatalf("rollback to inner twice: %v", err)
+	}
+
+	err = tx.RollbackTo(outer)
+	if err != nil {
+		t.Fatalf("rollback to outer: %v", err)
+	}
+
+	// Savepoints created after outer were released.
+	err = tx.RollbackTo(inner)
+	if !errors.Is(err, mddb.ErrInvalidSavepoint) {
+		t.Fatalf("rollback to released: got %v, want ErrInvalidSavepoint", err)
+	}
+
+	err = tx.Commit(t.Context())
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	_, err = os.Stat(filepath.Join(dir, doc.DocPath))
+	if !os.IsNotExist(err) {
+		t.Fatalf("file should not exist, err = %v", err)
+	}
+}
+
+func Test_Tx_Returns_Error_When_RollbackTo_Unknown_Or_Closed(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s := openTestStore(t, dir)
+
+	defer func() { _ = s.Close() }()
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	err = tx.RollbackTo(mddb.SavepointID(7))
+	if !errors.Is(err, mddb.ErrInvalidSavepoint) {
+		t.Fatalf("rollback to unknown: got %v, want ErrInvalidSavepoint", err)
+	}
+
+	sp := tx.Savepoint()
+
+	_ = tx.Rollback()
+
+	err = tx.RollbackTo(sp)
+	if err == nil || !strings.Contains(err.Error(), "transaction closed") {
+		t.Fatalf("rollback to after rollback: got %v, want transaction closed", err)
+	}
+
+	if got := tx.Savepoint(); got != -1 {
+		t.Fatalf("savepoint on closed tx = %d, want -1", got)
+	}
+}
+
+func Test_Tx_Plan_Reports_File_Changes_When_Ops_Buffered(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s := openTestStore(t, dir)
+
+	defer func() { _ = s.Close() }()
+
+	toUpdate := createTestDoc(t.Context(), t, s, newTestDoc(t, "To Update"))
+	toDelete := createTestDoc(t.Context(), t, s, newTestDoc(t, "To Delete"))
+	unchanged := createTestDoc(t.Context(), t, s, newTestDoc(t, "Unchanged"))
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	created, err := tx.Create(newTestDoc(t, "New"))
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	transient, err := tx.Create(newTestDoc(t, "Transient"))
+	if err != nil {
+		t.Fatalf("create transient: %v", err)
+	}
+
+	err = tx.Delete(transient.ID())
+	if err != nil {
+		t.Fatalf("delete transient: %v", err)
+	}
+
+	updated := *toUpdate
+	updated.DocTitle = "Updated"
+
+	_, err = tx.Update(&updated)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	_, err = tx.Update(unchanged)
+	if err != nil {
+		t.Fatalf("update unchanged: %v", err)
+	}
+
+	err = tx.Delete(toDelete.ID())
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	plan, err := tx.Plan(t.Context())
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+
+	got := make(map[string]mddb.ChangeAction, len(plan))
+	for _, change := range plan {
+		got[change.ID] = change.Action
+	}
+
+	want := map[string]mddb.ChangeAction{
+		created.ID():  mddb.ChangeCreate,
+		updated.ID():  mddb.ChangeOverwrite,
+		toDelete.ID(): mddb.ChangeDelete,
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("plan has %d changes, want %d: %+v", len(got), len(want), plan)
+	}
+
+	for id, action := range want {
+		if got[id] != action {
+			t.Fatalf("action for %s = %q, want %q", id, got[id], action)
+		}
+	}
+
+	for i := 1; i < len(plan); i++ {
+		if plan[i-1].Path > plan[i].Path {
+			t.Fatalf("plan not sorted by path: %+v", plan)
+		}
+	}
+
+	// Plan writes nothing; the transaction is still usable.
+	_, err = os.Stat(filepath.Join(dir, created.DocPath))
+	if !os.IsNotExist(err) {
+		t.Fatalf("file should not exist after plan, err = %v", err)
+	}
+
+	err = tx.Commit(t.Context())
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	content := readFileString(t, filepath.Join(dir, created.DocPath))
+	if !strings.Contains(content, "title: New") {
+		t.Fatalf("file missing title after commit, content:\n%s", content)
+	}
+}
+
+func Test_Tx_Plan_Returns_Error_When_Tx_Closed(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s := openTestStore(t, dir)
+
+	defer func() { _ = s.Close() }()
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	_ = tx.Rollback()
+
+	_, err = tx.Plan(t.Context())
+	if err == nil || !strings.Contains(err.Error(), "transaction closed") {
+		t.Fatalf("plan: got %v, want transaction closed", err)
+	}
+}
+
+func Test_Tx_PutIfUnchanged_Commits_When_File_Unchanged(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s := openTestStore(t, dir)
+
+	defer func() { _ = s.Close() }()
+
+	doc := createTestDoc(t.Context(), t, s, newTestDoc(t, "Before"))
+
+	raw, err := s.GetRaw(t.Context(), doc.DocID)
+	if err != nil {
+		t.Fatalf("get raw: %v", err)
+	}
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	updated := *doc
+	updated.DocTitle = "After"
+
+	err = tx.PutIfUnchanged(&updated, raw.MtimeNS)
+	if err != nil {
+		t.Fatalf("put if unchanged: %v", err)
+	}
+
+	err = tx.Commit(t.Context())
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	content := readFileString(t, filepath.Join(dir, doc.DocPath))
+	if !strings.Contains(content, "title: After") {
+		t.Fatalf("file not updated, content:\n%s", content)
+	}
+}
+
+func Test_Tx_PutIfUnchanged_Returns_ErrConflict_When_File_Changed(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s := openTestStore(t, dir)
+
+	defer func() { _ = s.Close() }()
+
+	doc := createTestDoc(t.Context(), t, s, newTestDoc(t, "Before"))
+	absPath := filepath.Join(dir, doc.DocPath)
+
+	raw, err := s.GetRaw(t.Context(), doc.DocID)
+	if err != nil {
+		t.Fatalf("get raw: %v", err)
+	}
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	updated := *doc
+	updated.DocTitle = "Lost Update"
+
+	err = tx.PutIfUnchanged(&updated, raw.MtimeNS)
+	if err != nil {
+		t.Fatalf("put if unchanged: %v", err)
+	}
+
+	// A concurrent edit the caller never saw.
+	concurrent := *doc
+	concurrent.DocTitle = "Concurrent"
+
+	err = os.WriteFile(absPath, []byte(renderDocContent(&concurrent)), 0o644)
+	if err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	err = os.Chtimes(absPath, time.Unix(0, raw.MtimeNS+int64(time.Second)), time.Unix(0, raw.MtimeNS+int64(time.Second)))
+	if err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	err = tx.Commit(t.Context())
+	if !errors.Is(err, mddb.ErrConflict) {
+		t.Fatalf("commit: got %v, want ErrConflict", err)
+	}
+
+	content := readFileString(t, absPath)
+	if !strings.Contains(content, "title: Concurrent") {
+		t.Fatalf("concurrent edit overwritten, content:\n%s", content)
+	}
+}
+
+func Test_Tx_Put_Reports_Path_And_Kind_When_Creating_Or_Replacing(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s := openTestStore(t, dir)
+
+	defer func() { _ = s.Close() }()
+
+	existing := createTestDoc(t.Context(), t, s, newTestDoc(t, "Existing"))
+	fresh := newTestDoc(t, "Fresh")
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	created, err := tx.Put(fresh)
+	if err != nil {
+		t.Fatalf("put fresh: %v", err)
+	}
+
+	if created != (mddb.PutResult{ID: fresh.DocID, Path: fresh.DocPath, Created: true}) {
+		t.Fatalf("put fresh = %+v, want created at %s", created, fresh.DocPath)
+	}
+
+	// Putting again in the same transaction is still a create.
+	again, err := tx.Put(fresh)
+	if err != nil {
+		t.Fatalf("put fresh again: %v", err)
+	}
+
+	if !again.Created {
+		t.Fatalf("put fresh again = %+v, want Created", again)
+	}
+
+	replaced := *existing
+	replaced.DocTitle = "Replaced"
+
+	updated, err := tx.Put(&replaced)
+	if err != nil {
+		t.Fatalf("put existing: %v", err)
+	}
+
+	if updated != (mddb.PutResult{ID: existing.DocID, Path: existing.DocPath, Created: false}) {
+		t.Fatalf("put existing = %+v, want update at %s", updated, existing.DocPath)
+	}
+
+	err = tx.Commit(t.Context())
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	got, err := s.Get(t.Context(), existing.DocID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if got.DocTitle != "Replaced" {
+		t.Fatalf("title = %q, want Replaced", got.DocTitle)
+	}
+
+	_, err = s.Get(t.Context(), fresh.DocID)
+	if err != nil {
+		t.Fatalf("get fresh: %v", err)
+	}
+}
+
+func Test_Tx_Commit_Applies_Normalize_When_Configured(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig(t.TempDir())
+	cfg.Normalize = func(doc *TestDoc) error {
+		doc.DocTitle = strings.TrimSpace(doc.DocTitle)
+		doc.DocStatus = strings.ToLower(doc.DocStatus)
+
+		if doc.DocPriority == 0 {
+			doc.DocPriority = 2
+		}
+
+		return nil
+	}
+
+	s, err := mddb.Open(t.Context(), cfg)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	defer func() { _ = s.Close() }()
+
+	created := newTestDoc(t, "  Padded  ")
+	created.DocStatus = "OPEN"
+	created.DocPriority = 0
+	createTestDoc(t.Context(), t, s, created)
+
+	got, err := s.Get(t.Context(), created.DocID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if got.DocTitle != "Padded" || got.DocStatus != "open" || got.DocPriority != 2 {
+		t.Fatalf("created doc = %q/%q/%d, want Padded/open/2", got.DocTitle, got.DocStatus, got.DocPriority)
+	}
+
+	got.DocStatus = "CLOSED"
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	_, err = tx.Update(got)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	err = tx.Commit(t.Context())
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	status, err := mddb.Query(t.Context(), s, func(db *sql.DB) (string, error) {
+		var status string
+
+		return status, db.QueryRow("SELECT status FROM "+testTableName+" WHERE id = ?", got.DocID).Scan(&status)
+	})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+
+	if status != "closed" {
+		t.Fatalf("indexed status = %q, want closed", status)
+	}
+}
+
+func Test_Tx_Plan_Leaves_Document_Unchanged_When_Normalize_Configured(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig(t.TempDir())
+	cfg.Normalize = func(doc *TestDoc) error {
+		doc.DocTitle = strings.TrimSpace(doc.DocTitle)
+
+		return nil
+	}
+
+	s, err := mddb.Open(t.Context(), cfg)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	defer func() { _ = s.Close() }()
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	doc := newTestDoc(t, "  Padded  ")
+
+	_, err = tx.Create(doc)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	changes, err := tx.Plan(t.Context())
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+
+	if len(changes) != 1 || !strings.Contains(changes[0].Content, "title: Padded\n") {
+		t.Fatalf("changes = %+v, want normalized title", changes)
+	}
+
+	if doc.DocTitle != "  Padded  " {
+		t.Fatalf("title after plan = %q, want unchanged", doc.DocTitle)
+	}
+}
+
+func Test_Tx_Commit_Writes_Nothing_When_Normalize_Rejects(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	errRejected := errors.New("rejected")
+
+	cfg := testConfig(dir)
+	cfg.Normalize = func(doc *TestDoc) error {
+		if doc.DocTitle == "Bad" {
+			return errRejected
+		}
+
+		return nil
+	}
+
+	s, err := mddb.Open(t.Context(), cfg)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	defer func() { _ = s.Close() }()
+
+	good := newTestDoc(t, "Good")
+	bad := newTestDoc(t, "Bad")
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	for _, doc := range []*TestDoc{good, bad} {
+		_, err = tx.Create(doc)
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+
+	err = tx.Commit(t.Context())
+	if !errors.Is(err, errRejected) {
+		t.Fatalf("commit: got %v, want Normalize error", err)
+	}
+
+	for _, doc := range []*TestDoc{good, bad} {
+		_, statErr := os.Stat(filepath.Join(dir, doc.DocPath))
+		if !errors.Is(statErr, os.ErrNotExist) {
+			t.Fatalf("%s: stat = %v, want not exist", doc.DocTitle, statErr)
+		}
+	}
+}
+
+func Test_Tx_Put_Mints_ID_When_NewID_Configured(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig(t.TempDir())
+	cfg.NewID = func() (string, error) {
+		id, err := uuid.NewV7()
+
+		return id.String(), err
+	}
+	cfg.SetID = func(doc *TestDoc, id string) { doc.DocID = id }
+
+	s, err := mddb.Open(t.Context(), cfg)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	defer func() { _ = s.Close() }()
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	doc := &TestDoc{DocTitle: "Minted", DocStatus: "open"}
+
+	result, err := tx.Put(doc)
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	if doc.DocID == "" || result.ID != doc.DocID || !result.Created {
+		t.Fatalf("put: doc id %q, result %+v; want minted id and create", doc.DocID, result)
+	}
+
+	err = tx.Commit(t.Context())
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	got, err := s.Get(t.Context(), doc.DocID)
+	if err != nil || got.DocTitle != "Minted" {
+		t.Fatalf("get: got %+v, %v", got, err)
+	}
+}
+
+func Test_Store_Returns_ErrInvalidID_When_ValidateID_Rejects(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s := openTestStore(t, dir)
+	existing := newTestDoc(t, "Existing")
+	createTestDoc(t.Context(), t, s, existing)
+
+	err := s.Close()
+	if err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	cfg := testConfig(dir)
+	cfg.ValidateID = func(id string) error {
+		uid, parseErr := uuid.Parse(id)
+		if parseErr != nil {
+			return parseErr
+		}
+
+		if uid.Version() != 4 {
+			return errors.New("not a UUIDv4")
+		}
+
+		return nil
+	}
+
+	s, err = mddb.Open(t.Context(), cfg)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	defer func() { _ = s.Close() }()
+
+	_, err = s.Get(t.Context(), existing.DocID)
+	if !errors.Is(err, mddb.ErrInvalidID) {
+		t.Fatalf("get: got %v, want ErrInvalidID", err)
+	}
+
+	tx, err := s.Begin(t.Context())
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.Create(newTestDoc(t, "Rejected"))
+	if !errors.Is(err, mddb.ErrInvalidID) {
+		t.Fatalf("create: got %v, want ErrInvalidID", err)
+	}
+}
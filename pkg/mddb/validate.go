@@ -16,12 +16,17 @@ var (
 	errEmptyTitle   = errors.New("title is empty")
 )
 
+// ErrInvalidID indicates a document ID rejected by [Config.ValidateID].
+var ErrInvalidID = errors.New("invalid id")
+
 // validateDocument checks a Document before Create/Update.
 // Returns validated ID and path (path only when valid).
 func (mddb *MDDB[T]) validateDocument(d Document) (string, string, error) {
 	id := d.ID()
-	if id == "" {
-		return "", "", errEmptyID
+
+	err := mddb.validateID(id)
+	if err != nil {
+		return id, "", err
 	}
 
 	if d.Title() == "" {
@@ -36,6 +41,24 @@ func (mddb *MDDB[T]) validateDocument(d Document) (string, string, error) {
 	return id, path, nil
 }
 
+// validateID checks that id is non-empty and accepted by [Config.ValidateID].
+func (mddb *MDDB[T]) validateID(id string) error {
+	if id == "" {
+		return errEmptyID
+	}
+
+	if mddb.cfg.ValidateID == nil {
+		return nil
+	}
+
+	err := mddb.cfg.ValidateID(id)
+	if err != nil {
+		return fmt.Errorf("%w %q: %w", ErrInvalidID, id, err)
+	}
+
+	return nil
+}
+
 // deriveAndValidate derives path and shortID from id, validating both.
 // If expectPath is non-empty, also checks that derived path matches (for parse validation).
 // Returns derived path and shortID.
@@ -112,3 +135,21 @@ func (mddb *MDDB[T]) validateRelPath(path string) error {
 
 	return nil
 }
+
+// validatePathComponent checks that s is a single, visible path component,
+// usable as a file or directory name under .mddb (attachments, locks).
+func validatePathComponent(s string) error {
+	if s == "" {
+		return errors.New("is empty")
+	}
+
+	if strings.HasPrefix(s, ".") {
+		return fmt.Errorf("%q must not start with \".\"", s)
+	}
+
+	if strings.ContainsAny(s, "/\\\x00") {
+		return fmt.Errorf("%q must not contain path separators", s)
+	}
+
+	return nil
+}
@@ -28,6 +28,7 @@ const (
 const (
 	walOpPut    = "put"
 	walOpDelete = "delete"
+	walOpAttach = "attach" // attachment Name of document ID, see [Tx.PutAttachment]
 )
 
 type walKind uint8
@@ -105,6 +106,30 @@ type walOp[T Document] struct {
 	Path    string  `json:"path"`
 	Content string  `json:"content,omitempty"`
 	Doc     *T      `json:"-"`
+
+	// Body is the body stored under .mddb/bodies when [Config.DedupBodies]
+	// moved it out of Content. Persisted so replay can write it.
+	Body string `json:"body,omitempty"`
+
+	// Name and Data are the attachment written by a walOpAttach op. Path is
+	// the owning document's path, so it validates like any other op.
+	Name string `json:"name,omitempty"`
+	Data []byte `json:"data,omitempty"`
+
+	// ExpectMtimeNS is the file mtime [Tx.Commit] requires before applying
+	// the op (see [Tx.PutIfUnchanged]). Zero means no check. Not persisted:
+	// the check happens before the WAL is written.
+	ExpectMtimeNS int64 `json:"-"`
+}
+
+// key returns the [Tx] buffer key of op: the document ID, or the ID and
+// name for attachments.
+func (op *walOp[T]) key() string {
+	if op.Op == walOpAttach {
+		return attachmentOpKey(op.ID, op.Name)
+	}
+
+	return op.ID
 }
 
 // recoverWalLocked recovers any pending WAL state.
@@ -119,6 +144,8 @@ func (mddb *MDDB[T]) recoverWalLocked(ctx context.Context) error {
 	case walEmpty:
 		return nil
 	case walUncommitted:
+		mddb.cfg.Logger.WarnContext(ctx, "mddb: discarding uncommitted wal", "bytes", len(body))
+
 		err := truncateWal(mddb.wal)
 		if err != nil {
 			return fmt.Errorf("truncating uncommitted wal: %w", err)
@@ -146,6 +173,8 @@ func (mddb *MDDB[T]) recoverWalLocked(ctx context.Context) error {
 			return fmt.Errorf("%w: truncating wal: %w", ErrWALReplay, err)
 		}
 
+		mddb.cfg.Logger.InfoContext(ctx, "mddb: wal replayed", "ops", len(ops))
+
 		return nil
 	default:
 		return fmt.Errorf("unknown wal state %d", state)
@@ -182,6 +211,15 @@ func (mddb *MDDB[T]) applyOpsToFS(ctx context.Context, ops []walOp[T]) error {
 				return fmt.Errorf("missing content (doc_id=%s doc_path=%s)", op.ID, op.Path)
 			}
 
+			// Write the body first so the document never references a
+			// missing body file.
+			if op.Body != "" {
+				err = mddb.writeBody(op.Body, rootDir, existingDirs, createdDirs, dirsToSync)
+				if err != nil {
+					return fmt.Errorf("writing body: %w (doc_id=%s doc_path=%s)", err, op.ID, op.Path)
+				}
+			}
+
 			err = ensureDir(mddb.fs, dir, rootDir, existingDirs, createdDirs, dirsToSync)
 			if err != nil {
 				return fmt.Errorf("creating dir: %w", err)
@@ -199,6 +237,8 @@ func (mddb *MDDB[T]) applyOpsToFS(ctx context.Context, ops []walOp[T]) error {
 			// because atomic.write uses tmp file + rename to atomically update (which updates the file's inode)
 			dirsToSync[dir] = struct{}{}
 
+			mddb.cfg.Logger.DebugContext(ctx, "mddb: file written", "doc_id", op.ID, "doc_path", op.Path, "bytes", len(op.Content))
+
 		case walOpDelete:
 			err := mddb.fs.Remove(absPath)
 			if err != nil && !errors.Is(err, os.ErrNotExist) {
@@ -207,6 +247,46 @@ func (mddb *MDDB[T]) applyOpsToFS(ctx context.Context, ops []walOp[T]) error {
 
 			dirsToSync[dir] = struct{}{}
 
+			// An ID that is not a valid path component can't have attachments.
+			if validatePathComponent(op.ID) == nil {
+				attachDir := mddb.attachmentDir(op.ID)
+
+				err = mddb.fs.RemoveAll(attachDir)
+				if err != nil {
+					return fmt.Errorf("fs: removing attachments: %w", err)
+				}
+
+				dirsToSync[filepath.Dir(attachDir)] = struct{}{}
+			}
+
+			mddb.cfg.Logger.DebugContext(ctx, "mddb: file removed", "doc_id", op.ID, "doc_path", op.Path)
+
+		case walOpAttach:
+			err := validateAttachmentRef(op.ID, op.Name)
+			if err != nil {
+				return fmt.Errorf("invalid attachment: %w (doc_id=%s doc_path=%s)", err, op.ID, op.Path)
+			}
+
+			attachPath := mddb.attachmentPath(op.ID, op.Name)
+			attachDir := filepath.Dir(attachPath)
+
+			err = ensureDir(mddb.fs, attachDir, rootDir, existingDirs, createdDirs, dirsToSync)
+			if err != nil {
+				return fmt.Errorf("creating dir: %w", err)
+			}
+
+			err = mddb.atomic.Write(attachPath, bytes.NewReader(op.Data), fs.AtomicWriteOptions{
+				SyncDir: false,
+				Perm:    0o644,
+			})
+			if err != nil {
+				return fmt.Errorf("fs: %w", err)
+			}
+
+			dirsToSync[attachDir] = struct{}{}
+
+			mddb.cfg.Logger.DebugContext(ctx, "mddb: attachment written", "doc_id", op.ID, "name", op.Name, "bytes", len(op.Data))
+
 		default:
 			return fmt.Errorf("unknown op %q (doc_id=%s doc_path=%s)", op.Op, op.ID, op.Path)
 		}
@@ -345,6 +425,10 @@ func ensureDir(fsys fs.FS, dir string, root string, existing map[string]struct{}
 // updateSqliteIndexFromOps applies WAL operations to SQLite in one transaction.
 // Note, this is not used for reindexing, only for recovery (reindex is super-hot path and has different logic).
 func (mddb *MDDB[T]) updateSqliteIndexFromOps(ctx context.Context, ops []walOp[T]) error {
+	if !mddb.hasIndex() {
+		return nil
+	}
+
 	tx, err := mddb.sql.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("sqlite: %w", err)
@@ -362,6 +446,7 @@ func (mddb *MDDB[T]) updateSqliteIndexFromOps(ctx context.Context, ops []walOp[T
 		deleteStmt *sql.Stmt
 		putRows    []IndexRow
 		putKinds   []walKind
+		deletedIDs []string
 		afterDocs  []*T // for AfterCreate/AfterUpdate callbacks only
 	)
 
@@ -394,6 +479,8 @@ func (mddb *MDDB[T]) updateSqliteIndexFromOps(ctx context.Context, ops []walOp[T
 				return fmt.Errorf("sqlite: %w (doc_id=%s doc_path=%s)", delErr, op.ID, op.Path)
 			}
 
+			deletedIDs = append(deletedIDs, op.ID)
+
 			if mddb.cfg.AfterDelete != nil {
 				callbackErr := mddb.cfg.AfterDelete(ctx, tx, op.ID)
 				if callbackErr != nil {
@@ -438,6 +525,8 @@ func (mddb *MDDB[T]) updateSqliteIndexFromOps(ctx context.Context, ops []walOp[T
 					afterDocs = append(afterDocs, doc)
 				}
 			}
+		case walOpAttach:
+			// Attachments are not indexed.
 		default:
 			return fmt.Errorf("unknown op %q (doc_id=%s doc_path=%s)", op.Op, op.ID, op.Path)
 		}
@@ -499,55 +588,120 @@ func (mddb *MDDB[T]) updateSqliteIndexFromOps(ctx context.Context, ops []walOp[T
 
 	committed = true
 
+	mddb.updatePrefixIndex(ctx, putRows, deletedIDs)
+
+	mddb.cfg.Logger.DebugContext(ctx, "mddb: index updated", "puts", len(putRows), "deletes", len(deletedIDs))
+
 	return nil
 }
 
 // marshalDocument renders a document to file bytes.
-func (mddb *MDDB[T]) marshalDocument(doc T) ([]byte, error) {
+//
+// With [Config.DedupBodies] and a non-empty body, the file references the body
+// via body_sha256 instead of containing it, and the body is returned separately
+// (with the trailing newline the inline form would have).
+//
+// With [Config.Checksums], the file gets a content_sha256 field holding the
+// SHA-256 of the file as rendered without that field.
+//
+// reserved is applied over the document's frontmatter; see
+// [MDDB.reservedFieldValues].
+func (mddb *MDDB[T]) marshalDocument(doc T, reserved frontmatter.Frontmatter) ([]byte, string, error) {
 	d, ok := any(doc).(Document)
 	if !ok {
-		return nil, errors.New("document type assertion failed")
+		return nil, "", errors.New("document type assertion failed")
 	}
 
 	fm := d.Frontmatter()
+	if reserved.Len() > 0 {
+		fm = frontmatter.Merge(fm, reserved, frontmatter.MergeOptions{Deletes: true})
+	}
 
 	// Inject reserved fields
 	id := d.ID()
 	if id == "" {
-		return nil, errEmptyID
+		return nil, "", errEmptyID
 	}
 
 	if err := fm.Set(frontmatterKeyID, frontmatter.StringValue(id)); err != nil {
-		return nil, fmt.Errorf("frontmatter: %w", err)
+		return nil, "", fmt.Errorf("frontmatter: %w", err)
 	}
 
 	if err := fm.Set(frontmatterKeySchemaVersion, frontmatter.IntValue(mddb.schema.fingerprint())); err != nil {
-		return nil, fmt.Errorf("frontmatter: %w", err)
+		return nil, "", fmt.Errorf("frontmatter: %w", err)
 	}
 
 	if err := fm.Set(frontmatterKeyTitle, frontmatter.StringValue(d.Title())); err != nil {
-		return nil, fmt.Errorf("frontmatter: %w", err)
+		return nil, "", fmt.Errorf("frontmatter: %w", err)
 	}
 
-	yamlStr, err := fm.MarshalYAML(frontmatter.WithKeyPriority(frontmatterKeyID, frontmatterKeySchemaVersion, frontmatterKeyTitle))
-	if err != nil {
-		return nil, fmt.Errorf("frontmatter: %w", err)
+	body := d.Body()
+	if body != "" && !strings.HasSuffix(body, "\n") {
+		body += "\n"
 	}
 
-	var b strings.Builder
-	b.WriteString(yamlStr)
+	if body != "" && mddb.cfg.DedupBodies {
+		if err := fm.Set(frontmatterKeyBodySHA256, frontmatter.StringValue(hashBody(body))); err != nil {
+			return nil, "", fmt.Errorf("frontmatter: %w", err)
+		}
+	} else if fm.Has(frontmatterKeyBodySHA256) {
+		// A stale reference (e.g. copied from a read document) would shadow
+		// the inline body.
+		var drop frontmatter.Frontmatter
+		drop.MustSet(frontmatterKeyBodySHA256, frontmatter.DeleteValue())
 
-	body := d.Body()
-	if body != "" {
-		b.WriteString("\n")
-		b.WriteString(body)
+		fm = frontmatter.Merge(fm, drop, frontmatter.MergeOptions{Deletes: true})
+	}
+
+	if fm.Has(frontmatterKeyContentSHA256) {
+		// Computed below over the rest of the file, never taken as given.
+		var drop frontmatter.Frontmatter
+		drop.MustSet(frontmatterKeyContentSHA256, frontmatter.DeleteValue())
+
+		fm = frontmatter.Merge(fm, drop, frontmatter.MergeOptions{Deletes: true})
+	}
+
+	content, err := mddb.renderFile(&fm, body)
+	if err != nil {
+		return nil, "", err
+	}
 
-		if !strings.HasSuffix(body, "\n") {
-			b.WriteString("\n")
+	if mddb.cfg.Checksums {
+		if err := fm.Set(frontmatterKeyContentSHA256, frontmatter.StringValue(hashBody(content))); err != nil {
+			return nil, "", fmt.Errorf("frontmatter: %w", err)
+		}
+
+		content, err = mddb.renderFile(&fm, body)
+		if err != nil {
+			return nil, "", err
 		}
 	}
 
-	return []byte(b.String()), nil
+	if body != "" && mddb.cfg.DedupBodies {
+		return []byte(content), body, nil
+	}
+
+	return []byte(content), "", nil
+}
+
+// renderFile renders the file content for fm and body; with
+// [Config.DedupBodies] and a non-empty body, only the frontmatter.
+func (mddb *MDDB[T]) renderFile(fm *frontmatter.Frontmatter, body string) (string, error) {
+	yamlStr, err := fm.MarshalYAML(frontmatter.WithKeyPriority(frontmatterKeyID, frontmatterKeySchemaVersion, frontmatterKeyTitle))
+	if err != nil {
+		return "", fmt.Errorf("frontmatter: %w", err)
+	}
+
+	if body == "" || mddb.cfg.DedupBodies {
+		return yamlStr, nil
+	}
+
+	var b strings.Builder
+	b.WriteString(yamlStr)
+	b.WriteString("\n")
+	b.WriteString(body)
+
+	return b.String(), nil
 }
 
 // readWalState inspects the WAL to determine its state.
@@ -714,7 +868,7 @@ func decodeWalOps[T Document](body []byte) ([]walOp[T], error) {
 			return nil, fmt.Errorf("json: %w", err)
 		}
 
-		if op.Op != walOpPut && op.Op != walOpDelete {
+		if op.Op != walOpPut && op.Op != walOpDelete && op.Op != walOpAttach {
 			return nil, fmt.Errorf("unknown op %q (doc_id=%s doc_path=%s)", op.Op, op.ID, op.Path)
 		}
 